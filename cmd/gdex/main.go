@@ -34,6 +34,7 @@ import (
 	"github.com/dexon-foundation/dexon/dex"
 	"github.com/dexon-foundation/dexon/eth"
 	"github.com/dexon-foundation/dexon/ethclient"
+	_ "github.com/dexon-foundation/dexon/indexer/sqlindexer" // built-in indexers
 	"github.com/dexon-foundation/dexon/internal/debug"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/metrics"
@@ -138,9 +139,18 @@ var (
 		utils.EWASMInterpreterFlag,
 		utils.EVMInterpreterFlag,
 		utils.IndexerEnableFlag,
+		utils.IndexerNameFlag,
 		utils.IndexerPluginFlag,
 		utils.IndexerPluginFlagsFlag,
 		utils.RecoveryNetworkRPCFlag,
+		utils.LenientHeaderVerificationFlag,
+		utils.DKGKeyPasswordFileFlag,
+		utils.ConsensusKeyFileFlag,
+		utils.ConsensusAccountFlag,
+		utils.ConsensusPasswordFileFlag,
+		utils.OperatorAccountFlag,
+		utils.OperatorPasswordFileFlag,
+		utils.OperatorSignerFlag,
 		configFileFlag,
 	}
 
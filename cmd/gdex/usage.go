@@ -244,6 +244,7 @@ var AppHelpFlagGroups = []flagGroup{
 		Name: "INDEXER",
 		Flags: []cli.Flag{
 			utils.IndexerEnableFlag,
+			utils.IndexerNameFlag,
 			utils.IndexerPluginFlag,
 			utils.IndexerPluginFlagsFlag,
 		},
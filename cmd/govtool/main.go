@@ -23,6 +23,9 @@ func init() {
 	app = utils.NewApp(gitCommit, "DEXON governance tool")
 	app.Commands = []cli.Command{
 		commandDecodeInput,
+		commandSnapshot,
+		commandDiff,
+		commandTx,
 	}
 }
 
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/dexon-foundation/dexon/cmd/utils"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/rawdb"
+	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/ethdb"
+	"github.com/dexon-foundation/dexon/params"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	roundFlag = cli.Uint64Flag{
+		Name:  "round",
+		Usage: "Round of the governance state",
+	}
+	fromRoundFlag = cli.Uint64Flag{
+		Name:  "from",
+		Usage: "Round to diff from",
+	}
+	toRoundFlag = cli.Uint64Flag{
+		Name:  "to",
+		Usage: "Round to diff to",
+	}
+)
+
+var commandSnapshot = cli.Command{
+	Name:      "snapshot",
+	Usage:     "dump governance state at a round",
+	ArgsUsage: " ",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		roundFlag,
+	},
+	Description: `dump the governance state at the beginning of a round as JSON from a
+local chain database`,
+	Action: snapshot,
+}
+
+var commandDiff = cli.Command{
+	Name:      "diff",
+	Usage:     "show governance state changes between rounds",
+	ArgsUsage: " ",
+	Flags: []cli.Flag{
+		utils.DataDirFlag,
+		fromRoundFlag,
+		toRoundFlag,
+	},
+	Description: `show the governance state changes between the beginning of two rounds
+from a local chain database`,
+	Action: diff,
+}
+
+// chainStateDB implements core.GovernanceStateDB on top of a chain database
+// without a running blockchain.
+type chainStateDB struct {
+	db    ethdb.Database
+	state state.Database
+}
+
+func newChainStateDB(db ethdb.Database) *chainStateDB {
+	return &chainStateDB{db: db, state: state.NewDatabase(db)}
+}
+
+func (c *chainStateDB) State() (*state.StateDB, error) {
+	hash := rawdb.ReadHeadBlockHash(c.db)
+	number := rawdb.ReadHeaderNumber(c.db, hash)
+	if number == nil {
+		return nil, fmt.Errorf("head block %x not exists", hash)
+	}
+	return c.StateAt(*number)
+}
+
+func (c *chainStateDB) StateAt(height uint64) (*state.StateDB, error) {
+	header := rawdb.ReadHeader(c.db, rawdb.ReadCanonicalHash(c.db, height), height)
+	if header == nil {
+		return nil, fmt.Errorf("header at %d not exists", height)
+	}
+	return state.New(header.Root, c.state)
+}
+
+type delegatorSnapshot struct {
+	Value         *big.Int `json:"value"`
+	Undelegated   *big.Int `json:"undelegated"`
+	UndelegatedAt *big.Int `json:"undelegatedAt"`
+}
+
+type nodeSnapshot struct {
+	Offset             uint64                                `json:"offset"`
+	PublicKey          hexutil.Bytes                         `json:"publicKey"`
+	Name               string                                `json:"name"`
+	Email              string                                `json:"email"`
+	Location           string                                `json:"location"`
+	Url                string                                `json:"url"`
+	Staked             *big.Int                              `json:"staked"`
+	Fined              *big.Int                              `json:"fined"`
+	Unstaked           *big.Int                              `json:"unstaked"`
+	UnstakedAt         *big.Int                              `json:"unstakedAt"`
+	Delegated          *big.Int                              `json:"delegated"`
+	Delegators         map[common.Address]*delegatorSnapshot `json:"delegators"`
+	CommissionRate     *big.Int                              `json:"commissionRate"`
+	Reward             *big.Int                              `json:"reward"`
+	LastProposedHeight *big.Int                              `json:"lastProposedHeight"`
+}
+
+type dkgSnapshot struct {
+	Round            uint64 `json:"round"`
+	ResetCount       uint64 `json:"resetCount"`
+	MasterPublicKeys uint64 `json:"masterPublicKeys"`
+	Complaints       uint64 `json:"complaints"`
+	MPKReadys        uint64 `json:"mpkReadys"`
+	Finalizeds       uint64 `json:"finalizeds"`
+	Successes        uint64 `json:"successes"`
+}
+
+type governanceSnapshot struct {
+	Round         uint64                           `json:"round"`
+	Height        uint64                           `json:"height"`
+	Owner         common.Address                   `json:"owner"`
+	CRSRound      uint64                           `json:"crsRound"`
+	CRS           common.Hash                      `json:"crs"`
+	DKG           *dkgSnapshot                     `json:"dkg"`
+	TotalSupply   *big.Int                         `json:"totalSupply"`
+	TotalStaked   *big.Int                         `json:"totalStaked"`
+	Configuration *params.DexconConfig             `json:"configuration"`
+	Nodes         map[common.Address]*nodeSnapshot `json:"nodes"`
+}
+
+// newGovernanceSnapshot collects the governance state at the beginning of
+// the round.
+func newGovernanceSnapshot(db core.GovernanceStateDB, round uint64) (*governanceSnapshot, error) {
+	gov := core.NewGovernance(db)
+
+	// Governance panics on missing state, check it beforehand.
+	if _, err := db.State(); err != nil {
+		return nil, err
+	}
+	height := gov.GetRoundHeight(round)
+	if round > 0 && height == 0 {
+		return nil, fmt.Errorf("round %d not reached", round)
+	}
+	if _, err := db.StateAt(height); err != nil {
+		return nil, fmt.Errorf("state of round %d at %d not available: %v", round, height, err)
+	}
+
+	gs := gov.GetStateAtRound(round)
+	dkgRound := gs.DKGRound()
+	s := &governanceSnapshot{
+		Round:    round,
+		Height:   height,
+		Owner:    gs.Owner(),
+		CRSRound: gs.CRSRound().Uint64(),
+		CRS:      gs.CRS(),
+		DKG: &dkgSnapshot{
+			Round:            dkgRound.Uint64(),
+			ResetCount:       gs.DKGResetCount(dkgRound).Uint64(),
+			MasterPublicKeys: gs.LenDKGMasterPublicKeys().Uint64(),
+			Complaints:       gs.LenDKGComplaints().Uint64(),
+			MPKReadys:        gs.DKGMPKReadysCount().Uint64(),
+			Finalizeds:       gs.DKGFinalizedsCount().Uint64(),
+			Successes:        gs.DKGSuccessesCount().Uint64(),
+		},
+		TotalSupply:   gs.TotalSupply(),
+		TotalStaked:   gs.TotalStaked(),
+		Configuration: gs.Configuration(),
+		Nodes:         make(map[common.Address]*nodeSnapshot),
+	}
+
+	for i, node := range gs.Nodes() {
+		delegators := make(map[common.Address]*delegatorSnapshot)
+		for _, delegator := range gs.Delegators(node.Owner) {
+			delegators[delegator.Owner] = &delegatorSnapshot{
+				Value:         delegator.Value,
+				Undelegated:   delegator.Undelegated,
+				UndelegatedAt: delegator.UndelegatedAt,
+			}
+		}
+		s.Nodes[node.Owner] = &nodeSnapshot{
+			Offset:             uint64(i),
+			PublicKey:          node.PublicKey,
+			Name:               node.Name,
+			Email:              node.Email,
+			Location:           node.Location,
+			Url:                node.Url,
+			Staked:             node.Staked,
+			Fined:              node.Fined,
+			Unstaked:           node.Unstaked,
+			UnstakedAt:         node.UnstakedAt,
+			Delegated:          gs.Delegated(node.Owner),
+			Delegators:         delegators,
+			CommissionRate:     gs.CommissionRate(node.Owner),
+			Reward:             gs.Reward(node.Owner),
+			LastProposedHeight: gs.LastProposedHeight(node.Owner),
+		}
+	}
+	return s, nil
+}
+
+// snapshotDiff is a change of a field between two snapshots. From or To is
+// nil if the field is added or removed.
+type snapshotDiff struct {
+	Path string
+	From *string
+	To   *string
+}
+
+func (d *snapshotDiff) String() string {
+	switch {
+	case d.From == nil:
+		return fmt.Sprintf("+ %s: %s", d.Path, *d.To)
+	case d.To == nil:
+		return fmt.Sprintf("- %s: %s", d.Path, *d.From)
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", d.Path, *d.From, *d.To)
+	}
+}
+
+// flattenSnapshot flattens the JSON form of the snapshot into a map from
+// field path to JSON value.
+func flattenSnapshot(s *governanceSnapshot) (map[string]string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	// Keep numbers as is, big integers do not fit in float64.
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]string)
+	var flatten func(path string, v interface{}) error
+	flatten = func(path string, v interface{}) error {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			for key, value := range v {
+				if err := flatten(path+"."+key, value); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for i, value := range v {
+				if err := flatten(fmt.Sprintf("%s[%d]", path, i), value); err != nil {
+					return err
+				}
+			}
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			fields[path[1:]] = string(data)
+		}
+		return nil
+	}
+	if err := flatten("", v); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// diffSnapshots returns the changed fields between two snapshots, ordered by
+// field path. The round and height of snapshots are not compared.
+func diffSnapshots(from, to *governanceSnapshot) ([]*snapshotDiff, error) {
+	fromFields, err := flattenSnapshot(from)
+	if err != nil {
+		return nil, err
+	}
+	toFields, err := flattenSnapshot(to)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[string]struct{})
+	for path := range fromFields {
+		paths[path] = struct{}{}
+	}
+	for path := range toFields {
+		paths[path] = struct{}{}
+	}
+	delete(paths, "round")
+	delete(paths, "height")
+
+	var diffs []*snapshotDiff
+	for path := range paths {
+		fromValue, fromExists := fromFields[path]
+		toValue, toExists := toFields[path]
+		if fromExists && toExists && fromValue == toValue {
+			continue
+		}
+		d := &snapshotDiff{Path: path}
+		if fromExists {
+			d.From = &fromValue
+		}
+		if toExists {
+			d.To = &toValue
+		}
+		diffs = append(diffs, d)
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Path < diffs[j].Path
+	})
+	return diffs, nil
+}
+
+// openChainDB opens the chain database in the data directory.
+func openChainDB(ctx *cli.Context) ethdb.Database {
+	path := filepath.Join(ctx.String(utils.DataDirFlag.Name), "gdex", "chaindata")
+	if _, err := os.Stat(path); err != nil {
+		utils.Fatalf("Could not open chain database: %v", err)
+	}
+	db, err := ethdb.NewLDBDatabase(path, 16, 16)
+	if err != nil {
+		utils.Fatalf("Could not open chain database: %v", err)
+	}
+	return db
+}
+
+func snapshot(ctx *cli.Context) error {
+	if !ctx.IsSet(roundFlag.Name) {
+		utils.Fatalf("no round specified")
+	}
+
+	db := openChainDB(ctx)
+	defer db.Close()
+
+	s, err := newGovernanceSnapshot(newChainStateDB(db), ctx.Uint64(roundFlag.Name))
+	if err != nil {
+		utils.Fatalf("%s", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		utils.Fatalf("%s", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+func diff(ctx *cli.Context) error {
+	if !ctx.IsSet(fromRoundFlag.Name) || !ctx.IsSet(toRoundFlag.Name) {
+		utils.Fatalf("both from and to round should be specified")
+	}
+
+	db := openChainDB(ctx)
+	defer db.Close()
+
+	stateDB := newChainStateDB(db)
+	from, err := newGovernanceSnapshot(stateDB, ctx.Uint64(fromRoundFlag.Name))
+	if err != nil {
+		utils.Fatalf("%s", err)
+	}
+	to, err := newGovernanceSnapshot(stateDB, ctx.Uint64(toRoundFlag.Name))
+	if err != nil {
+		utils.Fatalf("%s", err)
+	}
+
+	diffs, err := diffSnapshots(from, to)
+	if err != nil {
+		utils.Fatalf("%s", err)
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/rawdb"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/ethdb"
+)
+
+func TestGovernanceSnapshot(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	genesis := core.DefaultTestnetGenesisBlock().MustCommit(db)
+	stateDB := newChainStateDB(db)
+
+	from, err := newGovernanceSnapshot(stateDB, 0)
+	if err != nil {
+		t.Fatalf("failed to snapshot round 0: %v", err)
+	}
+	if len(from.Nodes) == 0 {
+		t.Fatalf("no node in snapshot")
+	}
+	if from.Configuration.RoundLength == 0 {
+		t.Errorf("configuration not in snapshot")
+	}
+	if _, err := newGovernanceSnapshot(stateDB, 1); err == nil {
+		t.Fatalf("snapshot of unreached round succeeded")
+	}
+
+	// Begin round 1 at height 1 with more stake on one of the nodes.
+	statedb, err := stateDB.State()
+	if err != nil {
+		t.Fatalf("failed to get head state: %v", err)
+	}
+	gs := &vm.GovernanceState{StateDB: statedb}
+	gs.PushRoundHeight(big.NewInt(1))
+	node := gs.Node(big.NewInt(0))
+	node.Staked = new(big.Int).Add(node.Staked, big.NewInt(1))
+	gs.UpdateNode(big.NewInt(0), node)
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("failed to commit trie: %v", err)
+	}
+	header := &types.Header{
+		ParentHash: genesis.Hash(),
+		Number:     big.NewInt(1),
+		Root:       root,
+		Round:      1,
+	}
+	rawdb.WriteHeader(db, header)
+	rawdb.WriteCanonicalHash(db, header.Hash(), 1)
+	rawdb.WriteHeadBlockHash(db, header.Hash())
+
+	to, err := newGovernanceSnapshot(stateDB, 1)
+	if err != nil {
+		t.Fatalf("failed to snapshot round 1: %v", err)
+	}
+	if to.Height != 1 {
+		t.Errorf("round height mismatch: have %d, want %d", to.Height, 1)
+	}
+
+	diffs, err := diffSnapshots(from, to)
+	if err != nil {
+		t.Fatalf("failed to diff snapshots: %v", err)
+	}
+	if len(diffs) != 1 {
+		t.Fatalf("diff count mismatch: have %v, want 1", diffs)
+	}
+	path := "nodes." + hexutil.Encode(node.Owner.Bytes()) + ".staked"
+	if diffs[0].Path != path {
+		t.Errorf("diff path mismatch: have %s, want %s", diffs[0].Path, path)
+	}
+	staked := new(big.Int).Sub(node.Staked, big.NewInt(1))
+	if *diffs[0].From != staked.String() || *diffs[0].To != node.Staked.String() {
+		t.Errorf("diff value mismatch: have %s", diffs[0])
+	}
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/dexon-foundation/dexon/accounts/keystore"
+	"github.com/dexon-foundation/dexon/cmd/utils"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/common/math"
+	"github.com/dexon-foundation/dexon/console"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/dex"
+	"github.com/dexon-foundation/dexon/params"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	nonceFlag = cli.Uint64Flag{
+		Name:  "nonce",
+		Usage: "Nonce of the transaction",
+	}
+	gasPriceFlag = cli.StringFlag{
+		Name:  "gasprice",
+		Usage: "Gas price of the transaction in wei",
+		Value: "1000000000",
+	}
+	gasFlag = cli.Uint64Flag{
+		Name:  "gas",
+		Usage: "Gas limit of the transaction",
+		Value: 1000000,
+	}
+	chainIDFlag = cli.StringFlag{
+		Name:  "chainid",
+		Usage: "Chain ID the transaction is signed for",
+		Value: params.MainnetChainConfig.ChainID.String(),
+	}
+	keyFileFlag = cli.StringFlag{
+		Name:  "keyfile",
+		Usage: "Encrypted key file to sign the transaction with",
+	}
+	passwordFlag = cli.StringFlag{
+		Name:  "password",
+		Usage: "File containing the password of the key file",
+	}
+	signerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer (clef) endpoint to sign the transaction with",
+	}
+	fromFlag = cli.StringFlag{
+		Name:  "from",
+		Usage: "Address of the account signing with the external signer",
+	}
+	outputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "File to write the raw transaction to (default = stdout)",
+	}
+	valueFlag = cli.StringFlag{
+		Name:  "value",
+		Usage: "Amount of DEX in wei sent with the transaction",
+		Value: "0",
+	}
+	amountFlag = cli.StringFlag{
+		Name:  "amount",
+		Usage: "Amount of DEX in wei",
+	}
+	publicKeyFlag = cli.StringFlag{
+		Name:  "publickey",
+		Usage: "Uncompressed secp256k1 public key of the node in hex",
+	}
+	nodeNameFlag = cli.StringFlag{
+		Name:  "name",
+		Usage: "Name of the node",
+	}
+	emailFlag = cli.StringFlag{
+		Name:  "email",
+		Usage: "Contact email of the node",
+	}
+	locationFlag = cli.StringFlag{
+		Name:  "location",
+		Usage: "Location of the node",
+	}
+	urlFlag = cli.StringFlag{
+		Name:  "url",
+		Usage: "Website of the node",
+	}
+	nodeFlag = cli.StringFlag{
+		Name:  "node",
+		Usage: "Owner address of the node",
+	}
+	newOwnerFlag = cli.StringFlag{
+		Name:  "newowner",
+		Usage: "Address of the new owner of the node",
+	}
+)
+
+var txFlags = []cli.Flag{
+	nonceFlag,
+	gasPriceFlag,
+	gasFlag,
+	chainIDFlag,
+	keyFileFlag,
+	passwordFlag,
+	signerFlag,
+	fromFlag,
+	outputFlag,
+}
+
+// txMethod describes how a governance method is built from flags.
+type txMethod struct {
+	name    string
+	usage   string
+	payable bool
+	flags   []cli.Flag
+	args    func(ctx *cli.Context) ([]interface{}, error)
+}
+
+var txMethods = []txMethod{
+	{
+		name:    "register",
+		usage:   "register a node with an initial stake",
+		payable: true,
+		flags:   []cli.Flag{publicKeyFlag, nodeNameFlag, emailFlag, locationFlag, urlFlag},
+		args: func(ctx *cli.Context) ([]interface{}, error) {
+			pk, _, err := parseNodePublicKey(ctx.String(publicKeyFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{pk, ctx.String(nodeNameFlag.Name), ctx.String(emailFlag.Name),
+				ctx.String(locationFlag.Name), ctx.String(urlFlag.Name)}, nil
+		},
+	},
+	{
+		name:    "stake",
+		usage:   "add stake to the node owned by the sender",
+		payable: true,
+	},
+	{
+		name:  "unstake",
+		usage: "unstake from the node owned by the sender",
+		flags: []cli.Flag{amountFlag},
+		args: func(ctx *cli.Context) ([]interface{}, error) {
+			amount, err := parseAmount(ctx.String(amountFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{amount}, nil
+		},
+	},
+	{
+		name:  "withdraw",
+		usage: "withdraw the unstaked amount after the lockup period",
+	},
+	{
+		name:    "payFine",
+		usage:   "pay the fine of a node",
+		payable: true,
+		flags:   []cli.Flag{nodeFlag},
+		args: func(ctx *cli.Context) ([]interface{}, error) {
+			node, err := parseAddress(ctx.String(nodeFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{node}, nil
+		},
+	},
+	{
+		name:  "transferNodeOwnership",
+		usage: "transfer the node owned by the sender to a new owner",
+		flags: []cli.Flag{newOwnerFlag},
+		args: func(ctx *cli.Context) ([]interface{}, error) {
+			owner, err := parseAddress(ctx.String(newOwnerFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{owner}, nil
+		},
+	},
+	{
+		name:  "replaceNodePublicKey",
+		usage: "replace the public key of the node owned by the sender",
+		flags: []cli.Flag{publicKeyFlag},
+		args: func(ctx *cli.Context) ([]interface{}, error) {
+			pk, _, err := parseNodePublicKey(ctx.String(publicKeyFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{pk}, nil
+		},
+	},
+	{
+		name:  "setP2PPublicKey",
+		usage: "set the p2p public key of the node owned by the sender",
+		flags: []cli.Flag{publicKeyFlag},
+		args: func(ctx *cli.Context) ([]interface{}, error) {
+			pk, _, err := parseNodePublicKey(ctx.String(publicKeyFlag.Name))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{pk}, nil
+		},
+	},
+}
+
+var commandTx = cli.Command{
+	Name:      "tx",
+	Usage:     "build and sign governance transactions offline",
+	ArgsUsage: " ",
+	Description: `build a governance transaction from flags, sign it with an encrypted key
+file or an external signer and print the raw transaction for submission from
+another machine`,
+	Subcommands: txCommands(),
+}
+
+func txCommands() []cli.Command {
+	commands := make([]cli.Command, 0, len(txMethods))
+	for _, method := range txMethods {
+		method := method
+		flags := append([]cli.Flag{}, txFlags...)
+		if method.payable {
+			flags = append(flags, valueFlag)
+		}
+		flags = append(flags, method.flags...)
+		commands = append(commands, cli.Command{
+			Name:      method.name,
+			Usage:     method.usage,
+			ArgsUsage: " ",
+			Flags:     flags,
+			Action: func(ctx *cli.Context) error {
+				return buildTx(ctx, &method)
+			},
+		})
+	}
+	return commands
+}
+
+// parseNodePublicKey checks the public key is in the format the governance
+// contract derives the node key address from, and returns the key with the
+// node key address.
+func parseNodePublicKey(s string) ([]byte, common.Address, error) {
+	if s == "" {
+		return nil, common.Address{}, fmt.Errorf("no public key specified")
+	}
+	pk, err := hexutil.Decode(withHexPrefix(s))
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("invalid public key: %v", err)
+	}
+	if len(pk) != 65 {
+		return nil, common.Address{}, fmt.Errorf(
+			"invalid public key length %d, want 65 bytes uncompressed", len(pk))
+	}
+	key, err := crypto.UnmarshalPubkey(pk)
+	if err != nil {
+		return nil, common.Address{}, fmt.Errorf("invalid public key: %v", err)
+	}
+	return pk, crypto.PubkeyToAddress(*key), nil
+}
+
+func parseAddress(s string) (common.Address, error) {
+	if !common.IsHexAddress(s) {
+		return common.Address{}, fmt.Errorf("invalid address %q", s)
+	}
+	return common.HexToAddress(s), nil
+}
+
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := math.ParseBig256(s)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+func withHexPrefix(s string) string {
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return "0x" + s
+	}
+	return s
+}
+
+// newGovernanceTx builds the unsigned transaction calling the governance
+// method with the arguments.
+func newGovernanceTx(method string, args []interface{}, nonce, gas uint64,
+	gasPrice, value *big.Int) (*types.Transaction, error) {
+	input, err := vm.GovernanceABI.ABI.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTransaction(nonce, vm.GovernanceContractAddress, value, gas, gasPrice, input), nil
+}
+
+func buildTx(ctx *cli.Context, method *txMethod) error {
+	if !ctx.IsSet(nonceFlag.Name) {
+		utils.Fatalf("Nonce must be specified to build transactions offline")
+	}
+	gasPrice, err := parseAmount(ctx.String(gasPriceFlag.Name))
+	if err != nil {
+		utils.Fatalf("Invalid gas price: %v", err)
+	}
+	chainID, err := parseAmount(ctx.String(chainIDFlag.Name))
+	if err != nil {
+		utils.Fatalf("Invalid chain ID: %v", err)
+	}
+	value := new(big.Int)
+	if method.payable {
+		if value, err = parseAmount(ctx.String(valueFlag.Name)); err != nil {
+			utils.Fatalf("Invalid value: %v", err)
+		}
+	}
+	var args []interface{}
+	if method.args != nil {
+		if args, err = method.args(ctx); err != nil {
+			utils.Fatalf("Invalid arguments of %s: %v", method.name, err)
+		}
+	}
+	if method.name == "register" || method.name == "replaceNodePublicKey" {
+		_, addr, _ := parseNodePublicKey(ctx.String(publicKeyFlag.Name))
+		fmt.Fprintf(os.Stderr, "Node key address: %s\n", addr.Hex())
+	}
+
+	tx, err := newGovernanceTx(method.name, args, ctx.Uint64(nonceFlag.Name),
+		ctx.Uint64(gasFlag.Name), gasPrice, value)
+	if err != nil {
+		utils.Fatalf("Failed to build transaction: %v", err)
+	}
+
+	var signed *types.Transaction
+	switch {
+	case ctx.String(keyFileFlag.Name) != "" && ctx.String(signerFlag.Name) != "":
+		utils.Fatalf("Only one of --%s and --%s can be specified", keyFileFlag.Name, signerFlag.Name)
+	case ctx.String(keyFileFlag.Name) != "":
+		signed, err = signWithKeyFile(ctx, tx, chainID)
+	case ctx.String(signerFlag.Name) != "":
+		signed, err = signWithSigner(ctx, tx, chainID)
+	default:
+		utils.Fatalf("Either --%s or --%s must be specified", keyFileFlag.Name, signerFlag.Name)
+	}
+	if err != nil {
+		utils.Fatalf("Failed to sign transaction: %v", err)
+	}
+
+	from, err := types.Sender(types.NewEIP155Signer(chainID), signed)
+	if err != nil {
+		utils.Fatalf("Invalid signature: %v", err)
+	}
+	raw, err := encodeTx(signed)
+	if err != nil {
+		utils.Fatalf("Failed to encode transaction: %v", err)
+	}
+	fmt.Fprintf(os.Stderr, "Method:  %s\nFrom:    %s\nNonce:   %d\nValue:   %s\nHash:    %s\n",
+		method.name, from.Hex(), signed.Nonce(), signed.Value(), signed.Hash().Hex())
+
+	if path := ctx.String(outputFlag.Name); path != "" {
+		if err := ioutil.WriteFile(path, []byte(raw+"\n"), 0644); err != nil {
+			utils.Fatalf("Failed to write transaction: %v", err)
+		}
+		return nil
+	}
+	fmt.Println(raw)
+	return nil
+}
+
+func encodeTx(tx *types.Transaction) (string, error) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
+// signWithKeyFile signs the transaction with an encrypted key file.
+func signWithKeyFile(ctx *cli.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	keyJSON, err := ioutil.ReadFile(ctx.String(keyFileFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	var password string
+	if path := ctx.String(passwordFlag.Name); path != "" {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		password = strings.TrimRight(string(content), "\r\n")
+	} else {
+		if password, err = console.Stdin.PromptPassword("Passphrase: "); err != nil {
+			return nil, err
+		}
+	}
+	key, err := keystore.DecryptKey(keyJSON, password)
+	if err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), key.PrivateKey)
+}
+
+// signWithSigner signs the transaction with an external signer, checking the
+// signer signs for the expected chain.
+func signWithSigner(ctx *cli.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	from, err := common.NewMixedcaseAddressFromString(ctx.String(fromFlag.Name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s address: %v", fromFlag.Name, err)
+	}
+	client, err := rpc.Dial(ctx.String(signerFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	return dex.NewExternalTxSigner(client, from.Address()).SignTx(tx, chainID)
+}
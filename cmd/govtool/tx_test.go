@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/rlp"
+)
+
+func TestParseNodePublicKey(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := crypto.FromECDSAPub(&key.PublicKey)
+
+	for _, s := range []string{hexutil.Encode(pk), hexutil.Encode(pk)[2:]} {
+		have, addr, err := parseNodePublicKey(s)
+		if err != nil {
+			t.Fatalf("failed to parse public key %s: %v", s, err)
+		}
+		if !bytes.Equal(have, pk) {
+			t.Errorf("public key mismatch: have %x, want %x", have, pk)
+		}
+		if want := crypto.PubkeyToAddress(key.PublicKey); addr != want {
+			t.Errorf("node key address mismatch: have %x, want %x", addr, want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"0xzz",
+		hexutil.Encode(crypto.CompressPubkey(&key.PublicKey)),
+		hexutil.Encode(append([]byte{0x05}, pk[1:]...)),
+	}
+	for _, s := range invalid {
+		if _, _, err := parseNodePublicKey(s); err == nil {
+			t.Errorf("invalid public key %q accepted", s)
+		}
+	}
+}
+
+func TestNewGovernanceTx(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	pk := crypto.FromECDSAPub(&key.PublicKey)
+	value := big.NewInt(1e18)
+
+	tx, err := newGovernanceTx("register", []interface{}{pk, "name", "email", "location", "url"},
+		3, 100000, big.NewInt(1e9), value)
+	if err != nil {
+		t.Fatalf("failed to build transaction: %v", err)
+	}
+	if *tx.To() != vm.GovernanceContractAddress || tx.Nonce() != 3 || tx.Value().Cmp(value) != 0 {
+		t.Errorf("transaction mismatch: %v", tx)
+	}
+	method, exists := vm.GovernanceABI.Sig2Method[string(tx.Data()[:4])]
+	if !exists || method.Name != "register" {
+		t.Fatalf("method mismatch: have %v", method.Name)
+	}
+	args := struct {
+		PublicKey []byte
+		Name      string
+		Email     string
+		Location  string
+		Url       string
+	}{}
+	if err := method.Inputs.Unpack(&args, tx.Data()[4:]); err != nil {
+		t.Fatalf("failed to unpack input: %v", err)
+	}
+	if !bytes.Equal(args.PublicKey, pk) || args.Name != "name" || args.Url != "url" {
+		t.Errorf("arguments mismatch: %+v", args)
+	}
+
+	if _, err := newGovernanceTx("unstake", nil, 0, 0, new(big.Int), new(big.Int)); err == nil {
+		t.Errorf("built transaction with missing arguments")
+	}
+
+	// The raw transaction decodes to the signed one.
+	signer := types.NewEIP155Signer(big.NewInt(237))
+	signed, err := types.SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign transaction: %v", err)
+	}
+	raw, err := encodeTx(signed)
+	if err != nil {
+		t.Fatalf("failed to encode transaction: %v", err)
+	}
+	decoded := new(types.Transaction)
+	if err := rlp.DecodeBytes(hexutil.MustDecode(raw), decoded); err != nil {
+		t.Fatalf("failed to decode transaction: %v", err)
+	}
+	if decoded.Hash() != signed.Hash() {
+		t.Errorf("hash mismatch: have %x, want %x", decoded.Hash(), signed.Hash())
+	}
+	if from, err := types.Sender(signer, decoded); err != nil || from != crypto.PubkeyToAddress(key.PublicKey) {
+		t.Errorf("sender mismatch: have %x, err %v", from, err)
+	}
+}
@@ -654,6 +654,11 @@ var (
 		Name:  "indexer",
 		Usage: "Enable indexer",
 	}
+	IndexerNameFlag = cli.StringFlag{
+		Name:  "indexer.name",
+		Usage: "Built-in indexer name (sql)",
+		Value: "",
+	}
 	IndexerPluginFlag = cli.StringFlag{
 		Name:  "indexer.plugin",
 		Usage: "External indexer plugin shared object path",
@@ -671,6 +676,39 @@ var (
 		Usage: "RPC URL of the recovery network",
 		Value: "https://mainnet.infura.io",
 	}
+	LenientHeaderVerificationFlag = cli.BoolFlag{
+		Name:  "dexcon.lenient-verification",
+		Usage: "Only log headers with invalid randomness, round or reward instead of rejecting them (compatibility)",
+	}
+	DKGKeyPasswordFileFlag = cli.StringFlag{
+		Name:  "dexcon.dkgkey-password",
+		Usage: "Password file to encrypt the DKG private keys in the database with",
+		Value: "",
+	}
+	ConsensusKeyFileFlag = cli.StringFlag{
+		Name:  "dexcon.consensuskey",
+		Usage: "Private key file signing consensus messages (default = p2p node key)",
+	}
+	ConsensusAccountFlag = cli.StringFlag{
+		Name:  "dexcon.consensus-account",
+		Usage: "Keystore account signing consensus messages instead of a private key file",
+	}
+	ConsensusPasswordFileFlag = cli.StringFlag{
+		Name:  "dexcon.consensus-password",
+		Usage: "Password file to unlock the consensus account with",
+	}
+	OperatorAccountFlag = cli.StringFlag{
+		Name:  "dexcon.operator-account",
+		Usage: "Account sending governance transactions of the node (default = consensus account)",
+	}
+	OperatorPasswordFileFlag = cli.StringFlag{
+		Name:  "dexcon.operator-password",
+		Usage: "Password file to unlock the operator account in the keystore with",
+	}
+	OperatorSignerFlag = cli.StringFlag{
+		Name:  "dexcon.operator-signer",
+		Usage: "External signer (clef) endpoint signing for the operator account instead of the keystore",
+	}
 )
 
 // MakeDataDir retrieves the currently requested data directory, terminating
@@ -693,6 +731,60 @@ func MakeDataDir(ctx *cli.Context) string {
 	return ""
 }
 
+// readPasswordFile reads the password in the first line of the file.
+func readPasswordFile(path, name string) string {
+	text, err := ioutil.ReadFile(path)
+	if err != nil {
+		Fatalf("Failed to read %s password file: %v", name, err)
+	}
+	password := strings.TrimRight(strings.SplitN(string(text), "\n", 2)[0], "\r")
+	if password == "" {
+		Fatalf("Empty %s password in %s", name, path)
+	}
+	return password
+}
+
+// setDexSigners configures the consensus signing key or account and the
+// operator account sending governance transactions.
+func setDexSigners(ctx *cli.Context, cfg *dex.Config) {
+	checkExclusive(ctx, ConsensusKeyFileFlag, ConsensusAccountFlag)
+
+	if file := ctx.GlobalString(ConsensusKeyFileFlag.Name); file != "" {
+		key, err := crypto.LoadECDSA(file)
+		if err != nil {
+			Fatalf("Option %q: %v", ConsensusKeyFileFlag.Name, err)
+		}
+		cfg.PrivateKey = key
+	}
+	if account := ctx.GlobalString(ConsensusAccountFlag.Name); account != "" {
+		if !common.IsHexAddress(account) {
+			Fatalf("Option %q: invalid address %q", ConsensusAccountFlag.Name, account)
+		}
+		cfg.ConsensusAccount = common.HexToAddress(account)
+		path := ctx.GlobalString(ConsensusPasswordFileFlag.Name)
+		if path == "" {
+			Fatalf("Option %q requires %q", ConsensusAccountFlag.Name, ConsensusPasswordFileFlag.Name)
+		}
+		cfg.ConsensusPassphrase = readPasswordFile(path, "consensus account")
+	}
+
+	if account := ctx.GlobalString(OperatorAccountFlag.Name); account != "" {
+		if !common.IsHexAddress(account) {
+			Fatalf("Option %q: invalid address %q", OperatorAccountFlag.Name, account)
+		}
+		cfg.OperatorAccount = common.HexToAddress(account)
+		cfg.OperatorSigner = ctx.GlobalString(OperatorSignerFlag.Name)
+		if cfg.OperatorSigner == "" {
+			path := ctx.GlobalString(OperatorPasswordFileFlag.Name)
+			if path == "" {
+				Fatalf("Option %q requires %q or %q", OperatorAccountFlag.Name,
+					OperatorPasswordFileFlag.Name, OperatorSignerFlag.Name)
+			}
+			cfg.OperatorPassphrase = readPasswordFile(path, "operator account")
+		}
+	}
+}
+
 // setNodeKey creates a node key from set command line flags, either loading it
 // from a file or as a specified hex value. If neither flags were provided, this
 // method returns nil and an emphemeral key is to be generated.
@@ -1229,6 +1321,13 @@ func SetDexConfig(ctx *cli.Context, stack *node.Node, cfg *dex.Config) {
 	if ctx.GlobalIsSet(BlockProposerEnabledFlag.Name) {
 		cfg.BlockProposerEnabled = ctx.GlobalBool(BlockProposerEnabledFlag.Name)
 	}
+	if ctx.GlobalIsSet(LenientHeaderVerificationFlag.Name) {
+		cfg.LenientHeaderVerification = ctx.GlobalBool(LenientHeaderVerificationFlag.Name)
+	}
+	if path := ctx.GlobalString(DKGKeyPasswordFileFlag.Name); path != "" {
+		cfg.DKGKeyPassphrase = readPasswordFile(path, "DKG key")
+	}
+	setDexSigners(ctx, cfg)
 
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheDatabaseFlag.Name) {
 		cfg.DatabaseCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
@@ -1336,6 +1435,7 @@ func setIndexerConfig(ctx *cli.Context, cfg *dex.Config) {
 		return
 	}
 
+	cfg.Indexer.Name = ctx.GlobalString(IndexerNameFlag.Name)
 	cfg.Indexer.Plugin = ctx.GlobalString(IndexerPluginFlag.Name)
 	cfg.Indexer.PluginFlags = ctx.GlobalString(IndexerPluginFlagsFlag.Name)
 	// copy required dex configs
@@ -1360,7 +1460,11 @@ func RegisterDexService(stack *node.Node, cfg *dex.Config) {
 		//})
 	} else {
 		err = stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-			cfg.PrivateKey = ctx.ServerConfig.PrivateKey
+			// The p2p node key signs consensus messages unless a separate
+			// consensus key or account is configured.
+			if cfg.PrivateKey == nil && cfg.ConsensusAccount == (common.Address{}) {
+				cfg.PrivateKey = ctx.ServerConfig.PrivateKey
+			}
 			fullNode, err := dex.New(ctx, cfg)
 			//if fullNode != nil && cfg.LightServ > 0 {
 			//	ls, _ := les.NewLesServer(fullNode, cfg)
@@ -1394,14 +1498,22 @@ func RegisterShhService(stack *node.Node, cfg *whisper.Config) {
 // the given node.
 func RegisterEthStatsService(stack *node.Node, url string) {
 	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-		// Retrieve both eth and les services
+		// Retrieve the eth, dex and les services
 		var ethServ *eth.Ethereum
 		ctx.Service(&ethServ)
 
+		var dexServ *dex.Dexon
+		ctx.Service(&dexServ)
+
 		var lesServ *les.LightEthereum
 		ctx.Service(&lesServ)
 
-		return ethstats.New(url, ethServ, lesServ)
+		// Don't wrap a missing DEXON service into a non-nil interface.
+		var dexBackend ethstats.DexonBackend
+		if dexServ != nil {
+			dexBackend = dexServ
+		}
+		return ethstats.New(url, ethServ, dexBackend, lesServ)
 	}); err != nil {
 		Fatalf("Failed to register the Ethereum Stats service: %v", err)
 	}
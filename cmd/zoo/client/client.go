@@ -109,6 +109,11 @@ func (c *Client) PrepareTx(ctx *TransferContext) *types.Transaction {
 	return tx
 }
 
+// SignTx signs the transaction with the key for the network of the client.
+func (c *Client) SignTx(tx *types.Transaction, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(c.networkID), key)
+}
+
 func (c *Client) Transfer(ctx *TransferContext) {
 	tx := c.PrepareTx(ctx)
 
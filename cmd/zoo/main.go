@@ -15,6 +15,8 @@ var batch = flag.Bool("batch", false, "monkeys will send transaction in batch")
 var sleep = flag.Int("sleep", 500, "time in millisecond that monkeys sleep between each transaction")
 var feeder = flag.Bool("feeder", false, "make this monkey a feeder")
 var timeout = flag.Int("timeout", 0, "execution time limit after start")
+var scenario = flag.String("scenario", "", "scenario file of the transactions to send")
+var report = flag.String("report", "", "file to write the scenario report in JSON")
 var shutdown = flag.String("shutdown", "", "shutdown the previously opened zoo")
 
 func main() {
@@ -39,6 +41,8 @@ func main() {
 		Batch:    *batch,
 		Sleep:    *sleep,
 		Timeout:  *timeout,
+		Scenario: *scenario,
+		Report:   *report,
 	})
 	monkey.Exec()
 }
@@ -44,6 +44,8 @@ type MonkeyConfig struct {
 	Batch    bool
 	Sleep    int
 	Timeout  int
+	Scenario string
+	Report   string
 }
 
 func Init(cfg *MonkeyConfig) {
@@ -188,10 +190,25 @@ func Exec() (*Monkey, uint64) {
 		panic(err)
 	}
 
+	var scenario *Scenario
+	if config.Scenario != "" {
+		if scenario, err = LoadScenario(config.Scenario); err != nil {
+			panic(err)
+		}
+	}
+
 	m := New(config.Endpoint, privKey, config.N, time.Duration(config.Timeout))
 	m.Distribute()
 	var finalNonce uint64
-	if config.Gambler {
+	if scenario != nil {
+		report := m.RunScenario(scenario)
+		report.Print(os.Stdout)
+		if config.Report != "" {
+			if err := report.WriteFile(config.Report); err != nil {
+				panic(err)
+			}
+		}
+	} else if config.Gambler {
 		finalNonce = m.Gamble()
 	} else if config.Feeder {
 		finalNonce = m.Feed()
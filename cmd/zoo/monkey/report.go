@@ -0,0 +1,257 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package monkey
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dexon-foundation/dexon/common"
+)
+
+// Latency is the distribution of the confirmation latencies.
+type Latency struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// ActionReport is the result of the transactions of an action type.
+type ActionReport struct {
+	Submitted   int            `json:"submitted"`
+	Confirmed   int            `json:"confirmed"`
+	Unconfirmed int            `json:"unconfirmed"`
+	Errors      map[string]int `json:"errors"`
+	Latency     Latency        `json:"latency"`
+}
+
+// Report is the result of a scenario run.
+type Report struct {
+	Scenario     string                   `json:"scenario"`
+	Duration     time.Duration            `json:"duration"`
+	Submitted    int                      `json:"submitted"`
+	Confirmed    int                      `json:"confirmed"`
+	Unconfirmed  int                      `json:"unconfirmed"`
+	SubmittedTPS float64                  `json:"submittedTPS"`
+	ConfirmedTPS float64                  `json:"confirmedTPS"`
+	Latency      Latency                  `json:"latency"`
+	Errors       map[string]int           `json:"errors"`
+	Actions      map[string]*ActionReport `json:"actions"`
+}
+
+// Print writes the report in a human readable form.
+func (r *Report) Print(w io.Writer) {
+	fmt.Fprintf(w, "Scenario %s, sent for %v\n", r.Scenario, r.Duration)
+	fmt.Fprintf(w, "  Submitted:    %d (%.2f tx/s)\n", r.Submitted, r.SubmittedTPS)
+	fmt.Fprintf(w, "  Confirmed:    %d (%.2f tx/s)\n", r.Confirmed, r.ConfirmedTPS)
+	fmt.Fprintf(w, "  Unconfirmed:  %d\n", r.Unconfirmed)
+	fmt.Fprintf(w, "  Latency:      p50 %v, p90 %v, p99 %v, max %v\n",
+		r.Latency.P50, r.Latency.P90, r.Latency.P99, r.Latency.Max)
+
+	types := make([]string, 0, len(r.Actions))
+	for typ := range r.Actions {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	for _, typ := range types {
+		action := r.Actions[typ]
+		fmt.Fprintf(w, "  Action %s: submitted %d, confirmed %d, unconfirmed %d, p50 %v, p99 %v\n",
+			typ, action.Submitted, action.Confirmed, action.Unconfirmed,
+			action.Latency.P50, action.Latency.P99)
+	}
+
+	if len(r.Errors) == 0 {
+		return
+	}
+	msgs := make([]string, 0, len(r.Errors))
+	for msg := range r.Errors {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool {
+		if r.Errors[msgs[i]] != r.Errors[msgs[j]] {
+			return r.Errors[msgs[i]] > r.Errors[msgs[j]]
+		}
+		return msgs[i] < msgs[j]
+	})
+	fmt.Fprintln(w, "  Errors:")
+	for _, msg := range msgs {
+		fmt.Fprintf(w, "    %6d  %s\n", r.Errors[msg], msg)
+	}
+}
+
+// WriteFile writes the report to the file in JSON.
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
+
+// sentTx is a submitted transaction waiting for confirmation.
+type sentTx struct {
+	action string
+	sentAt time.Time
+}
+
+// stats collects the results of the transactions sent in a scenario.
+type stats struct {
+	name      string
+	begin     time.Time
+	end       time.Time
+	pending   map[common.Hash]sentTx
+	submits   map[string]int
+	errors    map[string]map[string]int
+	latencies map[string][]time.Duration
+	lock      sync.Mutex
+}
+
+func newStats(name string) *stats {
+	return &stats{
+		name:      name,
+		pending:   make(map[common.Hash]sentTx),
+		submits:   make(map[string]int),
+		errors:    make(map[string]map[string]int),
+		latencies: make(map[string][]time.Duration),
+	}
+}
+
+func (s *stats) start(t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.begin = t
+}
+
+func (s *stats) stop(t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.end = t
+}
+
+func (s *stats) submitted(action string, hash common.Hash, t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.submits[action]++
+	s.pending[hash] = sentTx{action: action, sentAt: t}
+}
+
+func (s *stats) failed(action string, err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.errors[action] == nil {
+		s.errors[action] = make(map[string]int)
+	}
+	s.errors[action][err.Error()]++
+}
+
+// confirmed marks the transaction confirmed at the given time. Transactions
+// not sent in the scenario are ignored.
+func (s *stats) confirmed(hash common.Hash, t time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tx, exists := s.pending[hash]
+	if !exists {
+		return
+	}
+	delete(s.pending, hash)
+	s.latencies[tx.action] = append(s.latencies[tx.action], t.Sub(tx.sentAt))
+}
+
+func (s *stats) pendingCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.pending)
+}
+
+// report summarizes the collected results.
+func (s *stats) report() *Report {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	r := &Report{
+		Scenario: s.name,
+		Duration: s.end.Sub(s.begin),
+		Errors:   make(map[string]int),
+		Actions:  make(map[string]*ActionReport),
+	}
+	action := func(typ string) *ActionReport {
+		if r.Actions[typ] == nil {
+			r.Actions[typ] = &ActionReport{Errors: make(map[string]int)}
+		}
+		return r.Actions[typ]
+	}
+
+	var all []time.Duration
+	for typ, count := range s.submits {
+		action(typ).Submitted = count
+		r.Submitted += count
+	}
+	for typ, latencies := range s.latencies {
+		action(typ).Confirmed = len(latencies)
+		action(typ).Latency = percentiles(latencies)
+		r.Confirmed += len(latencies)
+		all = append(all, latencies...)
+	}
+	for _, tx := range s.pending {
+		action(tx.action).Unconfirmed++
+		r.Unconfirmed++
+	}
+	for typ, errors := range s.errors {
+		for msg, count := range errors {
+			action(typ).Errors[msg] += count
+			r.Errors[msg] += count
+		}
+	}
+	r.Latency = percentiles(all)
+
+	if seconds := r.Duration.Seconds(); seconds > 0 {
+		r.SubmittedTPS = float64(r.Submitted) / seconds
+		r.ConfirmedTPS = float64(r.Confirmed) / seconds
+	}
+	return r
+}
+
+// percentiles computes the latency distribution with the nearest-rank method.
+func percentiles(latencies []time.Duration) Latency {
+	if len(latencies) == 0 {
+		return Latency{}
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	rank := func(p int) time.Duration {
+		idx := (len(sorted)*p+99)/100 - 1
+		if idx < 0 {
+			idx = 0
+		}
+		return sorted[idx]
+	}
+	return Latency{
+		P50: rank(50),
+		P90: rank(90),
+		P99: rank(99),
+		Max: sorted[len(sorted)-1],
+	}
+}
@@ -0,0 +1,312 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package monkey
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/crypto"
+)
+
+// Scenario action types.
+const (
+	ActionTransfer = "transfer"
+	ActionERC20    = "erc20"
+	ActionDeploy   = "deploy"
+)
+
+// Default gas limits of the scenario actions.
+var defaultActionGas = map[string]uint64{
+	ActionTransfer: 21000,
+	ActionERC20:    42000,
+	ActionDeploy:   1500000,
+}
+
+// Action is a kind of transaction sent at a target rate.
+type Action struct {
+	// Type is one of transfer, erc20 and deploy.
+	Type string `json:"type"`
+
+	// Rate is the target number of transactions sent per second.
+	Rate float64 `json:"rate"`
+
+	// Gas is the gas limit of the transactions, defaults by type if zero.
+	Gas uint64 `json:"gas"`
+}
+
+// Scenario describes a mix of actions sent by the monkeys.
+type Scenario struct {
+	Name string `json:"name"`
+
+	// Duration is the time in seconds the actions are sent.
+	Duration int `json:"duration"`
+
+	// Drain is the time in seconds to wait for confirmations after sending.
+	Drain int `json:"drain"`
+
+	Actions []Action `json:"actions"`
+}
+
+// LoadScenario reads a scenario from a JSON file.
+func LoadScenario(path string) (*Scenario, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var s Scenario
+	if err := json.NewDecoder(file).Decode(&s); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %v", path, err)
+	}
+	if err := s.validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %v", path, err)
+	}
+	return &s, nil
+}
+
+func (s *Scenario) validate() error {
+	if s.Duration <= 0 {
+		return fmt.Errorf("non-positive duration %d", s.Duration)
+	}
+	if s.Drain < 0 {
+		return fmt.Errorf("negative drain %d", s.Drain)
+	}
+	if len(s.Actions) == 0 {
+		return fmt.Errorf("no action")
+	}
+	for i := range s.Actions {
+		action := &s.Actions[i]
+		gas, exists := defaultActionGas[action.Type]
+		if !exists {
+			return fmt.Errorf("unknown action type %q", action.Type)
+		}
+		if action.Rate <= 0 {
+			return fmt.Errorf("non-positive rate %v of %s", action.Rate, action.Type)
+		}
+		if action.Gas == 0 {
+			action.Gas = gas
+		}
+	}
+	return nil
+}
+
+// scenarioAccount is a monkey account sending scenario transactions. The
+// lock keeps the nonces of the account in order.
+type scenarioAccount struct {
+	key   *ecdsa.PrivateKey
+	nonce uint64
+	lock  sync.Mutex
+}
+
+// scenarioRunner sends the actions of a scenario and tracks the
+// confirmations of the sent transactions.
+type scenarioRunner struct {
+	monkey   *Monkey
+	scenario *Scenario
+	contract common.Address
+	gasPrice *big.Int
+	accounts []*scenarioAccount
+	next     int
+	stats    *stats
+	lock     sync.Mutex
+}
+
+// RunScenario sends the actions of the scenario at their target rates from
+// the monkey accounts and reports the results.
+func (m *Monkey) RunScenario(s *Scenario) *Report {
+	runner := &scenarioRunner{
+		monkey:   m,
+		scenario: s,
+		stats:    newStats(s.Name),
+	}
+	for _, action := range s.Actions {
+		if action.Type == ActionERC20 {
+			fmt.Println("Deploying contract ...")
+			runner.contract = m.Deploy(m.source, bananaContract, nil, new(big.Int), math.MaxUint64)
+			fmt.Println("  Contract deployed: ", runner.contract.String())
+			m.DistributeBanana(runner.contract)
+			break
+		}
+	}
+
+	gasPrice, err := m.SuggestGasPrice(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	runner.gasPrice = gasPrice
+	for _, key := range m.keys {
+		nonce, err := m.PendingNonceAt(context.Background(), crypto.PubkeyToAddress(key.PublicKey))
+		if err != nil {
+			panic(err)
+		}
+		runner.accounts = append(runner.accounts, &scenarioAccount{key: key, nonce: nonce})
+	}
+	head, err := m.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("Running scenario %s for %d seconds ...\n", s.Name, s.Duration)
+	quitSend := make(chan struct{})
+	quitConfirm := make(chan struct{})
+	var sendWg, confirmWg sync.WaitGroup
+
+	confirmWg.Add(1)
+	go func() {
+		defer confirmWg.Done()
+		runner.confirm(head.Number.Uint64()+1, quitConfirm)
+	}()
+
+	runner.stats.start(time.Now())
+	for _, action := range s.Actions {
+		sendWg.Add(1)
+		go func(action Action) {
+			defer sendWg.Done()
+			runner.send(action, quitSend)
+		}(action)
+	}
+
+	time.Sleep(time.Duration(s.Duration) * time.Second)
+	close(quitSend)
+	sendWg.Wait()
+	runner.stats.stop(time.Now())
+
+	fmt.Printf("Waiting %d seconds for confirmations ...\n", s.Drain)
+	deadline := time.After(time.Duration(s.Drain) * time.Second)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+drain:
+	for runner.stats.pendingCount() > 0 {
+		select {
+		case <-deadline:
+			break drain
+		case <-ticker.C:
+		}
+	}
+	close(quitConfirm)
+	confirmWg.Wait()
+
+	return runner.stats.report()
+}
+
+// send sends transactions of the action at its target rate until quit.
+func (r *scenarioRunner) send(action Action, quit <-chan struct{}) {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / action.Rate))
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.sendOne(action)
+		}()
+	}
+}
+
+// nextAccount picks the accounts in turn.
+func (r *scenarioRunner) nextAccount() *scenarioAccount {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	account := r.accounts[r.next]
+	r.next = (r.next + 1) % len(r.accounts)
+	return account
+}
+
+// sendOne signs and sends a transaction of the action.
+func (r *scenarioRunner) sendOne(action Action) {
+	account := r.nextAccount()
+	account.lock.Lock()
+	defer account.lock.Unlock()
+
+	var tx *types.Transaction
+	switch action.Type {
+	case ActionTransfer:
+		to := crypto.PubkeyToAddress(r.accounts[rand.Intn(len(r.accounts))].key.PublicKey)
+		tx = types.NewTransaction(account.nonce, to, big.NewInt(rand.Int63n(1e13)+1),
+			action.Gas, r.gasPrice, nil)
+	case ActionERC20:
+		to := crypto.PubkeyToAddress(r.accounts[rand.Intn(len(r.accounts))].key.PublicKey)
+		input, err := bananaABI.Pack("transfer", to, big.NewInt(rand.Int63n(100)+1))
+		if err != nil {
+			panic(err)
+		}
+		tx = types.NewTransaction(account.nonce, r.contract, new(big.Int), action.Gas, r.gasPrice, input)
+	case ActionDeploy:
+		tx = types.NewContractCreation(account.nonce, new(big.Int), action.Gas, r.gasPrice,
+			common.Hex2Bytes(bananaContract))
+	}
+	tx, err := r.monkey.SignTx(tx, account.key)
+	if err != nil {
+		panic(err)
+	}
+
+	sentAt := time.Now()
+	if err := r.monkey.SendTransaction(context.Background(), tx); err != nil {
+		r.stats.failed(action.Type, err)
+		return
+	}
+	account.nonce++
+	r.stats.submitted(action.Type, tx.Hash(), sentAt)
+}
+
+// confirm follows the chain from the given block and confirms the sent
+// transactions included in the blocks until quit.
+func (r *scenarioRunner) confirm(number uint64, quit <-chan struct{}) {
+	ticker := time.NewTicker(200 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+		for {
+			block, err := r.monkey.BlockByNumber(context.Background(), new(big.Int).SetUint64(number))
+			if err != nil {
+				// Block not available yet.
+				break
+			}
+			now := time.Now()
+			for _, tx := range block.Transactions() {
+				r.stats.confirmed(tx.Hash(), now)
+			}
+			number++
+		}
+	}
+}
@@ -0,0 +1,226 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dexcon
+
+import (
+	"errors"
+	"fmt"
+
+	dexCore "github.com/dexon-foundation/dexon-consensus/core"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/consensus"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+var (
+	errUnknownBlock     = errors.New("unknown block")
+	errUnknownRound     = errors.New("height of round unknown")
+	errGovStateNotReady = errors.New("governance state not ready")
+)
+
+// maxRewardScheduleRounds is the maximum number of rounds in a reward
+// schedule query.
+const maxRewardScheduleRounds = 256
+
+// API is a user facing RPC API to inspect the consensus provenance of blocks.
+type API struct {
+	chain  consensus.ChainReader
+	dexcon *Dexcon
+}
+
+// RoundReward is the reward of each block proposed in a round.
+type RoundReward struct {
+	Round  hexutil.Uint64 `json:"round"`
+	Reward *hexutil.Big   `json:"reward"`
+}
+
+// Position is the position of a consensus block.
+type Position struct {
+	Round  hexutil.Uint64 `json:"round"`
+	Height hexutil.Uint64 `json:"height"`
+}
+
+// Witness is the witness of a consensus block.
+type Witness struct {
+	Height hexutil.Uint64 `json:"height"`
+	Data   hexutil.Bytes  `json:"data"`
+}
+
+// DexconMeta is the decoded consensus block carried by a header.
+type DexconMeta struct {
+	ProposerID   common.Hash    `json:"proposerID"`
+	ParentHash   common.Hash    `json:"parentHash"`
+	Hash         common.Hash    `json:"hash"`
+	Position     Position       `json:"position"`
+	Timestamp    hexutil.Uint64 `json:"timestamp"`
+	PayloadHash  common.Hash    `json:"payloadHash"`
+	Witness      Witness        `json:"witness"`
+	Randomness   hexutil.Bytes  `json:"randomness"`
+	Signature    hexutil.Bytes  `json:"signature"`
+	CRSSignature hexutil.Bytes  `json:"crsSignature"`
+}
+
+// header retrieves the header of the given block number, the current one if
+// none is requested.
+func (api *API) header(number *rpc.BlockNumber) (*types.Header, error) {
+	var header *types.Header
+	if number == nil || *number == rpc.LatestBlockNumber || *number == rpc.PendingBlockNumber {
+		header = api.chain.CurrentHeader()
+	} else if round, ok := number.RoundStart(); ok {
+		// Resolve the first block of the round, unknown if the round has not
+		// begun or the chain does not track round heights.
+		if reader, ok := api.chain.(RoundHeightReader); ok {
+			if height, ok := reader.GetRoundHeight(round); ok {
+				header = api.chain.GetHeaderByNumber(height)
+			}
+		}
+	} else {
+		header = api.chain.GetHeaderByNumber(uint64(number.Int64()))
+	}
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return header, nil
+}
+
+// checkConfigRound makes sure the governance state the configuration of the
+// round is read from is available.
+func (api *API) checkConfigRound(round uint64) error {
+	if api.dexcon.govStateFetcer == nil {
+		return errGovStateNotReady
+	}
+	if round < dexCore.ConfigRoundShift {
+		return nil
+	}
+	reader, ok := api.chain.(RoundHeightReader)
+	if !ok {
+		return errGovStateNotReady
+	}
+	if _, ok := reader.GetRoundHeight(round - dexCore.ConfigRoundShift); !ok {
+		return errGovStateNotReady
+	}
+	return nil
+}
+
+// GetBlockReward returns the reward of each block proposed in the round.
+func (api *API) GetBlockReward(round hexutil.Uint64) (*hexutil.Big, error) {
+	if err := api.checkConfigRound(uint64(round)); err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(api.dexcon.calculateBlockReward(uint64(round))), nil
+}
+
+// GetRewardSchedule returns the block rewards of the rounds in [from, to].
+// The schedule stops at the first round whose configuration is not ready.
+func (api *API) GetRewardSchedule(from, to hexutil.Uint64) ([]*RoundReward, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid round range [%d, %d]", from, to)
+	}
+	if to-from >= maxRewardScheduleRounds {
+		return nil, fmt.Errorf("too many rounds, maximum %d", maxRewardScheduleRounds)
+	}
+	schedule := make([]*RoundReward, 0, to-from+1)
+	for round := uint64(from); round <= uint64(to); round++ {
+		if err := api.checkConfigRound(round); err != nil {
+			if len(schedule) == 0 {
+				return nil, err
+			}
+			break
+		}
+		schedule = append(schedule, &RoundReward{
+			Round:  hexutil.Uint64(round),
+			Reward: (*hexutil.Big)(api.dexcon.calculateBlockReward(round)),
+		})
+	}
+	return schedule, nil
+}
+
+// InExtendedRound tells whether the block is in an extended round, in which
+// no block reward is given.
+func (api *API) InExtendedRound(number *rpc.BlockNumber) (bool, error) {
+	header, err := api.header(number)
+	if err != nil {
+		return false, err
+	}
+	if err := api.checkConfigRound(header.Round); err != nil {
+		return false, err
+	}
+	roundHeight, ok := api.dexcon.roundHeight(api.chain, header, nil)
+	if !ok {
+		return false, errUnknownRound
+	}
+	return api.dexcon.inExtendedRound(header, roundHeight), nil
+}
+
+// GetDexconMeta returns the decoded consensus block of the block.
+func (api *API) GetDexconMeta(number *rpc.BlockNumber) (*DexconMeta, error) {
+	header, err := api.header(number)
+	if err != nil {
+		return nil, err
+	}
+	return decodeDexconMeta(header)
+}
+
+// GetDexconMetaByHash returns the decoded consensus block of the block.
+func (api *API) GetDexconMetaByHash(hash common.Hash) (*DexconMeta, error) {
+	header := api.chain.GetHeaderByHash(hash)
+	if header == nil {
+		return nil, errUnknownBlock
+	}
+	return decodeDexconMeta(header)
+}
+
+// GetRandomness returns the randomness of the block.
+func (api *API) GetRandomness(number *rpc.BlockNumber) (hexutil.Bytes, error) {
+	header, err := api.header(number)
+	if err != nil {
+		return nil, err
+	}
+	return header.Randomness, nil
+}
+
+func decodeDexconMeta(header *types.Header) (*DexconMeta, error) {
+	var coreBlock coreTypes.Block
+	if err := rlp.DecodeBytes(header.DexconMeta, &coreBlock); err != nil {
+		return nil, fmt.Errorf("decode dexcon meta fail, number=%d, err=%v",
+			header.Number.Uint64(), err)
+	}
+	return &DexconMeta{
+		ProposerID: common.Hash(coreBlock.ProposerID.Hash),
+		ParentHash: common.Hash(coreBlock.ParentHash),
+		Hash:       common.Hash(coreBlock.Hash),
+		Position: Position{
+			Round:  hexutil.Uint64(coreBlock.Position.Round),
+			Height: hexutil.Uint64(coreBlock.Position.Height),
+		},
+		Timestamp:   hexutil.Uint64(coreBlock.Timestamp.UnixNano() / 1000000),
+		PayloadHash: common.Hash(coreBlock.PayloadHash),
+		Witness: Witness{
+			Height: hexutil.Uint64(coreBlock.Witness.Height),
+			Data:   coreBlock.Witness.Data,
+		},
+		Randomness:   coreBlock.Randomness,
+		Signature:    coreBlock.Signature.Signature,
+		CRSSignature: coreBlock.CRSSignature.Signature,
+	}, nil
+}
@@ -17,17 +17,22 @@
 package dexcon
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"runtime"
 
 	dexCore "github.com/dexon-foundation/dexon-consensus/core"
+	coreCrypto "github.com/dexon-foundation/dexon-consensus/core/crypto"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
 	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/consensus"
 	"github.com/dexon-foundation/dexon/core/state"
 	"github.com/dexon-foundation/dexon/core/types"
 	"github.com/dexon-foundation/dexon/core/vm"
 	"github.com/dexon-foundation/dexon/log"
+	"github.com/dexon-foundation/dexon/rlp"
 	"github.com/dexon-foundation/dexon/rpc"
 )
 
@@ -36,9 +41,21 @@ type GovernanceStateFetcher interface {
 	DKGSetNodeKeyAddresses(round uint64) (map[common.Address]struct{}, error)
 }
 
+// RoundHeightReader is implemented by chains which keep track of the height
+// at which each round begins.
+type RoundHeightReader interface {
+	GetRoundHeight(round uint64) (uint64, bool)
+}
+
 // Dexcon is a delegated proof-of-stake consensus engine.
 type Dexcon struct {
 	govStateFetcer GovernanceStateFetcher
+	verifierCache  *dexCore.TSigVerifierCache
+
+	// lenient only logs headers violating the randomness, round or reward
+	// rules instead of rejecting them. It exists for compatibility with nodes
+	// which synced before these rules were enforced.
+	lenient bool
 }
 
 // New creates a Clique proof-of-authority consensus engine with the initial
@@ -54,6 +71,18 @@ func (d *Dexcon) SetGovStateFetcher(fetcher GovernanceStateFetcher) {
 	d.govStateFetcer = fetcher
 }
 
+// SetTSigVerifierCache sets the cache used to verify the threshold signature
+// carried in block randomness.
+func (d *Dexcon) SetTSigVerifierCache(cache *dexCore.TSigVerifierCache) {
+	d.verifierCache = cache
+}
+
+// SetLenientVerification switches header verification between rejecting and
+// only logging invalid randomness, round and reward.
+func (d *Dexcon) SetLenientVerification(lenient bool) {
+	d.lenient = lenient
+}
+
 // Author implements consensus.Engine, returning the Ethereum address recovered
 // from the signature in the header's extra-data section.
 func (d *Dexcon) Author(header *types.Header) (common.Address, error) {
@@ -62,28 +91,119 @@ func (d *Dexcon) Author(header *types.Header) (common.Address, error) {
 
 // VerifyHeader checks whether a header conforms to the consensus rules.
 func (d *Dexcon) VerifyHeader(chain consensus.ChainReader, header *types.Header, seal bool) error {
-	return nil
+	// Short circuit if the header is known, or it's parent not
+	number := header.Number.Uint64()
+	if chain.GetHeader(header.Hash(), number) != nil {
+		return nil
+	}
+	parent := chain.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
+	return d.verifyHeader(chain, header, []*types.Header{parent}, seal)
 }
 
 // VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers. The
 // method returns a quit channel to abort the operations and a results channel to
 // retrieve the async verifications (the order is that of the input slice).
 func (d *Dexcon) VerifyHeaders(chain consensus.ChainReader, headers []*types.Header, seals []bool) (chan<- struct{}, <-chan error) {
-	abort, results := make(chan struct{}), make(chan error)
+	if len(headers) == 0 {
+		abort, results := make(chan struct{}), make(chan error)
+		return abort, results
+	}
+
+	// Spawn as many workers as allowed threads, threshold signature
+	// verification dominates the cost so headers are checked in parallel.
+	workers := runtime.GOMAXPROCS(0)
+	if len(headers) < workers {
+		workers = len(headers)
+	}
+
+	var (
+		inputs = make(chan int)
+		done   = make(chan int, workers)
+		errors = make([]error, len(headers))
+		abort  = make(chan struct{})
+	)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for index := range inputs {
+				errors[index] = d.verifyHeaderWorker(chain, headers, seals, index)
+				done <- index
+			}
+		}()
+	}
+
+	errorsOut := make(chan error, len(headers))
 	go func() {
-		for range headers {
-			results <- nil
+		defer close(inputs)
+		var (
+			in, out = 0, 0
+			checked = make([]bool, len(headers))
+			inputs  = inputs
+		)
+		for {
+			select {
+			case inputs <- in:
+				if in++; in == len(headers) {
+					// Reached end of headers. Stop sending to workers.
+					inputs = nil
+				}
+			case index := <-done:
+				for checked[index] = true; checked[out]; out++ {
+					errorsOut <- errors[out]
+					if out == len(headers)-1 {
+						return
+					}
+				}
+			case <-abort:
+				return
+			}
 		}
 	}()
-	return abort, results
+	return abort, errorsOut
+}
+
+func (d *Dexcon) verifyHeaderWorker(chain consensus.ChainReader, headers []*types.Header, seals []bool, index int) error {
+	if chain.GetHeader(headers[index].Hash(), headers[index].Number.Uint64()) != nil {
+		return nil // known block
+	}
+	var parents []*types.Header
+	if index == 0 {
+		parent := chain.GetHeader(headers[0].ParentHash, headers[0].Number.Uint64()-1)
+		if parent == nil {
+			return consensus.ErrUnknownAncestor
+		}
+		parents = []*types.Header{parent}
+	} else if headers[index-1].Hash() == headers[index].ParentHash {
+		parents = headers[:index]
+	} else {
+		return consensus.ErrUnknownAncestor
+	}
+	return d.verifyHeader(chain, headers[index], parents, seals[index])
 }
 
 // verifyHeader checks whether a header conforms to the consensus rules.The
 // caller may optionally pass in a batch of parents (ascending order) to avoid
 // looking those up from the database. This is useful for concurrently verifying
 // a batch of new headers.
-func (d *Dexcon) verifyHeader(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
-	return nil
+func (d *Dexcon) verifyHeader(chain consensus.ChainReader, header *types.Header, parents []*types.Header, seal bool) error {
+	var coreBlock coreTypes.Block
+	if err := rlp.DecodeBytes(header.DexconMeta, &coreBlock); err != nil {
+		return fmt.Errorf("decode dexcon meta fail, number=%d, err=%v",
+			header.Number.Uint64(), err)
+	}
+	if header.Round != coreBlock.Position.Round {
+		if err := d.enforce(header, consensus.ErrInvalidRound); err != nil {
+			return err
+		}
+	}
+	if seal {
+		if err := d.verifySeal(header, &coreBlock); err != nil {
+			return err
+		}
+	}
+	return d.verifyCascadingFields(chain, header, parents)
 }
 
 // verifyCascadingFields verifies all the header fields that are not standalone,
@@ -91,6 +211,74 @@ func (d *Dexcon) verifyHeader(chain consensus.ChainReader, header *types.Header,
 // in a batch of parents (ascending order) to avoid looking those up from the
 // database. This is useful for concurrently verifying a batch of new headers.
 func (d *Dexcon) verifyCascadingFields(chain consensus.ChainReader, header *types.Header, parents []*types.Header) error {
+	number := header.Number.Uint64()
+
+	var parent *types.Header
+	if len(parents) > 0 {
+		parent = parents[len(parents)-1]
+	} else {
+		parent = chain.GetHeader(header.ParentHash, number-1)
+	}
+	if parent == nil || parent.Number.Uint64() != number-1 || parent.Hash() != header.ParentHash {
+		return consensus.ErrUnknownAncestor
+	}
+
+	// Round can only advance one at a time.
+	if header.Round < parent.Round || header.Round > parent.Round+1 {
+		if err := d.enforce(header, consensus.ErrInvalidRound); err != nil {
+			return err
+		}
+	}
+
+	roundHeight, ok := d.roundHeight(chain, header, parents)
+	return d.enforce(header, d.verifyReward(header, roundHeight, ok))
+}
+
+// roundHeight returns the height at which the round of header begins, looking
+// at the given parents first and falling back to the chain.
+func (d *Dexcon) roundHeight(chain consensus.ChainReader, header *types.Header, parents []*types.Header) (uint64, bool) {
+	for i := len(parents) - 1; i >= 0; i-- {
+		if parents[i].Round != header.Round {
+			return parents[i].Number.Uint64() + 1, true
+		}
+	}
+	if reader, ok := chain.(RoundHeightReader); ok {
+		return reader.GetRoundHeight(header.Round)
+	}
+	return 0, false
+}
+
+// verifyReward checks the reward of the header against the one derived from
+// governance state.
+func (d *Dexcon) verifyReward(header *types.Header, roundHeight uint64, known bool) error {
+	if d.govStateFetcer == nil {
+		return fmt.Errorf("governance state fetcher not set")
+	}
+	return VerifyReward(d.govStateFetcer, header, roundHeight, known)
+}
+
+// VerifyReward checks the reward of the header against the one derived from
+// governance state, given the height at which the round of the header begins.
+// If that height is unknown, whether the header is in an extended round can not
+// be told, so the header can only be verified once the beginning of its round
+// is known.
+func VerifyReward(gov GovernanceStateFetcher, header *types.Header, roundHeight uint64, known bool) error {
+	reward := header.Reward
+	if reward == nil {
+		reward = new(big.Int)
+	}
+	if header.Coinbase != (common.Address{}) && !known {
+		return consensus.ErrUnknownAncestor
+	}
+	if header.Coinbase == (common.Address{}) || inExtendedRound(gov, header, roundHeight) {
+		if reward.Sign() != 0 {
+			return consensus.ErrInvalidReward
+		}
+		return nil
+	}
+	if reward.Cmp(calculateBlockReward(gov, header.Round)) != 0 {
+		return consensus.ErrInvalidReward
+	}
 	return nil
 }
 
@@ -103,6 +291,57 @@ func (d *Dexcon) VerifyUncles(chain consensus.ChainReader, block *types.Block) e
 // VerifySeal implements consensus.Engine, checking whether the signature contained
 // in the header satisfies the consensus protocol requirements.
 func (d *Dexcon) VerifySeal(chain consensus.ChainReader, header *types.Header) error {
+	var coreBlock coreTypes.Block
+	if err := rlp.DecodeBytes(header.DexconMeta, &coreBlock); err != nil {
+		return fmt.Errorf("decode dexcon meta fail, number=%d, err=%v",
+			header.Number.Uint64(), err)
+	}
+	return d.verifySeal(header, &coreBlock)
+}
+
+// verifySeal checks the randomness of the header is the one in its consensus
+// meta and is signed by the DKG group of its round.
+func (d *Dexcon) verifySeal(header *types.Header, coreBlock *coreTypes.Block) error {
+	if !bytes.Equal(header.Randomness, coreBlock.Randomness) {
+		return d.enforce(header, consensus.ErrInvalidRandomness)
+	}
+	if d.verifierCache == nil {
+		return d.enforce(header, fmt.Errorf("tsig verifier cache not set"))
+	}
+	return d.enforce(header, VerifyRandomness(d.verifierCache, coreBlock))
+}
+
+// enforce returns err unless the engine is lenient, in which case the
+// violation is only logged.
+func (d *Dexcon) enforce(header *types.Header, err error) error {
+	if err == nil || !d.lenient {
+		return err
+	}
+	log.Debug("Ignore invalid dexon header", "number", header.Number, "hash", header.Hash(), "err", err)
+	return nil
+}
+
+// VerifyRandomness verifies the randomness of a consensus block is the
+// threshold signature of its hash signed by the DKG group of its round.
+func VerifyRandomness(verifierCache *dexCore.TSigVerifierCache, coreBlock *coreTypes.Block) error {
+	round := coreBlock.Position.Round
+	if round == 0 {
+		return nil
+	}
+
+	v, ok, err := verifierCache.UpdateAndGet(round)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return consensus.ErrDKGNotReady
+	}
+
+	if !v.VerifySignature(coreBlock.Hash, coreCrypto.Signature{
+		Type:      "bls",
+		Signature: coreBlock.Randomness}) {
+		return consensus.ErrInvalidRandomness
+	}
 	return nil
 }
 
@@ -112,11 +351,14 @@ func (d *Dexcon) Prepare(chain consensus.ChainReader, header *types.Header) erro
 	return nil
 }
 
-func (d *Dexcon) inExtendedRound(header *types.Header, state *state.StateDB) bool {
-	gs := vm.GovernanceState{state}
-	rgs := d.govStateFetcer.GetStateForConfigAtRound(header.Round)
+func (d *Dexcon) inExtendedRound(header *types.Header, roundHeight uint64) bool {
+	return inExtendedRound(d.govStateFetcer, header, roundHeight)
+}
+
+func inExtendedRound(gov GovernanceStateFetcher, header *types.Header, roundHeight uint64) bool {
+	rgs := gov.GetStateForConfigAtRound(header.Round)
 
-	roundEnd := gs.RoundHeight(new(big.Int).SetUint64(header.Round)).Uint64() + rgs.RoundLength().Uint64()
+	roundEnd := roundHeight + rgs.RoundLength().Uint64()
 
 	// Round 0 starts and height 0 instead of height 1.
 	if header.Round == 0 {
@@ -126,7 +368,11 @@ func (d *Dexcon) inExtendedRound(header *types.Header, state *state.StateDB) boo
 }
 
 func (d *Dexcon) calculateBlockReward(round uint64) *big.Int {
-	gs := d.govStateFetcer.GetStateForConfigAtRound(round)
+	return calculateBlockReward(d.govStateFetcer, round)
+}
+
+func calculateBlockReward(gov GovernanceStateFetcher, round uint64) *big.Int {
+	gs := gov.GetStateForConfigAtRound(round)
 	config := gs.Configuration()
 
 	blocksPerRound := config.RoundLength
@@ -161,7 +407,7 @@ func (d *Dexcon) Finalize(chain consensus.ChainReader, header *types.Header, sta
 		gs.PushRoundHeight(header.Number)
 
 		if header.Round > dexCore.DKGDelayRound {
-			// Check for dead node and disqualify them.
+			// Check for dead node and penalize them.
 			// A dead node node is defined as: a notary set node that did not propose
 			// any block in the past round.
 			addrs, err := d.govStateFetcer.DKGSetNodeKeyAddresses(header.Round - 1)
@@ -170,6 +416,7 @@ func (d *Dexcon) Finalize(chain consensus.ChainReader, header *types.Header, sta
 			}
 
 			gcs := d.govStateFetcer.GetStateForConfigAtRound(header.Round - 1)
+			graduated := chain.Config().IsLivenessPenalty(header.Number)
 
 			for addr := range addrs {
 				offset := gcs.NodesOffsetByNodeKeyAddress(addr)
@@ -180,8 +427,21 @@ func (d *Dexcon) Finalize(chain consensus.ChainReader, header *types.Header, sta
 				node := gcs.Node(offset)
 				lastHeight := gs.LastProposedHeight(node.Owner)
 				prevRoundHeight := gs.RoundHeight(big.NewInt(int64(header.Round - 1)))
+				alive := lastHeight.Uint64() >= prevRoundHeight.Uint64()
 
-				if lastHeight.Uint64() < prevRoundHeight.Uint64() {
+				// After the liveness penalty fork, a dead node is only jailed
+				// once it misses enough rounds in the liveness window.
+				if graduated {
+					jailed, err := gs.RecordLiveness(node, alive)
+					if err != nil {
+						log.Error("Failed to record node liveness", "err", err)
+					} else if jailed && !alive {
+						log.Info("Jail node", "round", header.Round, "nodePubKey", hex.EncodeToString(node.PublicKey))
+					}
+					continue
+				}
+
+				if !alive {
 					log.Info("Disqualify node", "round", header.Round, "nodePubKey", hex.EncodeToString(node.PublicKey))
 					err = gs.Disqualify(node)
 					if err != nil {
@@ -197,12 +457,19 @@ func (d *Dexcon) Finalize(chain consensus.ChainReader, header *types.Header, sta
 
 	// If this is not an empty block and we are not in extended round, calculate
 	// the block reward.
-	if header.Coinbase != (common.Address{}) && !d.inExtendedRound(header, state) {
+	roundHeight := gs.RoundHeight(new(big.Int).SetUint64(header.Round)).Uint64()
+	if header.Coinbase != (common.Address{}) && !d.inExtendedRound(header, roundHeight) {
 		reward = d.calculateBlockReward(header.Round)
 	}
 
 	header.Reward = reward
-	state.AddBalance(header.Coinbase, reward)
+	if chain.Config().IsRewardDistribution(header.Number) {
+		// Reward is shared between the node and its delegators and has to
+		// be claimed from governance contract.
+		gs.DistributeBlockReward(header.Coinbase, reward, new(big.Int).SetUint64(header.Time))
+	} else {
+		state.AddBalance(header.Coinbase, reward)
+	}
 	gs.IncTotalSupply(reward)
 
 	// Check if halving checkpoint reached.
@@ -243,8 +510,13 @@ func (d *Dexcon) Close() error {
 	return nil
 }
 
-// APIs implements consensus.Engine, returning the user facing RPC API to allow
-// controlling the signer voting.
+// APIs implements consensus.Engine, returning the user facing RPC API to
+// inspect the consensus provenance of blocks.
 func (d *Dexcon) APIs(chain consensus.ChainReader) []rpc.API {
-	return []rpc.API{}
+	return []rpc.API{{
+		Namespace: "dexcon",
+		Version:   "1.0",
+		Service:   &API{chain: chain, dexcon: d},
+		Public:    true,
+	}}
 }
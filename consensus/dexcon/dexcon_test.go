@@ -18,21 +18,31 @@
 package dexcon
 
 import (
+	"crypto/ecdsa"
 	"math/big"
 	"testing"
 
+	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
+	dexCore "github.com/dexon-foundation/dexon-consensus/core"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+	coreTypesDKG "github.com/dexon-foundation/dexon-consensus/core/types/dkg"
 	"github.com/stretchr/testify/suite"
 
 	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/consensus"
 	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/core/types"
 	"github.com/dexon-foundation/dexon/core/vm"
 	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/params"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
 )
 
 type govStateFetcher struct {
 	statedb *state.StateDB
+	dkgSet  map[common.Address]struct{}
 }
 
 func (g *govStateFetcher) GetStateForConfigAtRound(_ uint64) *vm.GovernanceState {
@@ -40,7 +50,76 @@ func (g *govStateFetcher) GetStateForConfigAtRound(_ uint64) *vm.GovernanceState
 }
 
 func (g *govStateFetcher) DKGSetNodeKeyAddresses(round uint64) (map[common.Address]struct{}, error) {
-	return make(map[common.Address]struct{}), nil
+	if g.dkgSet == nil {
+		return make(map[common.Address]struct{}), nil
+	}
+	return g.dkgSet, nil
+}
+
+type chainReader struct {
+	consensus.ChainReader
+	config *params.ChainConfig
+}
+
+func (c *chainReader) Config() *params.ChainConfig {
+	return c.config
+}
+
+// headerChain serves the given headers, the last one being the current.
+type headerChain struct {
+	consensus.ChainReader
+	headers      []*types.Header
+	roundHeights map[uint64]uint64
+}
+
+func (c *headerChain) CurrentHeader() *types.Header {
+	return c.headers[len(c.headers)-1]
+}
+
+func (c *headerChain) GetHeaderByNumber(number uint64) *types.Header {
+	if number >= uint64(len(c.headers)) {
+		return nil
+	}
+	return c.headers[number]
+}
+
+func (c *headerChain) GetHeaderByHash(hash common.Hash) *types.Header {
+	for _, header := range c.headers {
+		if header.Hash() == hash {
+			return header
+		}
+	}
+	return nil
+}
+
+func (c *headerChain) GetRoundHeight(round uint64) (uint64, bool) {
+	height, ok := c.roundHeights[round]
+	return height, ok
+}
+
+type tsigVerifierIntf struct {
+	nodes *NodeSet
+}
+
+func (t *tsigVerifierIntf) Configuration(round uint64) *coreTypes.Config {
+	return &coreTypes.Config{NotarySetSize: uint32(len(t.nodes.Nodes(round)))}
+}
+
+func (t *tsigVerifierIntf) DKGComplaints(round uint64) []*coreTypesDKG.Complaint {
+	return nil
+}
+
+func (t *tsigVerifierIntf) DKGMasterPublicKeys(round uint64) []*coreTypesDKG.MasterPublicKey {
+	var mpks []*coreTypesDKG.MasterPublicKey
+	for _, node := range t.nodes.Nodes(round) {
+		mpks = append(mpks, node.MasterPublicKey(round))
+	}
+	return mpks
+}
+
+func (t *tsigVerifierIntf) IsDKGFinal(round uint64) bool {
+	_, ok := t.nodes.nodes[round]
+	return ok
 }
 
 type DexconTestSuite struct {
@@ -90,7 +169,7 @@ func (d *DexconTestSuite) SetupTest() {
 
 func (d *DexconTestSuite) TestBlockRewardCalculation() {
 	consensus := New()
-	consensus.SetGovStateFetcher(&govStateFetcher{d.stateDB})
+	consensus.SetGovStateFetcher(&govStateFetcher{statedb: d.stateDB})
 
 	d.s.IncTotalStaked(big.NewInt(1e18))
 
@@ -99,6 +178,247 @@ func (d *DexconTestSuite) TestBlockRewardCalculation() {
 	d.Require().Equal(big.NewInt(5945585996), consensus.calculateBlockReward(0))
 }
 
+func (d *DexconTestSuite) TestVerifyReward() {
+	engine := New()
+	engine.SetGovStateFetcher(&govStateFetcher{statedb: d.stateDB})
+
+	d.s.IncTotalStaked(big.NewInt(1e18))
+	reward := engine.calculateBlockReward(0)
+
+	header := &types.Header{
+		Number:   big.NewInt(1),
+		Coinbase: common.Address{1},
+		Reward:   reward,
+	}
+	d.Require().NoError(engine.verifyReward(header, 0, true))
+
+	header.Reward = new(big.Int).Add(reward, big.NewInt(1))
+	d.Require().Equal(consensus.ErrInvalidReward, engine.verifyReward(header, 0, true))
+
+	// Empty blocks carry no reward.
+	header.Coinbase = common.Address{}
+	d.Require().Equal(consensus.ErrInvalidReward, engine.verifyReward(header, 0, true))
+	header.Reward = big.NewInt(0)
+	d.Require().NoError(engine.verifyReward(header, 0, true))
+
+	// Neither do blocks in an extended round.
+	header.Coinbase = common.Address{1}
+	header.Number = new(big.Int).SetUint64(d.config.RoundLength + 1)
+	d.Require().NoError(engine.verifyReward(header, 0, true))
+	header.Reward = reward
+	d.Require().Equal(consensus.ErrInvalidReward, engine.verifyReward(header, 0, true))
+
+	// Without knowing where the round begins, neither is accepted yet.
+	d.Require().Equal(consensus.ErrUnknownAncestor, engine.verifyReward(header, 0, false))
+	header.Reward = big.NewInt(0)
+	d.Require().Equal(consensus.ErrUnknownAncestor, engine.verifyReward(header, 0, false))
+
+	// Empty blocks are verified regardless.
+	header.Coinbase = common.Address{}
+	d.Require().NoError(engine.verifyReward(header, 0, false))
+}
+
+func (d *DexconTestSuite) TestFinalizeReward() {
+	engine := New()
+	engine.SetGovStateFetcher(&govStateFetcher{statedb: d.stateDB})
+
+	d.s.IncTotalStaked(big.NewInt(1e18))
+	reward := engine.calculateBlockReward(0)
+
+	config := *params.TestChainConfig
+	config.RewardDistributionBlock = big.NewInt(2)
+	chain := &chainReader{config: &config}
+
+	// Reward is paid to coinbase directly before the fork.
+	coinbase := common.Address{1}
+	header := &types.Header{Number: big.NewInt(1), Coinbase: coinbase}
+	_, err := engine.Finalize(chain, header, d.stateDB, nil, nil, nil)
+	d.Require().NoError(err)
+	d.Require().Equal(reward, header.Reward)
+	d.Require().Equal(reward, d.stateDB.GetBalance(coinbase))
+	d.Require().Equal(0, d.s.Reward(coinbase).Sign())
+
+	// Reward is deposited into governance contract after the fork.
+	govBalance := d.stateDB.GetBalance(vm.GovernanceContractAddress)
+	header = &types.Header{Number: big.NewInt(2), Coinbase: coinbase}
+	_, err = engine.Finalize(chain, header, d.stateDB, nil, nil, nil)
+	d.Require().NoError(err)
+	d.Require().Equal(reward, d.stateDB.GetBalance(coinbase))
+	d.Require().Equal(reward, d.s.Reward(coinbase))
+	d.Require().Equal(new(big.Int).Add(govBalance, reward),
+		d.stateDB.GetBalance(vm.GovernanceContractAddress))
+	d.Require().Equal(new(big.Int).Mul(reward, big.NewInt(2)), d.s.TotalSupply())
+}
+
+func (d *DexconTestSuite) TestFinalizeLiveness() {
+	key, err := crypto.GenerateKey()
+	d.Require().NoError(err)
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	d.s.Register(owner, crypto.FromECDSAPub(&key.PublicKey), "Test", "test@dexon.org",
+		"Taipei", "https://dexon.org", d.config.MinStake)
+	d.s.PushRoundHeight(big.NewInt(10))
+	dkgSet := map[common.Address]struct{}{owner: {}}
+
+	config := *params.TestChainConfig
+	config.LivenessPenaltyBlock = big.NewInt(20)
+	chain := &chainReader{config: &config}
+
+	// The node did not propose any block in round 1 and is disqualified
+	// before the fork.
+	statedb := d.stateDB.Copy()
+	gs := &vm.GovernanceState{StateDB: statedb}
+	engine := New()
+	engine.SetGovStateFetcher(&govStateFetcher{statedb: statedb, dkgSet: dkgSet})
+	header := &types.Header{Number: big.NewInt(11), Round: 2}
+	_, err = engine.Finalize(chain, header, statedb, nil, nil, nil)
+	d.Require().NoError(err)
+	d.Require().Equal(d.config.FineValues[vm.FineTypeFailStop], gs.Node(big.NewInt(0)).Fined)
+	d.Require().Equal(0, len(gs.QualifiedNodes()))
+
+	// The missed round is only recorded after the fork.
+	statedb = d.stateDB.Copy()
+	gs = &vm.GovernanceState{StateDB: statedb}
+	engine.SetGovStateFetcher(&govStateFetcher{statedb: statedb, dkgSet: dkgSet})
+	header = &types.Header{Number: big.NewInt(20), Round: 2}
+	_, err = engine.Finalize(chain, header, statedb, nil, nil, nil)
+	d.Require().NoError(err)
+	d.Require().Equal(0, gs.Node(big.NewInt(0)).Fined.Sign())
+	d.Require().Equal(int64(1), gs.LivenessRecord(owner).Int64())
+	d.Require().False(gs.Jailed(owner))
+	d.Require().Equal(1, len(gs.QualifiedNodes()))
+}
+
+func (d *DexconTestSuite) TestAPI() {
+	engine := New()
+	engine.SetGovStateFetcher(&govStateFetcher{statedb: d.stateDB})
+	d.s.IncTotalStaked(big.NewInt(1e18))
+	reward := engine.calculateBlockReward(0)
+
+	coreBlock := coreTypes.Block{
+		ProposerID: coreTypes.NodeID{Hash: coreCommon.NewRandomHash()},
+		Hash:       coreCommon.NewRandomHash(),
+		Position:   coreTypes.Position{Round: 0, Height: 1},
+		Witness:    coreTypes.Witness{Height: 0, Data: []byte{1}},
+		Randomness: []byte{2, 3},
+	}
+	meta, err := rlp.EncodeToBytes(&coreBlock)
+	d.Require().NoError(err)
+
+	chain := &headerChain{
+		headers: []*types.Header{
+			{Number: big.NewInt(0)},
+			{
+				Number:     big.NewInt(1),
+				Randomness: coreBlock.Randomness,
+				DexconMeta: meta,
+			},
+			{Number: new(big.Int).SetUint64(d.config.RoundLength + 1)},
+		},
+		roundHeights: map[uint64]uint64{0: 0},
+	}
+	api := engine.APIs(chain)[0].Service.(*API)
+
+	have, err := api.GetBlockReward(0)
+	d.Require().NoError(err)
+	d.Require().Equal(reward, have.ToInt())
+
+	// The configuration of round 2 is read from round 0, but round 1 has not
+	// begun so round 3 is not ready.
+	schedule, err := api.GetRewardSchedule(0, 3)
+	d.Require().NoError(err)
+	d.Require().Len(schedule, 3)
+	_, err = api.GetBlockReward(3)
+	d.Require().Equal(errGovStateNotReady, err)
+
+	number := rpc.BlockNumber(1)
+	extended, err := api.InExtendedRound(&number)
+	d.Require().NoError(err)
+	d.Require().False(extended)
+	extended, err = api.InExtendedRound(nil)
+	d.Require().NoError(err)
+	d.Require().True(extended)
+
+	decoded, err := api.GetDexconMeta(&number)
+	d.Require().NoError(err)
+	d.Require().Equal(common.Hash(coreBlock.ProposerID.Hash), decoded.ProposerID)
+	d.Require().Equal(common.Hash(coreBlock.Hash), decoded.Hash)
+	d.Require().Equal(uint64(1), uint64(decoded.Position.Height))
+	d.Require().Equal([]byte{1}, []byte(decoded.Witness.Data))
+	d.Require().Equal(coreBlock.Randomness, []byte(decoded.Randomness))
+
+	decoded, err = api.GetDexconMetaByHash(chain.headers[1].Hash())
+	d.Require().NoError(err)
+	d.Require().Equal(common.Hash(coreBlock.Hash), decoded.Hash)
+
+	randomness, err := api.GetRandomness(&number)
+	d.Require().NoError(err)
+	d.Require().Equal(coreBlock.Randomness, []byte(randomness))
+
+	number = rpc.BlockNumber(10)
+	_, err = api.GetRandomness(&number)
+	d.Require().Equal(errUnknownBlock, err)
+
+	// The first block of a round is resolved by the round height.
+	number = rpc.RoundStartBlockNumber(0)
+	extended, err = api.InExtendedRound(&number)
+	d.Require().NoError(err)
+	d.Require().False(extended)
+	number = rpc.RoundStartBlockNumber(1)
+	_, err = api.GetRandomness(&number)
+	d.Require().Equal(errUnknownBlock, err)
+}
+
+func (d *DexconTestSuite) TestVerifySeal() {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 4; i++ {
+		key, err := crypto.GenerateKey()
+		d.Require().NoError(err)
+		keys = append(keys, key)
+	}
+	nodes := NewNodeSet(0, []byte("crs"), types.NewEIP155Signer(big.NewInt(1)), keys)
+	nodes.RunDKG(1, 3)
+
+	engine := New()
+	engine.SetTSigVerifierCache(dexCore.NewTSigVerifierCache(&tsigVerifierIntf{nodes}, 5))
+
+	hash := crypto.Keccak256Hash([]byte("block"))
+	coreBlock := coreTypes.Block{
+		Hash:       coreCommon.Hash(hash),
+		Position:   coreTypes.Position{Round: 1, Height: 1},
+		Randomness: nodes.Randomness(1, hash),
+	}
+	meta, err := rlp.EncodeToBytes(&coreBlock)
+	d.Require().NoError(err)
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Round:      1,
+		Randomness: coreBlock.Randomness,
+		DexconMeta: meta,
+	}
+	d.Require().NoError(engine.VerifySeal(nil, header))
+
+	// Randomness signing another hash.
+	coreBlock.Randomness = nodes.Randomness(1, crypto.Keccak256Hash([]byte("other")))
+	meta, err = rlp.EncodeToBytes(&coreBlock)
+	d.Require().NoError(err)
+	header.Randomness = coreBlock.Randomness
+	header.DexconMeta = meta
+	d.Require().Equal(consensus.ErrInvalidRandomness, engine.VerifySeal(nil, header))
+
+	// Lenient verification only logs the violation.
+	engine.SetLenientVerification(true)
+	d.Require().NoError(engine.VerifySeal(nil, header))
+	engine.SetLenientVerification(false)
+
+	// DKG of round 2 is never run.
+	coreBlock.Position.Round = 2
+	meta, err = rlp.EncodeToBytes(&coreBlock)
+	d.Require().NoError(err)
+	header.DexconMeta = meta
+	d.Require().Equal(consensus.ErrDKGNotReady, engine.VerifySeal(nil, header))
+}
+
 func TestDexcon(t *testing.T) {
 	suite.Run(t, new(DexconTestSuite))
 }
@@ -36,4 +36,20 @@ var (
 	ErrInvalidNumber = errors.New("invalid block number")
 
 	ErrWitnessMismatch = errors.New("witness mismatch")
+
+	// ErrInvalidRandomness is returned if a block's randomness is not a valid
+	// threshold signature of the DKG group of its round.
+	ErrInvalidRandomness = errors.New("invalid randomness")
+
+	// ErrDKGNotReady is returned when the threshold signature of a block can not
+	// be verified since the DKG of its round is not finalized locally.
+	ErrDKGNotReady = errors.New("DKG not ready")
+
+	// ErrInvalidRound is returned if a block's round is inconsistent with its
+	// consensus meta or its parent.
+	ErrInvalidRound = errors.New("invalid round")
+
+	// ErrInvalidReward is returned if a block's reward differs from the one
+	// derived from governance state.
+	ErrInvalidReward = errors.New("invalid block reward")
 )
@@ -1701,6 +1701,17 @@ func (bc *BlockChain) VerifyDexonHeader(header *types.Header) error {
 	return bc.hc.VerifyDexonHeader(header, bc.gov, bc.verifierCache, bc.Validator())
 }
 
+// SetLenientVerification makes dexon header verification only log invalid
+// randomness, round and reward instead of rejecting the header.
+func (bc *BlockChain) SetLenientVerification(lenient bool) {
+	bc.hc.SetLenientVerification(lenient)
+}
+
+// TSigVerifierCache returns the cache used to verify block randomness.
+func (bc *BlockChain) TSigVerifierCache() *dexCore.TSigVerifierCache {
+	return bc.verifierCache
+}
+
 func (bc *BlockChain) ProcessBlock(block *types.Block, witness *coreTypes.Witness) (*common.Hash, error) {
 	root, events, logs, err := bc.processBlock(block, witness)
 	bc.PostChainEvents(events, logs)
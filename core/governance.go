@@ -1,9 +1,11 @@
 package core
 
 import (
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"sort"
 	"sync"
 	"time"
 
@@ -212,6 +214,44 @@ func (g *Governance) NotarySet(round uint64) (map[string]struct{}, error) {
 	return r, nil
 }
 
+// P2PPublicKey returns the p2p public key of the node with the consensus
+// public key, which is the consensus public key unless the node owner sets a
+// separate one.
+func (g *Governance) P2PPublicKey(pk []byte) []byte {
+	return p2pPublicKey(g.GetHeadState(), pk)
+}
+
+// NotaryNodeSet returns the p2p public keys of the notary set in hex, used to
+// connect to the notary set.
+func (g *Governance) NotaryNodeSet(round uint64) (map[string]struct{}, error) {
+	notarySet, err := g.NotarySet(round)
+	if err != nil {
+		return nil, err
+	}
+
+	headState := g.GetHeadState()
+	r := make(map[string]struct{}, len(notarySet))
+	for pk := range notarySet {
+		b, err := hex.DecodeString(pk)
+		if err != nil {
+			return nil, err
+		}
+		r[hex.EncodeToString(p2pPublicKey(headState, b))] = struct{}{}
+	}
+	return r, nil
+}
+
+func p2pPublicKey(headState *vm.GovernanceState, pk []byte) []byte {
+	key, err := crypto.UnmarshalPubkey(pk)
+	if err != nil {
+		return pk
+	}
+	if p2pKey := headState.P2PPublicKey(crypto.PubkeyToAddress(*key)); len(p2pKey) > 0 {
+		return p2pKey
+	}
+	return pk
+}
+
 func (g *Governance) DKGSetNodeKeyAddresses(round uint64) (map[common.Address]struct{}, error) {
 	config := g.Configuration(round)
 
@@ -232,6 +272,174 @@ func (g *Governance) DKGSetNodeKeyAddresses(round uint64) (map[common.Address]st
 	return r, nil
 }
 
+// NotarySetNode is a node in the notary set of a round.
+type NotarySetNode struct {
+	ID        coreTypes.NodeID
+	Owner     common.Address
+	PublicKey []byte
+	Staked    *big.Int
+	Delegated *big.Int
+}
+
+// DKGResult is the outcome of the DKG of a round.
+type DKGResult struct {
+	Reset            uint64
+	Threshold        int
+	MasterPublicKeys []*dkgTypes.MasterPublicKey
+	Complaints       []*dkgTypes.Complaint
+	Qualified        map[coreTypes.NodeID]struct{}
+
+	MPKReadys  uint64
+	Finalizeds uint64
+	Successes  uint64
+	MPKReady   bool
+	Final      bool
+	Success    bool
+}
+
+// lookupStateAtRound returns the governance state at the beginning of the
+// round. Unlike GetStateAtRound, rounds not begun and pruned states are
+// reported as errors instead of panics.
+func (g *Governance) lookupStateAtRound(round uint64) (*vm.GovernanceState, error) {
+	headState, err := g.db.State()
+	if err != nil {
+		return nil, err
+	}
+	head := &vm.GovernanceState{StateDB: headState}
+	height := head.RoundHeight(new(big.Int).SetUint64(round)).Uint64()
+	if round != 0 && height == 0 {
+		return nil, fmt.Errorf("round %d not begun", round)
+	}
+	s, err := g.db.StateAt(height)
+	if err != nil {
+		return nil, err
+	}
+	return &vm.GovernanceState{StateDB: s}, nil
+}
+
+// lookupStateForConfigAtRound is the non-panicking GetStateForConfigAtRound.
+func (g *Governance) lookupStateForConfigAtRound(round uint64) (*vm.GovernanceState, error) {
+	if round < dexCore.ConfigRoundShift {
+		round = 0
+	} else {
+		round -= dexCore.ConfigRoundShift
+	}
+	return g.lookupStateAtRound(round)
+}
+
+// NotarySetNodesAtRound computes the notary set of a begun round from the
+// governance states the round is configured by, bypassing the node set cache
+// so that any historical round can be queried.
+func (g *Governance) NotarySetNodesAtRound(round uint64) ([]*NotarySetNode, error) {
+	roundState, err := g.lookupStateAtRound(round)
+	if err != nil {
+		return nil, err
+	}
+	configState, err := g.lookupStateForConfigAtRound(round)
+	if err != nil {
+		return nil, err
+	}
+
+	var crs common.Hash
+	if round <= dexCore.DKGDelayRound {
+		genesisState, err := g.lookupStateAtRound(0)
+		if err != nil {
+			return nil, err
+		}
+		crs = genesisState.CRS()
+		for i := uint64(0); i < round; i++ {
+			crs = crypto.Keccak256Hash(crs[:])
+		}
+	} else {
+		crs = roundState.CRS()
+	}
+
+	nodes := configState.QualifiedNodes()
+	nodeSet := coreTypes.NewNodeSet()
+	offsets := make(map[coreTypes.NodeID]int, len(nodes))
+	for i, n := range nodes {
+		pk, err := coreEcdsa.NewPublicKeyFromByteSlice(n.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		id := coreTypes.NewNodeID(pk)
+		nodeSet.Add(id)
+		offsets[id] = i
+	}
+	notarySet := nodeSet.GetSubSet(int(configState.Configuration().NotarySetSize),
+		coreTypes.NewNotarySetTarget(coreCommon.Hash(crs)))
+
+	result := make([]*NotarySetNode, 0, len(notarySet))
+	for id := range notarySet {
+		n := nodes[offsets[id]]
+		result = append(result, &NotarySetNode{
+			ID:        id,
+			Owner:     n.Owner,
+			PublicKey: n.PublicKey,
+			Staked:    n.Staked,
+			Delegated: configState.Delegated(n.Owner),
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return bytes.Compare(result[i].ID.Hash[:], result[j].ID.Hash[:]) < 0
+	})
+	return result, nil
+}
+
+// DKGResultAtRound returns the DKG outcome of a round from the governance
+// state at the beginning of the round, or the head state if the DKG of the
+// round is still running.
+func (g *Governance) DKGResultAtRound(round uint64) (*DKGResult, error) {
+	if round < dexCore.DKGDelayRound {
+		return nil, fmt.Errorf("no DKG in round %d", round)
+	}
+	headState, err := g.db.State()
+	if err != nil {
+		return nil, err
+	}
+	s := &vm.GovernanceState{StateDB: headState}
+	dkgRound := s.DKGRound().Uint64()
+	if round > dkgRound {
+		return nil, fmt.Errorf("DKG of round %d not begun", round)
+	}
+	if round < dkgRound {
+		if s, err = g.lookupStateAtRound(round); err != nil {
+			return nil, err
+		}
+		if s.DKGRound().Uint64() != round {
+			return nil, fmt.Errorf("DKG state of round %d not available", round)
+		}
+	}
+	configState, err := g.lookupStateForConfigAtRound(round)
+	if err != nil {
+		return nil, err
+	}
+	config := &coreTypes.Config{
+		NotarySetSize: configState.Configuration().NotarySetSize,
+	}
+
+	result := &DKGResult{
+		Reset:            s.DKGResetCount(new(big.Int).SetUint64(round)).Uint64(),
+		Threshold:        coreUtils.GetDKGThreshold(config),
+		MasterPublicKeys: s.DKGMasterPublicKeyItems(),
+		Complaints:       s.DKGComplaintItems(),
+		MPKReadys:        s.DKGMPKReadysCount().Uint64(),
+		Finalizeds:       s.DKGFinalizedsCount().Uint64(),
+		Successes:        s.DKGSuccessesCount().Uint64(),
+	}
+	_, result.Qualified, err = dkgTypes.CalcQualifyNodes(
+		result.MasterPublicKeys, result.Complaints, result.Threshold)
+	if err != nil {
+		// Too few qualified nodes, the DKG of the round fails.
+		result.Qualified = make(map[coreTypes.NodeID]struct{})
+	}
+	threshold := 2*uint64(config.NotarySetSize)/3 + 1
+	result.MPKReady = result.MPKReadys >= threshold
+	result.Final = result.Finalizeds >= threshold
+	result.Success = result.Successes >= uint64(coreUtils.GetDKGValidThreshold(config))
+	return result, nil
+}
+
 func (g *Governance) getOrUpdateDKGCache(round uint64) *dkgCacheItem {
 	s := g.GetStateForDKGAtRound(round)
 	if s == nil {
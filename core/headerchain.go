@@ -24,11 +24,13 @@ import (
 	"math/big"
 	mrand "math/rand"
 	"reflect"
+	"runtime"
+	"sort"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	dexCore "github.com/dexon-foundation/dexon-consensus/core"
-	coreCrypto "github.com/dexon-foundation/dexon-consensus/core/crypto"
 	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
 	lru "github.com/hashicorp/golang-lru"
 
@@ -47,9 +49,10 @@ import (
 )
 
 const (
-	headerCacheLimit = 512
-	tdCacheLimit     = 1024
-	numberCacheLimit = 2048
+	headerCacheLimit      = 512
+	tdCacheLimit          = 1024
+	numberCacheLimit      = 2048
+	roundHeightCacheLimit = 16
 )
 
 // HeaderChain implements the basic block header chain logic that is shared by
@@ -70,10 +73,16 @@ type HeaderChain struct {
 	tdCache     *lru.Cache // Cache for the most recent block total difficulties
 	numberCache *lru.Cache // Cache for the most recent block numbers
 
+	roundHeightCache *lru.Cache // Cache for the height where recent rounds begin
+
 	procInterrupt func() bool
 
 	rand   *mrand.Rand
 	engine consensus.Engine
+
+	// lenientVerification only logs dexon headers violating the randomness,
+	// round or reward rules instead of rejecting them.
+	lenientVerification bool
 }
 
 // NewHeaderChain creates a new HeaderChain structure.
@@ -84,6 +93,7 @@ func NewHeaderChain(chainDb ethdb.Database, config *params.ChainConfig, engine c
 	headerCache, _ := lru.New(headerCacheLimit)
 	tdCache, _ := lru.New(tdCacheLimit)
 	numberCache, _ := lru.New(numberCacheLimit)
+	roundHeightCache, _ := lru.New(roundHeightCacheLimit)
 
 	// Seed a fast but crypto originating random generator
 	seed, err := crand.Int(crand.Reader, big.NewInt(math.MaxInt64))
@@ -92,14 +102,15 @@ func NewHeaderChain(chainDb ethdb.Database, config *params.ChainConfig, engine c
 	}
 
 	hc := &HeaderChain{
-		config:        config,
-		chainDb:       chainDb,
-		headerCache:   headerCache,
-		tdCache:       tdCache,
-		numberCache:   numberCache,
-		procInterrupt: procInterrupt,
-		rand:          mrand.New(mrand.NewSource(seed.Int64())),
-		engine:        engine,
+		config:           config,
+		chainDb:          chainDb,
+		headerCache:      headerCache,
+		tdCache:          tdCache,
+		numberCache:      numberCache,
+		roundHeightCache: roundHeightCache,
+		procInterrupt:    procInterrupt,
+		rand:             mrand.New(mrand.NewSource(seed.Int64())),
+		engine:           engine,
 	}
 
 	hc.genesisHeader = hc.GetHeaderByNumber(0)
@@ -403,7 +414,9 @@ type headerVerifierCache struct {
 	gov            dexcon.GovernanceStateFetcher
 	stateCache     *lru.Cache
 	nodeOwnerCache *lru.Cache
+	nodeOwnerLock  sync.Mutex
 	configCache    *lru.Cache
+	roundHeights   map[uint64]uint64
 }
 
 func newHeaderVerifierCache(
@@ -418,6 +431,7 @@ func newHeaderVerifierCache(
 		stateCache:     stateCache,
 		nodeOwnerCache: nodeOwnerCache,
 		configCache:    configCache,
+		roundHeights:   make(map[uint64]uint64),
 	}
 }
 
@@ -432,6 +446,9 @@ func (c *headerVerifierCache) state(round uint64) *vm.GovernanceState {
 
 func (c *headerVerifierCache) getNodeOwnerByID(round uint64, ID coreTypes.NodeID) (
 	common.Address, error) {
+	c.nodeOwnerLock.Lock()
+	defer c.nodeOwnerLock.Unlock()
+
 	nodeOwner, exist := c.nodeOwnerCache.Get(round)
 	if !exist {
 		nodeOwner = make(map[coreTypes.NodeID]interface{})
@@ -463,6 +480,12 @@ func (c *headerVerifierCache) configuration(round uint64) *params.DexconConfig {
 	return cfg
 }
 
+// SetLenientVerification makes dexon header verification only log invalid
+// randomness, round and reward instead of rejecting the header.
+func (hc *HeaderChain) SetLenientVerification(lenient bool) {
+	hc.lenientVerification = lenient
+}
+
 func (hc *HeaderChain) ValidateDexonHeaderChain(chain []*types.HeaderWithGovState,
 	gov dexcon.GovernanceStateFetcher,
 	verifierCache *dexCore.TSigVerifierCache, validator Validator) (int, error) {
@@ -478,32 +501,68 @@ func (hc *HeaderChain) ValidateDexonHeaderChain(chain []*types.HeaderWithGovStat
 		}
 	}
 
-	// If the last TSig pass the verification, we don't need to verify others.
-	cache := newHeaderVerifierCache(verifierCache, gov)
-	verifyTSig := false
-	if err := hc.verifyDexonHeader(chain[len(chain)-1].Header, gov, cache, true); err != nil {
-		verifyTSig = true
+	log.Debug("validate header chain", "parent", chain[0].ParentHash.String(), "number", chain[0].Number.Uint64()-1)
+	parent := hc.GetHeader(chain[0].ParentHash, chain[0].Number.Uint64()-1)
+	if parent == nil {
+		return 0, consensus.ErrUnknownAncestor
 	}
-	// Iterate over the headers and ensure they all check out
+
+	// Record the rounds beginning inside the batch, the headers are not
+	// written yet so they can not be looked up from the database.
+	cache := newHeaderVerifierCache(verifierCache, gov)
+	parents := make([]*types.Header, len(chain))
 	for i, header := range chain {
-		// If the chain is terminating, stop processing blocks
-		if hc.procInterrupt() {
-			log.Debug("Premature abort during headers verification")
-			return 0, errors.New("aborted")
+		parents[i] = parent
+		if header.Round != parent.Round {
+			cache.roundHeights[header.Round] = header.Number.Uint64()
 		}
+		parent = header.Header
+	}
 
-		if i == 0 {
-			log.Debug("validate header chain", "parent", header.ParentHash.String(), "number", header.Number.Uint64()-1)
-			if parent := hc.GetHeader(header.ParentHash, header.Number.Uint64()-1); parent == nil {
-				return 0, consensus.ErrUnknownAncestor
+	// Verify the headers in parallel, threshold signature verification is
+	// the dominating cost.
+	workers := runtime.GOMAXPROCS(0)
+	if len(chain) < workers {
+		workers = len(chain)
+	}
+	var (
+		errs  = make([]error, len(chain))
+		tasks = make(chan int)
+		wg    sync.WaitGroup
+	)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range tasks {
+				errs[i] = hc.verifyDexonHeader(chain[i].Header, parents[i], gov, cache)
 			}
+		}()
+	}
+	aborted := false
+	for i := range chain {
+		// If the chain is terminating, stop processing blocks
+		if hc.procInterrupt() {
+			aborted = true
+			break
 		}
+		tasks <- i
+	}
+	close(tasks)
+	wg.Wait()
 
-		if err := hc.verifyDexonHeader(header.Header, gov, cache, verifyTSig); err != nil {
+	if aborted {
+		log.Debug("Premature abort during headers verification")
+		return 0, errors.New("aborted")
+	}
+	for i, err := range errs {
+		if err != nil {
 			return i, err
 		}
+	}
 
-		// Verify witness
+	// Verify witness
+	for i, header := range chain {
 		var coreBlock coreTypes.Block
 		if err := rlp.DecodeBytes(header.DexconMeta, &coreBlock); err != nil {
 			return i, err
@@ -536,11 +595,15 @@ func (hc *HeaderChain) VerifyDexonHeader(header *types.Header,
 	gov dexcon.GovernanceStateFetcher,
 	verifierCache *dexCore.TSigVerifierCache, validator Validator) error {
 
-	if parent := hc.GetHeader(header.ParentHash, header.Number.Uint64()-1); parent == nil {
+	parent := hc.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
 		return consensus.ErrUnknownAncestor
 	}
 	cache := newHeaderVerifierCache(verifierCache, gov)
-	if err := hc.verifyDexonHeader(header, gov, cache, true); err != nil {
+	if header.Round != parent.Round {
+		cache.roundHeights[header.Round] = header.Number.Uint64()
+	}
+	if err := hc.verifyDexonHeader(header, parent, gov, cache); err != nil {
 		return err
 	}
 
@@ -565,9 +628,11 @@ func (hc *HeaderChain) VerifyDexonHeader(header *types.Header,
 	return nil
 }
 
-func (hc *HeaderChain) verifyDexonHeader(header *types.Header,
-	gov dexcon.GovernanceStateFetcher,
-	cache *headerVerifierCache, verifyTSig bool) error {
+// verifyDexonHeader checks the header against its consensus meta and the
+// governance state. The cache is only read, so it is safe to verify headers
+// sharing a cache concurrently.
+func (hc *HeaderChain) verifyDexonHeader(header, parent *types.Header,
+	gov dexcon.GovernanceStateFetcher, cache *headerVerifierCache) error {
 
 	// If the header is a banned one, straight out abort
 	if BadHashes[header.Hash()] {
@@ -586,10 +651,9 @@ func (hc *HeaderChain) verifyDexonHeader(header *types.Header,
 			header.Number.Uint64(), err)
 	}
 
-	if verifyTSig {
-		if err := hc.verifyTSig(&coreBlock, cache.verifierCache); err != nil {
-			log.Debug("verify header sig fail, number=%d, err=%v",
-				header.Number.Uint64(), err)
+	if err := dexcon.VerifyRandomness(cache.verifierCache, &coreBlock); err != nil {
+		if err := hc.enforce(header, err); err != nil {
+			return err
 		}
 	}
 
@@ -624,39 +688,63 @@ func (hc *HeaderChain) verifyDexonHeader(header *types.Header,
 		return fmt.Errorf("round mismatch")
 	}
 
+	// Round can only advance one at a time.
+	if header.Round < parent.Round || header.Round > parent.Round+1 {
+		if err := hc.enforce(header, consensus.ErrInvalidRound); err != nil {
+			return err
+		}
+	}
+
 	config := cache.configuration(header.Round)
 	if header.GasLimit != config.BlockGasLimit {
 		return fmt.Errorf("block gas limit mismatch")
 	}
-	return nil
-}
 
-func (hc *HeaderChain) verifyTSig(coreBlock *coreTypes.Block,
-	verifierCache *dexCore.TSigVerifierCache) error {
+	roundHeight, ok := cache.roundHeights[header.Round]
+	if !ok {
+		roundHeight, ok = hc.roundHeight(header.Round)
+	}
+	return hc.enforce(header, dexcon.VerifyReward(gov, header, roundHeight, ok))
+}
 
-	round := coreBlock.Position.Round
-	randomness := coreBlock.Randomness
+// enforce returns err unless lenient verification is enabled, in which case the
+// violation is only logged.
+func (hc *HeaderChain) enforce(header *types.Header, err error) error {
+	if err == nil || !hc.lenientVerification {
+		return err
+	}
+	log.Debug("Ignore invalid dexon header", "number", header.Number, "hash", header.Hash(), "err", err)
+	return nil
+}
 
+// roundHeight returns the height of the first canonical header of the given
+// round. Rounds never decrease along the chain, so the header is located by
+// binary search.
+func (hc *HeaderChain) roundHeight(round uint64) (uint64, bool) {
 	if round == 0 {
-		return nil
+		return 0, true
 	}
-
-	// Verify threshold signature
-	v, ok, err := verifierCache.UpdateAndGet(round)
-	if err != nil {
-		panic(err)
+	if height, ok := hc.roundHeightCache.Get(round); ok {
+		return height.(uint64), true
 	}
-
-	if !ok {
-		panic(fmt.Errorf("DKG of round %d is not finished", round))
+	head := hc.CurrentHeader().Number.Uint64()
+	var lookupErr bool
+	height := uint64(sort.Search(int(head+1), func(i int) bool {
+		header := hc.GetHeaderByNumber(uint64(i))
+		if header == nil {
+			lookupErr = true
+			return true
+		}
+		return header.Round >= round
+	}))
+	if lookupErr || height > head {
+		return 0, false
 	}
-
-	if !v.VerifySignature(coreBlock.Hash, coreCrypto.Signature{
-		Type:      "bls",
-		Signature: randomness}) {
-		return fmt.Errorf("signature invalid")
+	if header := hc.GetHeaderByNumber(height); header == nil || header.Round != round {
+		return 0, false
 	}
-	return nil
+	hc.roundHeightCache.Add(round, height)
+	return height, true
 }
 
 // InsertDexonHeaderChain attempts to insert the given header chain in to the local
@@ -4,8 +4,10 @@ import (
 	"bytes"
 
 	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
 
 	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/rlp"
 )
@@ -49,3 +51,50 @@ func WriteCoreBlock(db DatabaseWriter, hash common.Hash, block *coreTypes.Block)
 	}
 	WriteCoreBlockRLP(db, hash, data)
 }
+
+// CoreBlockIterator iterates over the core blocks stored by WriteCoreBlock in
+// ascending order of their hashes.
+type CoreBlockIterator struct {
+	it iterator.Iterator
+}
+
+// NewCoreBlockIterator creates an iterator over all core blocks in db.
+func NewCoreBlockIterator(db ethdb.Iteratee) *CoreBlockIterator {
+	return &CoreBlockIterator{it: db.NewIteratorWithPrefix(coreBlockPrefix)}
+}
+
+// Next moves the iterator to the next core block, returning whether there is
+// one. Other entries sharing the core block prefix, like DKG private keys, are
+// skipped by their key length.
+func (it *CoreBlockIterator) Next() bool {
+	for it.it.Next() {
+		if len(it.it.Key()) == len(coreBlockPrefix)+common.HashLength {
+			return true
+		}
+	}
+	return false
+}
+
+// Hash returns the hash of the current core block.
+func (it *CoreBlockIterator) Hash() common.Hash {
+	return common.BytesToHash(it.it.Key()[len(coreBlockPrefix):])
+}
+
+// Block decodes the current core block.
+func (it *CoreBlockIterator) Block() (*coreTypes.Block, error) {
+	block := new(coreTypes.Block)
+	if err := rlp.DecodeBytes(it.it.Value(), block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
+// Error returns any error encountered during iteration.
+func (it *CoreBlockIterator) Error() error {
+	return it.it.Error()
+}
+
+// Release releases the resources held by the iterator.
+func (it *CoreBlockIterator) Release() {
+	it.it.Release()
+}
@@ -1,9 +1,10 @@
 package rawdb
 
 import (
-	"bytes"
+	"encoding/binary"
 
 	coreDKG "github.com/dexon-foundation/dexon-consensus/core/crypto/dkg"
+	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/rlp"
 )
@@ -26,33 +27,107 @@ func WriteCoreDKGPrivateKeyRLP(db DatabaseWriter, round uint64, rlp rlp.RawValue
 	return err
 }
 
+// DeleteCoreDKGPrivateKey removes the plaintext DKG private key of the round.
+func DeleteCoreDKGPrivateKey(db DatabaseDeleter, round uint64) {
+	if err := db.Delete(coreDKGPrivateKeyKey(round)); err != nil {
+		log.Crit("Failed to delete core DKG private key", "err", err, "round", round)
+	}
+}
+
 func ReadCoreDKGPrivateKey(db DatabaseReader, round, reset uint64) *coreDKG.PrivateKey {
 	data := ReadCoreDKGPrivateKeyRLP(db, round)
 	if len(data) == 0 {
 		return nil
 	}
-	key := &dkgPrivateKey{
-		PK: new(coreDKG.PrivateKey),
-	}
-	if err := rlp.Decode(bytes.NewReader(data), key); err != nil {
+	pk, keyReset, err := DecodeCoreDKGPrivateKey(data)
+	if err != nil {
 		log.Error("Invalid core DKG private key RLP", "round", round, "err", err)
 		return nil
 	}
-	if key.Reset != reset {
+	if keyReset != reset {
 		return nil
 	}
-	return key.PK
+	return pk
 }
 
 func WriteCoreDKGPrivateKey(db DatabaseWriter, round, reset uint64, pk *coreDKG.PrivateKey) error {
-	key := &dkgPrivateKey{
-		PK:    pk,
-		Reset: reset,
-	}
-	data, err := rlp.EncodeToBytes(key)
+	data, err := EncodeCoreDKGPrivateKey(pk, reset)
 	if err != nil {
 		log.Crit("Failed to RLP encode core DKG private key", "round", round, "err", err)
 		return err
 	}
 	return WriteCoreDKGPrivateKeyRLP(db, round, data)
 }
+
+// EncodeCoreDKGPrivateKey encodes the DKG private key of a DKG reset into the
+// RLP stored by WriteCoreDKGPrivateKey.
+func EncodeCoreDKGPrivateKey(pk *coreDKG.PrivateKey, reset uint64) ([]byte, error) {
+	return rlp.EncodeToBytes(&dkgPrivateKey{
+		PK:    pk,
+		Reset: reset,
+	})
+}
+
+// DecodeCoreDKGPrivateKey decodes the DKG private key and the DKG reset it
+// belongs to.
+func DecodeCoreDKGPrivateKey(data []byte) (*coreDKG.PrivateKey, uint64, error) {
+	key := &dkgPrivateKey{
+		PK: new(coreDKG.PrivateKey),
+	}
+	if err := rlp.DecodeBytes(data, key); err != nil {
+		return nil, 0, err
+	}
+	return key.PK, key.Reset, nil
+}
+
+// ReadCoreDKGPrivateKeyRounds returns the rounds of the DKG private keys
+// stored in plaintext.
+func ReadCoreDKGPrivateKeyRounds(db ethdb.Iteratee) ([]uint64, error) {
+	it := db.NewIteratorWithPrefix(coreDKGPrivateKeyPrefix)
+	defer it.Release()
+
+	var rounds []uint64
+	for it.Next() {
+		// Core blocks share the prefix, skip them by the key length.
+		key := it.Key()
+		if len(key) != len(coreDKGPrivateKeyPrefix)+8 {
+			continue
+		}
+		rounds = append(rounds, binary.LittleEndian.Uint64(key[len(coreDKGPrivateKeyPrefix):]))
+	}
+	return rounds, it.Error()
+}
+
+// ReadCoreEncryptedDKGPrivateKey retrieves the encrypted DKG private key of
+// the round.
+func ReadCoreEncryptedDKGPrivateKey(db DatabaseReader, round uint64) []byte {
+	data, _ := db.Get(coreEncryptedDKGPrivateKeyKey(round))
+	return data
+}
+
+// WriteCoreEncryptedDKGPrivateKey stores the encrypted DKG private key of the
+// round.
+func WriteCoreEncryptedDKGPrivateKey(db DatabaseWriter, round uint64, data []byte) error {
+	err := db.Put(coreEncryptedDKGPrivateKeyKey(round), data)
+	if err != nil {
+		log.Crit("Failed to store encrypted core DKG private key", "err", err, "round", round)
+	}
+	return err
+}
+
+// ReadCoreDKGKeyEncryption retrieves the parameters the key encrypting DKG
+// private keys is derived with.
+func ReadCoreDKGKeyEncryption(db DatabaseReader) []byte {
+	data, _ := db.Get(coreDKGKeyEncryptionKey)
+	return data
+}
+
+// WriteCoreDKGKeyEncryption stores the parameters the key encrypting DKG
+// private keys is derived with.
+func WriteCoreDKGKeyEncryption(db DatabaseWriter, data []byte) error {
+	err := db.Put(coreDKGKeyEncryptionKey, data)
+	if err != nil {
+		log.Crit("Failed to store core DKG key encryption", "err", err)
+	}
+	return err
+}
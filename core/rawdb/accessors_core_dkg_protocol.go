@@ -42,3 +42,25 @@ func WriteCoreDKGProtocol(db DatabaseWriter, protocol *coreDb.DKGProtocolInfo) e
 	}
 	return WriteCoreDKGProtocolRLP(db, data)
 }
+
+// DeleteCoreDKGProtocol removes the plaintext DKG protocol.
+func DeleteCoreDKGProtocol(db DatabaseDeleter) {
+	if err := db.Delete(coreDKGProtocolKey); err != nil {
+		log.Crit("Failed to delete core DKG protocol", "err", err)
+	}
+}
+
+// ReadCoreEncryptedDKGProtocol retrieves the encrypted DKG protocol.
+func ReadCoreEncryptedDKGProtocol(db DatabaseReader) []byte {
+	data, _ := db.Get(coreEncryptedDKGProtocolKey)
+	return data
+}
+
+// WriteCoreEncryptedDKGProtocol stores the encrypted DKG protocol.
+func WriteCoreEncryptedDKGProtocol(db DatabaseWriter, data []byte) error {
+	err := db.Put(coreEncryptedDKGProtocolKey, data)
+	if err != nil {
+		log.Crit("Failed to store encrypted core DKG protocol", "err", err)
+	}
+	return err
+}
@@ -60,6 +60,10 @@ var (
 	coreCompactionChainTipKey = []byte("CoreChainTip")
 	coreDKGProtocolKey        = []byte("CoreDKGProtocol")
 
+	coreEncryptedDKGPrivateKeyPrefix = []byte("EDPK")
+	coreDKGKeyEncryptionKey          = []byte("CoreDKGKeyEncryption")
+	coreEncryptedDKGProtocolKey      = []byte("CoreEncryptedDKGProtocol")
+
 	preimagePrefix = []byte("secure-key-")      // preimagePrefix + hash -> preimage
 	configPrefix   = []byte("ethereum-config-") // config prefix for the db
 
@@ -137,6 +141,14 @@ func coreDKGPrivateKeyKey(round uint64) []byte {
 	return ret
 }
 
+// coreEncryptedDKGPrivateKeyKey = coreEncryptedDKGPrivateKeyPrefix + round
+func coreEncryptedDKGPrivateKeyKey(round uint64) []byte {
+	ret := make([]byte, len(coreEncryptedDKGPrivateKeyPrefix)+8)
+	copy(ret, coreEncryptedDKGPrivateKeyPrefix)
+	binary.LittleEndian.PutUint64(ret[len(coreEncryptedDKGPrivateKeyPrefix):], round)
+	return ret
+}
+
 // bloomBitsKey = bloomBitsPrefix + bit (uint16 big endian) + section (uint64 big endian) + hash
 func bloomBitsKey(bit uint, section uint64, hash common.Hash) []byte {
 	key := append(append(bloomBitsPrefix, make([]byte, 10)...), hash.Bytes()...)
@@ -3,6 +3,7 @@ package state
 import (
 	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/trie"
 )
 
@@ -29,3 +30,62 @@ func GetGovState(statedb *StateDB, header *types.Header,
 	}
 	return govState, nil
 }
+
+// DiffGovState converts the governance state to trie nodes, keeping only the
+// storage trie nodes which are not in the storage trie of the base state. All
+// the storage trie nodes are kept if base is nil.
+func DiffGovState(s, base *types.GovState) (*types.GovStateDiff, error) {
+	triedb := trie.NewDatabase(ethdb.NewMemDatabase())
+	t, err := newGovStorageTrie(triedb, s.Storage)
+	if err != nil {
+		return nil, err
+	}
+	it := t.NodeIterator(nil)
+	if base != nil {
+		baseTrie, err := newGovStorageTrie(triedb, base.Storage)
+		if err != nil {
+			return nil, err
+		}
+		it, _ = trie.NewDifferenceIterator(baseTrie.NodeIterator(nil), it)
+	}
+
+	diff := &types.GovStateDiff{
+		BlockHash: s.BlockHash,
+		Number:    s.Number,
+		Root:      s.Root,
+		Proof:     s.Proof,
+	}
+	for it.Next(true) {
+		// Nodes embedded in their parents have no hash.
+		if it.Hash() == (common.Hash{}) {
+			continue
+		}
+		node, err := triedb.Node(it.Hash())
+		if err != nil {
+			return nil, err
+		}
+		diff.Nodes = append(diff.Nodes, node)
+	}
+	if it.Error() != nil {
+		return nil, it.Error()
+	}
+	return diff, nil
+}
+
+// newGovStorageTrie rebuilds the governance contract storage trie in the
+// database.
+func newGovStorageTrie(triedb *trie.Database, storage [][2][]byte) (*trie.Trie, error) {
+	t, err := trie.New(common.Hash{}, triedb)
+	if err != nil {
+		return nil, err
+	}
+	for _, kv := range storage {
+		if err := t.TryUpdate(kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := t.Commit(nil); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
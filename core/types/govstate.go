@@ -14,7 +14,22 @@ type GovState struct {
 	Storage   [][2][]byte
 }
 
+// GovStateDiff is the governance state sent as trie nodes: the proof of the
+// governance contract account, and the nodes of the contract storage trie
+// which are not in the storage trie of a base state the receiver has.
+type GovStateDiff struct {
+	BlockHash common.Hash
+	Number    *big.Int
+	Root      common.Hash
+	Proof     [][]byte
+	Nodes     [][]byte
+}
+
+// HeaderWithGovState is a header with the governance state at the block. The
+// governance state is sent as a diff on the network, and is expanded to the
+// full state by the receiver.
 type HeaderWithGovState struct {
 	*Header
-	GovState *GovState `rlp:"nil"`
+	GovState     *GovState     `rlp:"-"`
+	GovStateDiff *GovStateDiff `rlp:"nil"`
 }
@@ -18,10 +18,13 @@
 package vm
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/dexon-foundation/dexon/accounts/abi"
 	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
 )
 
 var GovernanceContractAddress = common.HexToAddress("63751838d6485578b23e8b051d40861ecc416794")
@@ -30,6 +33,7 @@ var GovernanceABI *OracleContractABI
 
 func init() {
 	GovernanceABI = NewOracleContractABI(GovernanceABIJSON)
+	legacyUpdateConfigurationMethod = newLegacyUpdateConfigurationMethod(GovernanceABI)
 }
 
 // OracleContract represent special system contracts written in Go.
@@ -57,6 +61,7 @@ type OracleContractABI struct {
 	Name2Method map[string]abi.Method
 	Sig2Method  map[string]abi.Method
 	Events      map[string]abi.Event
+	ID2Event    map[common.Hash]abi.Event
 }
 
 // NewOracleContractABI parse the ABI.
@@ -75,8 +80,10 @@ func NewOracleContractABI(abiDefinition string) *OracleContractABI {
 	}
 
 	events := make(map[string]abi.Event)
+	id2Event := make(map[common.Hash]abi.Event)
 	for _, event := range abiObject.Events {
 		events[event.Name] = event
+		id2Event[event.Id()] = event
 	}
 
 	return &OracleContractABI{
@@ -84,5 +91,71 @@ func NewOracleContractABI(abiDefinition string) *OracleContractABI {
 		Name2Method: name2Method,
 		Sig2Method:  sig2Method,
 		Events:      events,
+		ID2Event:    id2Event,
 	}
 }
+
+// EventTopics builds the log filter topics matching any of the named events,
+// followed by one topic per given indexed argument. No names with indexed
+// arguments matches every event.
+func (a *OracleContractABI) EventTopics(names []string, indexed ...common.Hash) ([][]common.Hash, error) {
+	var topics [][]common.Hash
+	if len(names) > 0 || len(indexed) > 0 {
+		ids := make([]common.Hash, 0, len(names))
+		for _, name := range names {
+			event, ok := a.Events[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown event %q", name)
+			}
+			ids = append(ids, event.Id())
+		}
+		topics = append(topics, ids)
+	}
+	for _, arg := range indexed {
+		topics = append(topics, []common.Hash{arg})
+	}
+	return topics, nil
+}
+
+// DecodeEvent decodes the given log into its event and the event arguments
+// keyed by argument name. Indexed arguments are recovered from the topics,
+// the others from the log data.
+func (a *OracleContractABI) DecodeEvent(log *types.Log) (abi.Event, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return abi.Event{}, nil, errors.New("log without topics")
+	}
+	event, ok := a.ID2Event[log.Topics[0]]
+	if !ok {
+		return abi.Event{}, nil, fmt.Errorf("unknown event %x", log.Topics[0])
+	}
+
+	args := make(map[string]interface{}, len(event.Inputs))
+	topics := log.Topics[1:]
+	for _, input := range event.Inputs {
+		if !input.Indexed {
+			continue
+		}
+		if len(topics) == 0 {
+			return abi.Event{}, nil, fmt.Errorf("missing topic for %s.%s", event.Name, input.Name)
+		}
+		switch input.Type.T {
+		case abi.AddressTy:
+			args[input.Name] = common.BytesToAddress(topics[0].Bytes())
+		case abi.UintTy, abi.IntTy:
+			args[input.Name] = topics[0].Big()
+		default:
+			// Dynamic types are indexed by their hash.
+			args[input.Name] = topics[0]
+		}
+		topics = topics[1:]
+	}
+
+	values, err := event.Inputs.UnpackValues(log.Data)
+	if err != nil {
+		return abi.Event{}, nil, fmt.Errorf("failed to unpack %s: %v", event.Name, err)
+	}
+	for i, input := range event.Inputs.NonIndexed() {
+		args[input.Name] = values[i]
+	}
+	return event, args, nil
+}
@@ -97,6 +97,37 @@ const (
 	minBlockIntervalLoc
 	fineValuesLoc
 	finedRecordsLoc
+	delegatorsLoc
+	delegatorsOffsetLoc
+	delegatedLoc
+	commissionRatesLoc
+	rewardsLoc
+	livenessWindowLoc
+	livenessJailThresholdLoc
+	livenessRecordsLoc
+	jailedLoc
+	jailCountsLoc
+	p2pPublicKeysLoc
+	pendingCommissionRatesLoc
+	pendingCommissionRatesAtLoc
+)
+
+// CommissionRateBase is the denominator of node commission rates, i.e. the
+// commission rate is expressed in basis points.
+const CommissionRateBase = 10000
+
+// Liveness penalty defaults, used when not configured.
+const (
+	// DefaultLivenessWindow is the number of the latest rounds served in the
+	// DKG set a node's liveness is scored over.
+	DefaultLivenessWindow = 8
+
+	// DefaultLivenessJailThreshold is the number of missed rounds in the
+	// window a node is jailed at.
+	DefaultLivenessJailThreshold = 3
+
+	// maxLivenessWindow is the number of bits of a liveness record.
+	maxLivenessWindow = 256
 )
 
 func publicKeyToNodeKeyAddress(pkBytes []byte) (common.Address, error) {
@@ -478,6 +509,20 @@ func (s *GovernanceState) PopLastNode() {
 		UnstakedAt: big.NewInt(0),
 	})
 }
+func (s *GovernanceState) RemoveNode(offset *big.Int) {
+	node := s.Node(offset)
+	lastIndex := new(big.Int).Sub(s.LenNodes(), big.NewInt(1))
+
+	// Move the last node into the hole.
+	if offset.Cmp(lastIndex) != 0 {
+		lastNode := s.Node(lastIndex)
+		s.UpdateNode(offset, lastNode)
+		s.PutNodeOffsets(lastNode, offset)
+	}
+	s.DeleteNodeOffsets(node)
+	s.PopLastNode()
+	s.emitNodeRemoved(node.Owner)
+}
 func (s *GovernanceState) Nodes() []*nodeInfo {
 	var nodes []*nodeInfo
 	for i := int64(0); i < int64(s.LenNodes().Uint64()); i++ {
@@ -493,7 +538,16 @@ func (s *GovernanceState) QualifiedNodes() []*nodeInfo {
 		if node.Fined.Cmp(big.NewInt(0)) > 0 {
 			continue
 		}
-		if node.Staked.Cmp(s.MinStake()) >= 0 {
+		// Jailed node is unqualified until unjailed.
+		if s.Jailed(node.Owner) {
+			continue
+		}
+		// Node must keep some stake of its own to be backed by delegators.
+		if node.Staked.Cmp(big.NewInt(0)) == 0 {
+			continue
+		}
+		staked := new(big.Int).Add(node.Staked, s.Delegated(node.Owner))
+		if staked.Cmp(s.MinStake()) >= 0 {
 			nodes = append(nodes, node)
 		}
 	}
@@ -554,6 +608,413 @@ func (s *GovernanceState) GetNodeByID(id coreTypes.NodeID) (*nodeInfo, error) {
 	return node, nil
 }
 
+// struct Delegator {
+//     address owner;
+//     uint256 value;
+//     uint256 undelegated;
+//     uint256 undelegatedAt;
+// }
+//
+// mapping(address => Delegator[]) public delegators;
+
+type delegatorInfo struct {
+	Owner         common.Address
+	Value         *big.Int
+	Undelegated   *big.Int
+	UndelegatedAt *big.Int
+}
+
+const delegatorStructSize = 4
+
+func (s *GovernanceState) delegatorElementLoc(nodeAddr common.Address, index *big.Int) *big.Int {
+	arrayLoc := s.getMapLoc(big.NewInt(delegatorsLoc), nodeAddr.Bytes())
+	arrayBaseLoc := s.getSlotLoc(arrayLoc)
+	return new(big.Int).Add(arrayBaseLoc,
+		new(big.Int).Mul(index, big.NewInt(delegatorStructSize)))
+}
+func (s *GovernanceState) LenDelegators(nodeAddr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(delegatorsLoc), nodeAddr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) Delegator(nodeAddr common.Address, index *big.Int) *delegatorInfo {
+	delegator := new(delegatorInfo)
+	elementBaseLoc := s.delegatorElementLoc(nodeAddr, index)
+
+	// Owner.
+	loc := elementBaseLoc
+	delegator.Owner = common.BytesToAddress(s.getState(common.BigToHash(loc)).Bytes())
+
+	// Value.
+	loc = new(big.Int).Add(elementBaseLoc, big.NewInt(1))
+	delegator.Value = s.getStateBigInt(loc)
+
+	// Undelegated.
+	loc = new(big.Int).Add(elementBaseLoc, big.NewInt(2))
+	delegator.Undelegated = s.getStateBigInt(loc)
+
+	// UndelegatedAt.
+	loc = new(big.Int).Add(elementBaseLoc, big.NewInt(3))
+	delegator.UndelegatedAt = s.getStateBigInt(loc)
+
+	return delegator
+}
+func (s *GovernanceState) PushDelegator(nodeAddr common.Address, delegator *delegatorInfo) {
+	// Increase length by 1.
+	arrayLength := s.LenDelegators(nodeAddr)
+	loc := s.getMapLoc(big.NewInt(delegatorsLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, new(big.Int).Add(arrayLength, big.NewInt(1)))
+
+	s.UpdateDelegator(nodeAddr, arrayLength, delegator)
+}
+func (s *GovernanceState) UpdateDelegator(nodeAddr common.Address, index *big.Int, delegator *delegatorInfo) {
+	elementBaseLoc := s.delegatorElementLoc(nodeAddr, index)
+
+	// Owner.
+	loc := elementBaseLoc
+	s.setState(common.BigToHash(loc), delegator.Owner.Hash())
+
+	// Value.
+	loc = new(big.Int).Add(elementBaseLoc, big.NewInt(1))
+	s.setStateBigInt(loc, delegator.Value)
+
+	// Undelegated.
+	loc = new(big.Int).Add(elementBaseLoc, big.NewInt(2))
+	s.setStateBigInt(loc, delegator.Undelegated)
+
+	// UndelegatedAt.
+	loc = new(big.Int).Add(elementBaseLoc, big.NewInt(3))
+	s.setStateBigInt(loc, delegator.UndelegatedAt)
+}
+func (s *GovernanceState) PopLastDelegator(nodeAddr common.Address) {
+	// Decrease length by 1.
+	arrayLength := s.LenDelegators(nodeAddr)
+	newArrayLength := new(big.Int).Sub(arrayLength, big.NewInt(1))
+	loc := s.getMapLoc(big.NewInt(delegatorsLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, newArrayLength)
+
+	s.UpdateDelegator(nodeAddr, newArrayLength, &delegatorInfo{
+		Value:         big.NewInt(0),
+		Undelegated:   big.NewInt(0),
+		UndelegatedAt: big.NewInt(0),
+	})
+}
+func (s *GovernanceState) Delegators(nodeAddr common.Address) []*delegatorInfo {
+	var delegators []*delegatorInfo
+	for i := int64(0); i < int64(s.LenDelegators(nodeAddr).Uint64()); i++ {
+		delegators = append(delegators, s.Delegator(nodeAddr, big.NewInt(i)))
+	}
+	return delegators
+}
+
+// mapping(address => mapping(address => uint256)) public delegatorsOffset;
+func (s *GovernanceState) delegatorsOffsetLoc(nodeAddr, delegatorAddr common.Address) *big.Int {
+	nodeLoc := s.getMapLoc(big.NewInt(delegatorsOffsetLoc), nodeAddr.Bytes())
+	return s.getMapLoc(nodeLoc, delegatorAddr.Bytes())
+}
+func (s *GovernanceState) DelegatorsOffset(nodeAddr, delegatorAddr common.Address) *big.Int {
+	loc := s.delegatorsOffsetLoc(nodeAddr, delegatorAddr)
+	return new(big.Int).Sub(s.getStateBigInt(loc), big.NewInt(1))
+}
+func (s *GovernanceState) PutDelegatorsOffset(nodeAddr, delegatorAddr common.Address, offset *big.Int) {
+	loc := s.delegatorsOffsetLoc(nodeAddr, delegatorAddr)
+	s.setStateBigInt(loc, new(big.Int).Add(offset, big.NewInt(1)))
+}
+func (s *GovernanceState) DeleteDelegatorsOffset(nodeAddr, delegatorAddr common.Address) {
+	loc := s.delegatorsOffsetLoc(nodeAddr, delegatorAddr)
+	s.setStateBigInt(loc, big.NewInt(0))
+}
+
+// mapping(address => uint256) public delegated;
+func (s *GovernanceState) Delegated(nodeAddr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(delegatedLoc), nodeAddr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) IncDelegated(nodeAddr common.Address, amount *big.Int) {
+	loc := s.getMapLoc(big.NewInt(delegatedLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, new(big.Int).Add(s.getStateBigInt(loc), amount))
+}
+func (s *GovernanceState) DecDelegated(nodeAddr common.Address, amount *big.Int) {
+	loc := s.getMapLoc(big.NewInt(delegatedLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, new(big.Int).Sub(s.getStateBigInt(loc), amount))
+}
+
+// mapping(address => uint256) public commissionRates;
+func (s *GovernanceState) CommissionRate(nodeAddr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(commissionRatesLoc), nodeAddr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) SetCommissionRate(nodeAddr common.Address, rate *big.Int) {
+	loc := s.getMapLoc(big.NewInt(commissionRatesLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, rate)
+}
+
+// mapping(address => uint256) public pendingCommissionRates;
+func (s *GovernanceState) PendingCommissionRate(nodeAddr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(pendingCommissionRatesLoc), nodeAddr.Bytes())
+	return s.getStateBigInt(loc)
+}
+
+// mapping(address => uint256) public pendingCommissionRatesAt;
+func (s *GovernanceState) PendingCommissionRateAt(nodeAddr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(pendingCommissionRatesAtLoc), nodeAddr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) SetPendingCommissionRate(nodeAddr common.Address, rate, at *big.Int) {
+	loc := s.getMapLoc(big.NewInt(pendingCommissionRatesLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, rate)
+	loc = s.getMapLoc(big.NewInt(pendingCommissionRatesAtLoc), nodeAddr.Bytes())
+	s.setStateBigInt(loc, at)
+}
+
+// CommissionRateAt returns the commission rate of the node in effect at the
+// given time, including the pending change if it is due.
+func (s *GovernanceState) CommissionRateAt(nodeAddr common.Address, now *big.Int) *big.Int {
+	at := s.PendingCommissionRateAt(nodeAddr)
+	if at.Cmp(big.NewInt(0)) > 0 && now.Cmp(at) >= 0 {
+		return s.PendingCommissionRate(nodeAddr)
+	}
+	return s.CommissionRate(nodeAddr)
+}
+
+// ApplyPendingCommissionRate makes the pending commission rate change of the
+// node the commission rate if it is due at the given time.
+func (s *GovernanceState) ApplyPendingCommissionRate(nodeAddr common.Address, now *big.Int) {
+	at := s.PendingCommissionRateAt(nodeAddr)
+	if at.Cmp(big.NewInt(0)) == 0 || now.Cmp(at) < 0 {
+		return
+	}
+	s.SetCommissionRate(nodeAddr, s.PendingCommissionRate(nodeAddr))
+	s.SetPendingCommissionRate(nodeAddr, big.NewInt(0), big.NewInt(0))
+}
+
+// mapping(address => uint256) public rewards;
+func (s *GovernanceState) Reward(addr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(rewardsLoc), addr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) IncReward(addr common.Address, amount *big.Int) {
+	loc := s.getMapLoc(big.NewInt(rewardsLoc), addr.Bytes())
+	s.setStateBigInt(loc, new(big.Int).Add(s.getStateBigInt(loc), amount))
+}
+func (s *GovernanceState) ResetReward(addr common.Address) {
+	loc := s.getMapLoc(big.NewInt(rewardsLoc), addr.Bytes())
+	s.setStateBigInt(loc, big.NewInt(0))
+}
+
+// uint256 public livenessWindow;
+func (s *GovernanceState) LivenessWindow() *big.Int {
+	return s.getStateBigInt(big.NewInt(livenessWindowLoc))
+}
+
+// uint256 public livenessJailThreshold;
+func (s *GovernanceState) LivenessJailThreshold() *big.Int {
+	return s.getStateBigInt(big.NewInt(livenessJailThresholdLoc))
+}
+
+// mapping(address => uint256) public livenessRecords;
+func (s *GovernanceState) LivenessRecord(addr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(livenessRecordsLoc), addr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) SetLivenessRecord(addr common.Address, record *big.Int) {
+	loc := s.getMapLoc(big.NewInt(livenessRecordsLoc), addr.Bytes())
+	s.setStateBigInt(loc, record)
+}
+
+// mapping(address => bool) public jailed;
+func (s *GovernanceState) Jailed(addr common.Address) bool {
+	loc := s.getMapLoc(big.NewInt(jailedLoc), addr.Bytes())
+	return s.getStateBigInt(loc).Cmp(big.NewInt(0)) > 0
+}
+func (s *GovernanceState) SetJailed(addr common.Address, jailed bool) {
+	loc := s.getMapLoc(big.NewInt(jailedLoc), addr.Bytes())
+	value := int64(0)
+	if jailed {
+		value = int64(1)
+	}
+	s.setStateBigInt(loc, big.NewInt(value))
+}
+
+// mapping(address => uint256) public jailCounts;
+func (s *GovernanceState) JailCount(addr common.Address) *big.Int {
+	loc := s.getMapLoc(big.NewInt(jailCountsLoc), addr.Bytes())
+	return s.getStateBigInt(loc)
+}
+func (s *GovernanceState) SetJailCount(addr common.Address, count *big.Int) {
+	loc := s.getMapLoc(big.NewInt(jailCountsLoc), addr.Bytes())
+	s.setStateBigInt(loc, count)
+}
+
+// mapping(address => bytes) public p2pPublicKeys;
+func (s *GovernanceState) P2PPublicKey(addr common.Address) []byte {
+	loc := s.getMapLoc(big.NewInt(p2pPublicKeysLoc), addr.Bytes())
+	return s.readBytes(loc)
+}
+func (s *GovernanceState) SetP2PPublicKey(addr common.Address, pk []byte) {
+	loc := s.getMapLoc(big.NewInt(p2pPublicKeysLoc), addr.Bytes())
+	s.eraseBytes(loc)
+	s.writeBytes(loc, pk)
+}
+
+// livenessParams returns the liveness window and jail threshold in effect.
+func (s *GovernanceState) livenessParams() (window, threshold uint64) {
+	window = s.LivenessWindow().Uint64()
+	if window == 0 {
+		window = DefaultLivenessWindow
+	}
+	if window > maxLivenessWindow {
+		window = maxLivenessWindow
+	}
+	threshold = s.LivenessJailThreshold().Uint64()
+	if threshold == 0 {
+		threshold = DefaultLivenessJailThreshold
+	}
+	if threshold > window {
+		threshold = window
+	}
+	return
+}
+
+// RecordLiveness records whether the node was alive in the latest round it
+// served in the DKG set. The liveness record is a bitmap of the missed rounds
+// in the liveness window. Once the missed rounds reach the jail threshold, the
+// node is jailed and fined the fail stop fine multiplied by the number of
+// times it has been jailed. It returns whether the node is jailed.
+func (s *GovernanceState) RecordLiveness(n *nodeInfo, alive bool) (bool, error) {
+	nodeAddr, err := publicKeyToNodeKeyAddress(n.PublicKey)
+	if err != nil {
+		return false, err
+	}
+
+	// Node might already been unstaked in the latest state.
+	offset := s.NodesOffsetByNodeKeyAddress(nodeAddr)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return false, errors.New("node does not exist")
+	}
+	node := s.Node(offset)
+
+	// Node still serving the rounds configured before being jailed.
+	if s.Jailed(node.Owner) {
+		return true, nil
+	}
+
+	window, threshold := s.livenessParams()
+	record := new(big.Int).Lsh(s.LivenessRecord(node.Owner), 1)
+	if !alive {
+		record.SetBit(record, 0, 1)
+	}
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(window)), big.NewInt(1))
+	record.And(record, mask)
+
+	missed := uint64(0)
+	for i := 0; i < record.BitLen(); i++ {
+		missed += uint64(record.Bit(i))
+	}
+	if missed < threshold {
+		s.SetLivenessRecord(node.Owner, record)
+		return false, nil
+	}
+
+	// Jail the node and start over the liveness record.
+	count := new(big.Int).Add(s.JailCount(node.Owner), big.NewInt(1))
+	s.SetJailCount(node.Owner, count)
+	s.SetJailed(node.Owner, true)
+	s.SetLivenessRecord(node.Owner, big.NewInt(0))
+
+	s.FineNode(offset, new(big.Int).Mul(s.FineValue(big.NewInt(FineTypeFailStop)), count))
+	return true, nil
+}
+
+// TransferLiveness moves the liveness record, jail state and jail count of a
+// node to its new owner.
+func (s *GovernanceState) TransferLiveness(oldOwner, newOwner common.Address) {
+	s.SetLivenessRecord(newOwner, s.LivenessRecord(oldOwner))
+	s.SetLivenessRecord(oldOwner, big.NewInt(0))
+	s.SetJailed(newOwner, s.Jailed(oldOwner))
+	s.SetJailed(oldOwner, false)
+	s.SetJailCount(newOwner, s.JailCount(oldOwner))
+	s.SetJailCount(oldOwner, big.NewInt(0))
+}
+
+// DistributeBlockReward deposits the block reward of the node owned by
+// nodeAddr into the governance contract and credits it as claimable reward.
+// The node owner takes its commission first, the remainder is split pro rata
+// between the node's own stake and its delegators, with the rounding dust
+// going to the node owner. Reward of an unknown node goes to nodeAddr. The
+// pending commission rate change due at the block time is applied first.
+func (s *GovernanceState) DistributeBlockReward(nodeAddr common.Address, amount, now *big.Int) {
+	if amount.Cmp(big.NewInt(0)) == 0 {
+		return
+	}
+	s.StateDB.AddBalance(GovernanceContractAddress, amount)
+
+	offset := s.NodesOffsetByAddress(nodeAddr)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		s.IncReward(nodeAddr, amount)
+		return
+	}
+
+	node := s.Node(offset)
+	delegated := s.Delegated(nodeAddr)
+	total := new(big.Int).Add(node.Staked, delegated)
+
+	s.ApplyPendingCommissionRate(nodeAddr, now)
+	commission := new(big.Int).Mul(amount, s.CommissionRate(nodeAddr))
+	commission.Div(commission, big.NewInt(CommissionRateBase))
+	remainder := new(big.Int).Sub(amount, commission)
+
+	distributed := big.NewInt(0)
+	if delegated.Cmp(big.NewInt(0)) > 0 {
+		for _, delegator := range s.Delegators(nodeAddr) {
+			share := new(big.Int).Mul(remainder, delegator.Value)
+			share.Div(share, total)
+			if share.Cmp(big.NewInt(0)) == 0 {
+				continue
+			}
+			s.IncReward(delegator.Owner, share)
+			distributed.Add(distributed, share)
+		}
+	}
+	s.IncReward(nodeAddr, new(big.Int).Sub(amount, distributed))
+}
+
+// FineNode adds a fine to the node at the given offset. The fine is shared
+// pro rata between the node's own stake and its delegators: the delegators'
+// share is slashed from their delegation and paid to the governance owner,
+// while the node's share is recorded as unpaid fine. A node without
+// delegators bears the whole fine.
+func (s *GovernanceState) FineNode(offset *big.Int, amount *big.Int) {
+	node := s.Node(offset)
+	delegated := s.Delegated(node.Owner)
+	total := new(big.Int).Add(node.Staked, delegated)
+
+	slashed := big.NewInt(0)
+	if delegated.Cmp(big.NewInt(0)) > 0 {
+		for i, delegator := range s.Delegators(node.Owner) {
+			share := new(big.Int).Mul(amount, delegator.Value)
+			share.Div(share, total)
+			if share.Cmp(delegator.Value) > 0 {
+				share = new(big.Int).Set(delegator.Value)
+			}
+			if share.Cmp(big.NewInt(0)) == 0 {
+				continue
+			}
+			delegator.Value = new(big.Int).Sub(delegator.Value, share)
+			s.UpdateDelegator(node.Owner, big.NewInt(int64(i)), delegator)
+			slashed.Add(slashed, share)
+		}
+	}
+	if slashed.Cmp(big.NewInt(0)) > 0 {
+		s.DecDelegated(node.Owner, slashed)
+		s.DecTotalStaked(slashed)
+		s.StateDB.SubBalance(GovernanceContractAddress, slashed)
+		s.StateDB.AddBalance(s.Owner(), slashed)
+	}
+
+	node.Fined = new(big.Int).Add(node.Fined, new(big.Int).Sub(amount, slashed))
+	s.UpdateNode(offset, node)
+}
+
 // mapping(address => uint256) public lastProposedHeight;
 func (s *GovernanceState) LastProposedHeight(addr common.Address) *big.Int {
 	loc := s.getMapLoc(big.NewInt(lastProposedHeightLoc), addr.Bytes())
@@ -1011,10 +1472,7 @@ func (s *GovernanceState) Disqualify(n *nodeInfo) error {
 	}
 
 	// Set fined value.
-	node := s.Node(offset)
-	amount := s.FineValue(big.NewInt(FineTypeFailStop))
-	node.Fined = new(big.Int).Add(node.Fined, amount)
-	s.UpdateNode(offset, node)
+	s.FineNode(offset, s.FineValue(big.NewInt(FineTypeFailStop)))
 
 	return nil
 }
@@ -1039,6 +1497,9 @@ func (s *GovernanceState) Configuration() *params.DexconConfig {
 		RoundLength:       s.getStateBigInt(big.NewInt(roundLengthLoc)).Uint64(),
 		MinBlockInterval:  s.getStateBigInt(big.NewInt(minBlockIntervalLoc)).Uint64(),
 		FineValues:        s.FineValues(),
+
+		LivenessWindow:        s.LivenessWindow().Uint64(),
+		LivenessJailThreshold: s.LivenessJailThreshold().Uint64(),
 	}
 }
 
@@ -1059,6 +1520,8 @@ func (s *GovernanceState) UpdateConfiguration(cfg *params.DexconConfig) {
 	s.setStateBigInt(big.NewInt(roundLengthLoc), big.NewInt(int64(cfg.RoundLength)))
 	s.setStateBigInt(big.NewInt(minBlockIntervalLoc), big.NewInt(int64(cfg.MinBlockInterval)))
 	s.SetFineValues(cfg.FineValues)
+	s.setStateBigInt(big.NewInt(livenessWindowLoc), new(big.Int).SetUint64(cfg.LivenessWindow))
+	s.setStateBigInt(big.NewInt(livenessJailThresholdLoc), new(big.Int).SetUint64(cfg.LivenessJailThreshold))
 
 	// Calculate set size.
 	s.CalNotarySetSize()
@@ -1076,6 +1539,9 @@ type rawConfigStruct struct {
 	RoundLength      *big.Int
 	MinBlockInterval *big.Int
 	FineValues       []*big.Int
+
+	LivenessWindow        *big.Int
+	LivenessJailThreshold *big.Int
 }
 
 // UpdateConfigurationRaw updates system configuration.
@@ -1092,6 +1558,12 @@ func (s *GovernanceState) UpdateConfigurationRaw(cfg *rawConfigStruct) {
 	s.setStateBigInt(big.NewInt(minBlockIntervalLoc), cfg.MinBlockInterval)
 	s.SetFineValues(cfg.FineValues)
 
+	// The liveness parameters are absent in the legacy form.
+	if cfg.LivenessWindow != nil && cfg.LivenessJailThreshold != nil {
+		s.setStateBigInt(big.NewInt(livenessWindowLoc), cfg.LivenessWindow)
+		s.setStateBigInt(big.NewInt(livenessJailThresholdLoc), cfg.LivenessJailThreshold)
+	}
+
 	// Calculate set size.
 	s.CalNotarySetSize()
 }
@@ -1246,6 +1718,88 @@ func (s *GovernanceState) emitDKGReset(round *big.Int, blockHeight *big.Int) {
 	})
 }
 
+// event Delegated(address indexed NodeAddress, address indexed DelegatorAddress, uint256 Amount);
+func (s *GovernanceState) emitDelegated(nodeAddr, delegatorAddr common.Address, amount *big.Int) {
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics: []common.Hash{GovernanceABI.Events["Delegated"].Id(),
+			nodeAddr.Hash(), delegatorAddr.Hash()},
+		Data: common.BigToHash(amount).Bytes(),
+	})
+}
+
+// event Undelegated(address indexed NodeAddress, address indexed DelegatorAddress, uint256 Amount);
+func (s *GovernanceState) emitUndelegated(nodeAddr, delegatorAddr common.Address, amount *big.Int) {
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics: []common.Hash{GovernanceABI.Events["Undelegated"].Id(),
+			nodeAddr.Hash(), delegatorAddr.Hash()},
+		Data: common.BigToHash(amount).Bytes(),
+	})
+}
+
+// event DelegationWithdrawn(address indexed NodeAddress, address indexed DelegatorAddress, uint256 Amount);
+func (s *GovernanceState) emitDelegationWithdrawn(nodeAddr, delegatorAddr common.Address, amount *big.Int) {
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics: []common.Hash{GovernanceABI.Events["DelegationWithdrawn"].Id(),
+			nodeAddr.Hash(), delegatorAddr.Hash()},
+		Data: common.BigToHash(amount).Bytes(),
+	})
+}
+
+// event CommissionRateChangePending(address indexed NodeAddress, uint256 Rate, uint256 EffectiveAt);
+func (s *GovernanceState) emitCommissionRateChangePending(nodeAddr common.Address, rate, effectiveAt *big.Int) {
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics:  []common.Hash{GovernanceABI.Events["CommissionRateChangePending"].Id(), nodeAddr.Hash()},
+		Data:    append(common.BigToHash(rate).Bytes(), common.BigToHash(effectiveAt).Bytes()...),
+	})
+}
+
+// event RewardClaimed(address indexed Owner, uint256 Amount);
+func (s *GovernanceState) emitRewardClaimed(owner common.Address, amount *big.Int) {
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics:  []common.Hash{GovernanceABI.Events["RewardClaimed"].Id(), owner.Hash()},
+		Data:    common.BigToHash(amount).Bytes(),
+	})
+}
+
+// event Unjailed(address indexed NodeAddress);
+func (s *GovernanceState) emitUnjailed(nodeAddr common.Address) {
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics:  []common.Hash{GovernanceABI.Events["Unjailed"].Id(), nodeAddr.Hash()},
+		Data:    []byte{},
+	})
+}
+
+func (s *GovernanceState) emitP2PPublicKeyChanged(nodeAddr common.Address, pk []byte) {
+	t, err := abi.NewType("bytes", nil)
+	if err != nil {
+		panic(err)
+	}
+
+	arg := abi.Arguments{
+		abi.Argument{
+			Name:    "PublicKey",
+			Type:    t,
+			Indexed: false,
+		},
+	}
+
+	data, err := arg.Pack(pk)
+	if err != nil {
+		panic(err)
+	}
+	s.StateDB.AddLog(&types.Log{
+		Address: GovernanceContractAddress,
+		Topics:  []common.Hash{GovernanceABI.Events["P2PPublicKeyChanged"].Id(), nodeAddr.Hash()},
+		Data:    data,
+	})
+}
+
 func getRoundState(evm *EVM, round *big.Int) (*GovernanceState, error) {
 	gs := &GovernanceState{evm.StateDB}
 	height := gs.RoundHeight(round).Uint64()
@@ -1443,14 +1997,33 @@ func (g *GovernanceContract) fineFailStopDKG(threshold int) {
 
 		node := g.state.Node(offset)
 		amount := g.state.FineValue(big.NewInt(FineTypeFailStopDKG))
-		node.Fined = new(big.Int).Add(node.Fined, amount)
-		g.state.UpdateNode(offset, node)
+		g.state.FineNode(offset, amount)
 		g.state.emitFined(node.Owner, amount)
 	}
 }
 
+func (g *GovernanceContract) nodeKeySeparationEnabled() bool {
+	return g.evm.ChainConfig().IsNodeKeySeparation(g.evm.BlockNumber)
+}
+
+// dkgParticipant returns the node key address a DKG message is accounted to.
+// Before the node key separation fork it is the transaction sender. After the
+// fork it is the signer of the message, so that the message can be relayed by
+// an operator account other than the node key.
+func (g *GovernanceContract) dkgParticipant(proposerID coreTypes.NodeID) common.Address {
+	if g.nodeKeySeparationEnabled() {
+		return IdToAddress(proposerID)
+	}
+	return g.contract.Caller()
+}
+
 func (g *GovernanceContract) addDKGComplaint(comp []byte) ([]byte, error) {
-	caller := g.contract.Caller()
+	var dkgComplaint dkgTypes.Complaint
+	if err := rlp.DecodeBytes(comp, &dkgComplaint); err != nil {
+		return nil, errExecutionReverted
+	}
+
+	caller := g.dkgParticipant(dkgComplaint.ProposerID)
 	offset := g.state.NodesOffsetByNodeKeyAddress(caller)
 
 	// Can not add complaint if caller does not exists.
@@ -1471,11 +2044,6 @@ func (g *GovernanceContract) addDKGComplaint(comp []byte) ([]byte, error) {
 		return nil, errExecutionReverted
 	}
 
-	var dkgComplaint dkgTypes.Complaint
-	if err := rlp.DecodeBytes(comp, &dkgComplaint); err != nil {
-		return nil, errExecutionReverted
-	}
-
 	if g.state.DKGComplaintProposed(getDKGComplaintID(&dkgComplaint)) {
 		return nil, errExecutionReverted
 	}
@@ -1550,7 +2118,7 @@ func (g *GovernanceContract) addDKGMasterPublicKey(mpk []byte) ([]byte, error) {
 		return nil, errExecutionReverted
 	}
 
-	caller := g.contract.Caller()
+	caller := g.dkgParticipant(dkgMasterPK.ProposerID)
 	offset := g.state.NodesOffsetByNodeKeyAddress(caller)
 
 	// Can not add dkg mpk if not staked.
@@ -1593,12 +2161,11 @@ func (g *GovernanceContract) addDKGMasterPublicKey(mpk []byte) ([]byte, error) {
 }
 
 func (g *GovernanceContract) addDKGMPKReady(ready []byte) ([]byte, error) {
-	caller := g.contract.Caller()
-
 	var dkgReady dkgTypes.MPKReady
 	if err := rlp.DecodeBytes(ready, &dkgReady); err != nil {
 		return nil, errExecutionReverted
 	}
+	caller := g.dkgParticipant(dkgReady.ProposerID)
 	round := big.NewInt(int64(dkgReady.Round))
 	if round.Uint64() != g.evm.Round.Uint64()+1 {
 		return nil, errExecutionReverted
@@ -1627,12 +2194,11 @@ func (g *GovernanceContract) addDKGMPKReady(ready []byte) ([]byte, error) {
 }
 
 func (g *GovernanceContract) addDKGFinalize(finalize []byte) ([]byte, error) {
-	caller := g.contract.Caller()
-
 	var dkgFinalize dkgTypes.Finalize
 	if err := rlp.DecodeBytes(finalize, &dkgFinalize); err != nil {
 		return nil, errExecutionReverted
 	}
+	caller := g.dkgParticipant(dkgFinalize.ProposerID)
 	round := big.NewInt(int64(dkgFinalize.Round))
 	if round.Uint64() != g.evm.Round.Uint64()+1 {
 		return nil, errExecutionReverted
@@ -1669,12 +2235,11 @@ func (g *GovernanceContract) addDKGFinalize(finalize []byte) ([]byte, error) {
 }
 
 func (g *GovernanceContract) addDKGSuccess(success []byte) ([]byte, error) {
-	caller := g.contract.Caller()
-
 	var dkgSuccess dkgTypes.Success
 	if err := rlp.DecodeBytes(success, &dkgSuccess); err != nil {
 		return nil, errExecutionReverted
 	}
+	caller := g.dkgParticipant(dkgSuccess.ProposerID)
 	round := big.NewInt(int64(dkgSuccess.Round))
 	if round.Uint64() != g.evm.Round.Uint64()+1 {
 		return nil, errExecutionReverted
@@ -1719,6 +2284,14 @@ func (g *GovernanceContract) updateConfiguration(cfg *rawConfigStruct) ([]byte,
 		cfg.MinBlockInterval.Cmp(big.NewInt(0)) <= 0 {
 		return nil, errExecutionReverted
 	}
+	if cfg.LivenessWindow != nil && cfg.LivenessJailThreshold != nil {
+		if cfg.LivenessWindow.Sign() <= 0 ||
+			cfg.LivenessWindow.Cmp(big.NewInt(maxLivenessWindow)) > 0 ||
+			cfg.LivenessJailThreshold.Sign() <= 0 ||
+			cfg.LivenessJailThreshold.Cmp(cfg.LivenessWindow) > 0 {
+			return nil, errExecutionReverted
+		}
+	}
 
 	g.state.UpdateConfigurationRaw(cfg)
 	g.state.emitConfigurationChangedEvent()
@@ -1858,19 +2431,10 @@ func (g *GovernanceContract) withdraw() ([]byte, error) {
 	node.UnstakedAt = big.NewInt(0)
 	g.state.UpdateNode(offset, node)
 
-	if node.Staked.Cmp(big.NewInt(0)) == 0 {
-		length := g.state.LenNodes()
-		lastIndex := new(big.Int).Sub(length, big.NewInt(1))
-
-		// Delete the node.
-		if offset.Cmp(lastIndex) != 0 {
-			lastNode := g.state.Node(lastIndex)
-			g.state.UpdateNode(offset, lastNode)
-			g.state.PutNodeOffsets(lastNode, offset)
-		}
-		g.state.DeleteNodeOffsets(node)
-		g.state.PopLastNode()
-		g.state.emitNodeRemoved(caller)
+	// Node with delegators is kept so the delegations can be withdrawn.
+	if node.Staked.Cmp(big.NewInt(0)) == 0 &&
+		g.state.LenDelegators(caller).Cmp(big.NewInt(0)) == 0 {
+		g.state.RemoveNode(offset)
 	}
 
 	// Return the staked fund.
@@ -1906,7 +2470,250 @@ func (g *GovernanceContract) withdrawable() bool {
 	return g.evm.Time.Cmp(unlockTime) > 0
 }
 
-func (g *GovernanceContract) payFine(nodeAddr common.Address) ([]byte, error) {
+func (g *GovernanceContract) delegationEnabled() bool {
+	return g.evm.ChainConfig().IsDelegation(g.evm.BlockNumber)
+}
+
+func (g *GovernanceContract) delegate(nodeAddr common.Address) ([]byte, error) {
+	caller := g.contract.Caller()
+	value := g.contract.Value()
+
+	if big.NewInt(0).Cmp(value) == 0 {
+		return nil, errExecutionReverted
+	}
+
+	// Node owner should stake instead.
+	if caller == nodeAddr {
+		return nil, errExecutionReverted
+	}
+
+	offset := g.state.NodesOffsetByAddress(nodeAddr)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	// Can not delegate to node with unpaid fine.
+	node := g.state.Node(offset)
+	if node.Fined.Cmp(big.NewInt(0)) > 0 {
+		return nil, errExecutionReverted
+	}
+
+	delegatorOffset := g.state.DelegatorsOffset(nodeAddr, caller)
+	if delegatorOffset.Cmp(big.NewInt(0)) < 0 {
+		delegatorOffset = g.state.LenDelegators(nodeAddr)
+		g.state.PushDelegator(nodeAddr, &delegatorInfo{
+			Owner:         caller,
+			Value:         value,
+			Undelegated:   big.NewInt(0),
+			UndelegatedAt: big.NewInt(0),
+		})
+		g.state.PutDelegatorsOffset(nodeAddr, caller, delegatorOffset)
+	} else {
+		delegator := g.state.Delegator(nodeAddr, delegatorOffset)
+		delegator.Value = new(big.Int).Add(delegator.Value, value)
+		g.state.UpdateDelegator(nodeAddr, delegatorOffset, delegator)
+	}
+
+	g.state.IncDelegated(nodeAddr, value)
+	g.state.IncTotalStaked(value)
+	g.state.emitDelegated(nodeAddr, caller, value)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) undelegate(nodeAddr common.Address, amount *big.Int) ([]byte, error) {
+	caller := g.contract.Caller()
+
+	offset := g.state.DelegatorsOffset(nodeAddr, caller)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	delegator := g.state.Delegator(nodeAddr, offset)
+
+	// Can not undelegate if there are unwithdrawn delegation.
+	if delegator.Undelegated.Cmp(big.NewInt(0)) > 0 {
+		return nil, errExecutionReverted
+	}
+	if amount.Cmp(big.NewInt(0)) <= 0 || delegator.Value.Cmp(amount) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	delegator.Value = new(big.Int).Sub(delegator.Value, amount)
+	delegator.Undelegated = amount
+	delegator.UndelegatedAt = g.evm.Time
+	g.state.UpdateDelegator(nodeAddr, offset, delegator)
+
+	g.state.DecDelegated(nodeAddr, amount)
+	g.state.DecTotalStaked(amount)
+	g.state.emitUndelegated(nodeAddr, caller, amount)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) withdrawDelegation(nodeAddr common.Address) ([]byte, error) {
+	caller := g.contract.Caller()
+
+	offset := g.state.DelegatorsOffset(nodeAddr, caller)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	delegator := g.state.Delegator(nodeAddr, offset)
+
+	// Can not withdraw if there are no pending withdrawal or the delegation
+	// is still locked.
+	if delegator.Undelegated.Cmp(big.NewInt(0)) == 0 {
+		return nil, errExecutionReverted
+	}
+	unlockTime := new(big.Int).Add(delegator.UndelegatedAt, g.state.LockupPeriod())
+	if g.evm.Time.Cmp(unlockTime) <= 0 {
+		return nil, errExecutionReverted
+	}
+
+	amount := delegator.Undelegated
+	delegator.Undelegated = big.NewInt(0)
+	delegator.UndelegatedAt = big.NewInt(0)
+	g.state.UpdateDelegator(nodeAddr, offset, delegator)
+
+	if delegator.Value.Cmp(big.NewInt(0)) == 0 {
+		length := g.state.LenDelegators(nodeAddr)
+		lastIndex := new(big.Int).Sub(length, big.NewInt(1))
+
+		// Delete the delegator.
+		if offset.Cmp(lastIndex) != 0 {
+			lastDelegator := g.state.Delegator(nodeAddr, lastIndex)
+			g.state.UpdateDelegator(nodeAddr, offset, lastDelegator)
+			g.state.PutDelegatorsOffset(nodeAddr, lastDelegator.Owner, offset)
+		}
+		g.state.DeleteDelegatorsOffset(nodeAddr, caller)
+		g.state.PopLastDelegator(nodeAddr)
+
+		// Remove the node kept for its delegators once the last delegation
+		// is withdrawn and the node has withdrawn all of its own stake.
+		if g.state.LenDelegators(nodeAddr).Cmp(big.NewInt(0)) == 0 {
+			nodeOffset := g.state.NodesOffsetByAddress(nodeAddr)
+			if nodeOffset.Cmp(big.NewInt(0)) >= 0 {
+				node := g.state.Node(nodeOffset)
+				if node.Staked.Cmp(big.NewInt(0)) == 0 &&
+					node.Unstaked.Cmp(big.NewInt(0)) == 0 &&
+					node.Fined.Cmp(big.NewInt(0)) == 0 {
+					g.state.RemoveNode(nodeOffset)
+				}
+			}
+		}
+	}
+
+	// Return the delegated fund.
+	if !g.transfer(GovernanceContractAddress, caller, amount) {
+		return nil, errExecutionReverted
+	}
+	g.state.emitDelegationWithdrawn(nodeAddr, caller, amount)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) rewardDistributionEnabled() bool {
+	return g.evm.ChainConfig().IsRewardDistribution(g.evm.BlockNumber)
+}
+
+func (g *GovernanceContract) setCommissionRate(rate *big.Int) ([]byte, error) {
+	caller := g.contract.Caller()
+
+	offset := g.state.NodesOffsetByAddress(caller)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	if rate.Cmp(big.NewInt(0)) < 0 || rate.Cmp(big.NewInt(CommissionRateBase)) > 0 {
+		return nil, errExecutionReverted
+	}
+
+	// The change takes effect after the lockup period, giving the delegators
+	// the time to undelegate.
+	g.state.ApplyPendingCommissionRate(caller, g.evm.Time)
+	effectiveAt := new(big.Int).Add(g.evm.Time, g.state.LockupPeriod())
+	g.state.SetPendingCommissionRate(caller, rate, effectiveAt)
+	g.state.emitCommissionRateChangePending(caller, rate, effectiveAt)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) claimReward() ([]byte, error) {
+	caller := g.contract.Caller()
+
+	amount := g.state.Reward(caller)
+	if amount.Cmp(big.NewInt(0)) == 0 {
+		return nil, errExecutionReverted
+	}
+
+	g.state.ResetReward(caller)
+
+	if !g.transfer(GovernanceContractAddress, caller, amount) {
+		return nil, errExecutionReverted
+	}
+	g.state.emitRewardClaimed(caller, amount)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) livenessPenaltyEnabled() bool {
+	return g.evm.ChainConfig().IsLivenessPenalty(g.evm.BlockNumber)
+}
+
+func (g *GovernanceContract) unjail() ([]byte, error) {
+	caller := g.contract.Caller()
+
+	offset := g.state.NodesOffsetByAddress(caller)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	if !g.state.Jailed(caller) {
+		return nil, errExecutionReverted
+	}
+
+	// Can not unjail if there are unpaid fine.
+	node := g.state.Node(offset)
+	if node.Fined.Cmp(big.NewInt(0)) > 0 {
+		return nil, errExecutionReverted
+	}
+
+	g.state.SetJailed(caller, false)
+	g.state.SetLivenessRecord(caller, big.NewInt(0))
+	g.state.emitUnjailed(caller)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) setP2PPublicKey(publicKey []byte) ([]byte, error) {
+	caller := g.contract.Caller()
+
+	offset := g.state.NodesOffsetByAddress(caller)
+	if offset.Cmp(big.NewInt(0)) < 0 {
+		return nil, errExecutionReverted
+	}
+
+	// Empty public key falls back to the node public key.
+	if len(publicKey) > 0 {
+		if _, err := publicKeyToNodeKeyAddress(publicKey); err != nil {
+			return nil, errExecutionReverted
+		}
+	}
+
+	node := g.state.Node(offset)
+	nodeKeyAddr, err := publicKeyToNodeKeyAddress(node.PublicKey)
+	if err != nil {
+		return nil, errExecutionReverted
+	}
+
+	g.state.SetP2PPublicKey(nodeKeyAddr, publicKey)
+	g.state.emitP2PPublicKeyChanged(caller, publicKey)
+
+	return g.useGas(GovernanceActionGasCost)
+}
+
+func (g *GovernanceContract) payFine(nodeAddr common.Address) ([]byte, error) {
 	nodeOffset := g.state.NodesOffsetByAddress(nodeAddr)
 	if nodeOffset.Cmp(big.NewInt(0)) < 0 {
 		return nil, errExecutionReverted
@@ -1996,9 +2803,7 @@ func (g *GovernanceContract) fine(nodeAddr common.Address, amount *big.Int, payl
 	}
 
 	// Set fined value.
-	node := g.state.Node(nodeOffset)
-	node.Fined = new(big.Int).Add(node.Fined, amount)
-	g.state.UpdateNode(nodeOffset, node)
+	g.state.FineNode(nodeOffset, amount)
 
 	g.state.emitFined(nodeAddr, amount)
 
@@ -2186,6 +2991,53 @@ func (g *GovernanceContract) resetDKG(newSignedCRS []byte) ([]byte, error) {
 	return nil, nil
 }
 
+// delegationMethods are the methods introduced by the delegation fork.
+var delegationMethods = map[string]bool{
+	"delegate":           true,
+	"delegated":          true,
+	"delegators":         true,
+	"delegatorsLength":   true,
+	"delegatorsOffset":   true,
+	"undelegate":         true,
+	"withdrawDelegation": true,
+}
+
+// rewardDistributionMethods are the methods introduced by the reward
+// distribution fork.
+var rewardDistributionMethods = map[string]bool{
+	"claimReward":            true,
+	"commissionRates":        true,
+	"pendingCommissionRates": true,
+	"rewards":                true,
+	"setCommissionRate":      true,
+}
+
+// livenessPenaltyMethods are the methods introduced by the liveness penalty
+// fork.
+var livenessPenaltyMethods = map[string]bool{
+	"jailCounts":      true,
+	"jailed":          true,
+	"livenessRecords": true,
+	"unjail":          true,
+}
+
+// nodeKeySeparationMethods are the methods introduced by the node key
+// separation fork.
+var nodeKeySeparationMethods = map[string]bool{
+	"p2pPublicKeys":   true,
+	"setP2PPublicKey": true,
+}
+
+// legacyUpdateConfigurationMethod is updateConfiguration without the liveness
+// parameters, the only form accepted before the liveness penalty fork.
+var legacyUpdateConfigurationMethod abi.Method
+
+func newLegacyUpdateConfigurationMethod(a *OracleContractABI) abi.Method {
+	method := a.Name2Method["updateConfiguration"]
+	method.Inputs = method.Inputs[:len(method.Inputs)-2]
+	return method
+}
+
 // Run executes governance contract.
 func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (ret []byte, err error) {
 	if len(input) < 4 {
@@ -2199,12 +3051,42 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 
 	// Parse input.
 	method, exists := GovernanceABI.Sig2Method[string(input[:4])]
+	legacy := !exists && bytes.Equal(input[:4], legacyUpdateConfigurationMethod.Id())
+	if legacy {
+		method, exists = legacyUpdateConfigurationMethod, true
+	}
 	if !exists {
 		return nil, errExecutionReverted
 	}
 
 	arguments := input[4:]
 
+	// Delegation methods are not available before the delegation fork.
+	if delegationMethods[method.Name] && !g.delegationEnabled() {
+		return nil, errExecutionReverted
+	}
+
+	// Reward methods are not available before the reward distribution fork.
+	if rewardDistributionMethods[method.Name] && !g.rewardDistributionEnabled() {
+		return nil, errExecutionReverted
+	}
+
+	// Jail methods are not available before the liveness penalty fork.
+	if livenessPenaltyMethods[method.Name] && !g.livenessPenaltyEnabled() {
+		return nil, errExecutionReverted
+	}
+
+	// updateConfiguration takes the liveness parameters only after the
+	// liveness penalty fork, and requires them from then on.
+	if method.Name == "updateConfiguration" && legacy == g.livenessPenaltyEnabled() {
+		return nil, errExecutionReverted
+	}
+
+	// P2P key methods are not available before the node key separation fork.
+	if nodeKeySeparationMethods[method.Name] && !g.nodeKeySeparationEnabled() {
+		return nil, errExecutionReverted
+	}
+
 	// Dispatch method call.
 	switch method.Name {
 	case "addDKGComplaint":
@@ -2288,6 +3170,26 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return g.register(args.PublicKey, args.Name, args.Email, args.Location, args.Url)
+	case "claimReward":
+		return g.claimReward()
+	case "delegate":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		return g.delegate(address)
+	case "setCommissionRate":
+		rate := new(big.Int)
+		if err := method.Inputs.Unpack(&rate, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		return g.setCommissionRate(rate)
+	case "setP2PPublicKey":
+		var publicKey []byte
+		if err := method.Inputs.Unpack(&publicKey, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		return g.setP2PPublicKey(publicKey)
 	case "stake":
 		return g.stake()
 	case "transferOwnership":
@@ -2311,6 +3213,17 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return g.transferNodeOwnershipByFoundation(args.OldOwner, args.NewOwner)
+	case "unjail":
+		return g.unjail()
+	case "undelegate":
+		args := struct {
+			NodeAddress common.Address
+			Amount      *big.Int
+		}{}
+		if err := method.Inputs.Unpack(&args, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		return g.undelegate(args.NodeAddress, args.Amount)
 	case "unstake":
 		amount := new(big.Int)
 		if err := method.Inputs.Unpack(&amount, arguments); err != nil {
@@ -2325,6 +3238,12 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 		return g.updateConfiguration(&cfg)
 	case "withdraw":
 		return g.withdraw()
+	case "withdrawDelegation":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		return g.withdrawDelegation(address)
 	case "withdrawable":
 		res, err := method.Outputs.Pack(g.withdrawable())
 		if err != nil {
@@ -2342,6 +3261,32 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return res, nil
+	case "commissionRates":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.CommissionRateAt(address, g.evm.Time))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
+	case "pendingCommissionRates":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		rate := g.state.PendingCommissionRate(address)
+		effectiveAt := g.state.PendingCommissionRateAt(address)
+		if effectiveAt.Cmp(big.NewInt(0)) > 0 && g.evm.Time.Cmp(effectiveAt) >= 0 {
+			// The change is in effect already.
+			rate, effectiveAt = big.NewInt(0), big.NewInt(0)
+		}
+		res, err := method.Outputs.Pack(rate, effectiveAt)
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
 	case "crs":
 		res, err := method.Outputs.Pack(g.state.CRS())
 		if err != nil {
@@ -2354,6 +3299,54 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return res, nil
+	case "delegated":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.Delegated(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
+	case "delegators":
+		args := struct {
+			NodeAddress common.Address
+			Index       *big.Int
+		}{}
+		if err := method.Inputs.Unpack(&args, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		delegator := g.state.Delegator(args.NodeAddress, args.Index)
+		res, err := method.Outputs.Pack(delegator.Owner, delegator.Value,
+			delegator.Undelegated, delegator.UndelegatedAt)
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
+	case "delegatorsLength":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.LenDelegators(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
+	case "delegatorsOffset":
+		args := struct {
+			NodeAddress      common.Address
+			DelegatorAddress common.Address
+		}{}
+		if err := method.Inputs.Unpack(&args, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.DelegatorsOffset(args.NodeAddress, args.DelegatorAddress))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
 	case "dkgComplaints":
 		offset := new(big.Int)
 		if err := method.Inputs.Unpack(&offset, arguments); err != nil {
@@ -2490,6 +3483,26 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return res, nil
+	case "jailCounts":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.JailCount(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
+	case "jailed":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.Jailed(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
 	case "lambdaBA":
 		res, err := method.Outputs.Pack(g.state.LambdaBA())
 		if err != nil {
@@ -2518,6 +3531,16 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return res, nil
+	case "livenessRecords":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.LivenessRecord(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
 	case "lockupPeriod":
 		res, err := method.Outputs.Pack(g.state.LockupPeriod())
 		if err != nil {
@@ -2612,12 +3635,32 @@ func (g *GovernanceContract) Run(evm *EVM, input []byte, contract *Contract) (re
 			return nil, errExecutionReverted
 		}
 		return res, nil
+	case "p2pPublicKeys":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.P2PPublicKey(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
 	case "replaceNodePublicKey":
 		var pk []byte
 		if err := method.Inputs.Unpack(&pk, arguments); err != nil {
 			return nil, errExecutionReverted
 		}
 		return g.replaceNodePublicKey(pk)
+	case "rewards":
+		address := common.Address{}
+		if err := method.Inputs.Unpack(&address, arguments); err != nil {
+			return nil, errExecutionReverted
+		}
+		res, err := method.Outputs.Pack(g.state.Reward(address))
+		if err != nil {
+			return nil, errExecutionReverted
+		}
+		return res, nil
 	case "roundHeight":
 		round := new(big.Int)
 		if err := method.Inputs.Unpack(&round, arguments); err != nil {
@@ -2678,12 +3721,18 @@ func (g *GovernanceContract) transferNodeOwnership(newOwner common.Address) ([]b
 		return nil, errExecutionReverted
 	}
 
+	// Delegations are keyed by node owner.
+	if g.state.LenDelegators(caller).Cmp(big.NewInt(0)) > 0 {
+		return nil, errExecutionReverted
+	}
+
 	node := g.state.Node(offset)
 	g.state.DeleteNodeOffsets(node)
 
 	node.Owner = newOwner
 	g.state.PutNodeOffsets(node, offset)
 	g.state.UpdateNode(offset, node)
+	g.state.TransferLiveness(caller, newOwner)
 
 	g.state.emitNodeOwnershipTransfered(caller, newOwner)
 
@@ -2710,12 +3759,18 @@ func (g *GovernanceContract) transferNodeOwnershipByFoundation(oldOwner, newOwne
 		return nil, errExecutionReverted
 	}
 
+	// Delegations are keyed by node owner.
+	if g.state.LenDelegators(oldOwner).Cmp(big.NewInt(0)) > 0 {
+		return nil, errExecutionReverted
+	}
+
 	node := g.state.Node(offset)
 	g.state.DeleteNodeOffsets(node)
 
 	node.Owner = newOwner
 	g.state.PutNodeOffsets(node, offset)
 	g.state.UpdateNode(offset, node)
+	g.state.TransferLiveness(oldOwner, newOwner)
 
 	g.state.emitNodeOwnershipTransfered(oldOwner, newOwner)
 
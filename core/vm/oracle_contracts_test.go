@@ -36,6 +36,7 @@ import (
 
 	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/core/types"
 	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/params"
@@ -164,11 +165,12 @@ func TestGovernanceState(t *testing.T) {
 type OracleContractsTestSuite struct {
 	suite.Suite
 
-	context Context
-	config  *params.DexconConfig
-	memDB   *ethdb.MemDatabase
-	stateDB *state.StateDB
-	s       *GovernanceState
+	context     Context
+	config      *params.DexconConfig
+	chainConfig *params.ChainConfig
+	memDB       *ethdb.MemDatabase
+	stateDB     *state.StateDB
+	s           *GovernanceState
 }
 
 func (g *OracleContractsTestSuite) SetupTest() {
@@ -189,6 +191,7 @@ func (g *OracleContractsTestSuite) SetupTest() {
 	config.NotarySetSize = 7
 
 	g.config = config
+	g.chainConfig = params.TestChainConfig
 
 	// Give governance contract balance so it will not be deleted because of being an empty state object.
 	stateDB.AddBalance(GovernanceContractAddress, big.NewInt(1))
@@ -248,7 +251,7 @@ func (g *OracleContractsTestSuite) call(
 
 	g.context.Time = big.NewInt(time.Now().UnixNano() / 1000000)
 
-	evm := NewEVM(g.context, g.stateDB, params.TestChainConfig, Config{IsBlockProposer: true})
+	evm := NewEVM(g.context, g.stateDB, g.chainConfig, Config{IsBlockProposer: true})
 	ret, _, err := evm.Call(AccountRef(caller), contractAddr, input, 10000000, value)
 	return ret, err
 }
@@ -565,6 +568,454 @@ func (g *OracleContractsTestSuite) TestStakingMechanism() {
 	g.Require().Equal(big.NewInt(1), g.stateDB.GetBalance(GovernanceContractAddress))
 }
 
+func (g *OracleContractsTestSuite) TestDelegation() {
+	privKey, addr := newPrefundAccount(g.stateDB)
+	pk := crypto.FromECDSAPub(&privKey.PublicKey)
+	_, delegatorAddr := newPrefundAccount(g.stateDB)
+	_, delegatorAddr2 := newPrefundAccount(g.stateDB)
+	balanceBeforeDelegate := g.stateDB.GetBalance(delegatorAddr)
+
+	// Register with half of the minimum stake.
+	amount := new(big.Int).Div(g.s.MinStake(), big.NewInt(2))
+	input, err := GovernanceABI.ABI.Pack("register", pk, "Test1", "test1@dexon.org", "Taipei", "https://dexon.org")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, amount)
+	g.Require().NoError(err)
+	g.Require().Equal(0, len(g.s.QualifiedNodes()))
+
+	// Delegation is not available before the fork.
+	chainConfig := *params.TestChainConfig
+	chainConfig.DelegationBlock = big.NewInt(1)
+	g.chainConfig = &chainConfig
+	input, err = GovernanceABI.ABI.Pack("delegate", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, amount)
+	g.Require().Error(err)
+	g.chainConfig = params.TestChainConfig
+
+	// Delegating nothing, to self or to unknown node should fail.
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, big.NewInt(0))
+	g.Require().Error(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, amount)
+	g.Require().Error(err)
+	input, err = GovernanceABI.ABI.Pack("delegate", delegatorAddr2)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, amount)
+	g.Require().Error(err)
+
+	// Delegate to qualify the node.
+	input, err = GovernanceABI.ABI.Pack("delegate", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, amount)
+	g.Require().NoError(err)
+	g.Require().Equal(1, len(g.s.QualifiedNodes()))
+	g.Require().Equal(amount.String(), g.s.Delegated(addr).String())
+	g.Require().Equal(new(big.Int).Mul(amount, big.NewInt(2)).String(), g.s.TotalStaked().String())
+	g.Require().Equal(1, int(g.s.LenDelegators(addr).Uint64()))
+	g.Require().Equal(0, int(g.s.DelegatorsOffset(addr, delegatorAddr).Int64()))
+
+	// Delegate more and from another delegator.
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, amount)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr2, input, amount)
+	g.Require().NoError(err)
+	g.Require().Equal(2, int(g.s.LenDelegators(addr).Uint64()))
+	g.Require().Equal(new(big.Int).Mul(amount, big.NewInt(2)).String(),
+		g.s.Delegator(addr, big.NewInt(0)).Value.String())
+	g.Require().Equal(new(big.Int).Mul(amount, big.NewInt(3)).String(), g.s.Delegated(addr).String())
+
+	// Node without stake of its own is unqualified regardless of delegations.
+	node := g.s.Node(big.NewInt(0))
+	node.Staked = big.NewInt(0)
+	g.s.UpdateNode(big.NewInt(0), node)
+	g.Require().Equal(0, len(g.s.QualifiedNodes()))
+	node.Staked = amount
+	g.s.UpdateNode(big.NewInt(0), node)
+	g.Require().Equal(1, len(g.s.QualifiedNodes()))
+
+	// Read delegator through the contract.
+	input, err = GovernanceABI.ABI.Pack("delegators", addr, big.NewInt(1))
+	g.Require().NoError(err)
+	output, err := g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	delegator := delegatorInfo{}
+	g.Require().NoError(GovernanceABI.ABI.Unpack(&delegator, "delegators", output))
+	g.Require().Equal(delegatorAddr2, delegator.Owner)
+	g.Require().Equal(amount.String(), delegator.Value.String())
+
+	// Node with delegators can not be transferred.
+	input, err = GovernanceABI.ABI.Pack("transferNodeOwnership", common.Address{0x1})
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	// Fine is shared pro rata between node and delegators.
+	fine := new(big.Int).Div(amount, big.NewInt(2))
+	ownerBalance := g.stateDB.GetBalance(g.s.Owner())
+	g.s.FineNode(big.NewInt(0), fine)
+	share := new(big.Int).Div(fine, big.NewInt(4))
+	g.Require().Equal(share.String(), g.s.Node(big.NewInt(0)).Fined.String())
+	g.Require().Equal(new(big.Int).Sub(new(big.Int).Mul(amount, big.NewInt(2)),
+		new(big.Int).Mul(share, big.NewInt(2))).String(), g.s.Delegator(addr, big.NewInt(0)).Value.String())
+	g.Require().Equal(new(big.Int).Sub(amount, share).String(), g.s.Delegator(addr, big.NewInt(1)).Value.String())
+	g.Require().Equal(new(big.Int).Sub(new(big.Int).Mul(amount, big.NewInt(3)),
+		new(big.Int).Mul(share, big.NewInt(3))).String(), g.s.Delegated(addr).String())
+	g.Require().Equal(new(big.Int).Add(ownerBalance, new(big.Int).Mul(share, big.NewInt(3))),
+		g.stateDB.GetBalance(g.s.Owner()))
+
+	input, err = GovernanceABI.ABI.Pack("payFine", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, share)
+	g.Require().NoError(err)
+
+	// Undelegate more than delegated should fail.
+	value := g.s.Delegator(addr, big.NewInt(1)).Value
+	input, err = GovernanceABI.ABI.Pack("undelegate", addr, new(big.Int).Add(value, big.NewInt(1)))
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr2, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	// Undelegate all of the second delegator.
+	totalStaked := g.s.TotalStaked()
+	input, err = GovernanceABI.ABI.Pack("undelegate", addr, value)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr2, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(new(big.Int).Sub(totalStaked, value).String(), g.s.TotalStaked().String())
+
+	// Undelegate again before withdrawal should fail.
+	input, err = GovernanceABI.ABI.Pack("undelegate", addr, big.NewInt(1))
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr2, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	// Withdraw immediately should fail.
+	input, err = GovernanceABI.ABI.Pack("withdrawDelegation", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr2, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	// Undelegate all of the first delegator.
+	value = g.s.Delegator(addr, big.NewInt(0)).Value
+	input, err = GovernanceABI.ABI.Pack("undelegate", addr, value)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(0, len(g.s.QualifiedNodes()))
+
+	// Withdraw after lockup removes the delegators.
+	time.Sleep(time.Second * 2)
+	input, err = GovernanceABI.ABI.Pack("withdrawDelegation", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(1, int(g.s.LenDelegators(addr).Uint64()))
+	g.Require().Equal(-1, int(g.s.DelegatorsOffset(addr, delegatorAddr).Int64()))
+	g.Require().Equal(0, int(g.s.DelegatorsOffset(addr, delegatorAddr2).Int64()))
+	g.Require().Equal(delegatorAddr2, g.s.Delegator(addr, big.NewInt(0)).Owner)
+	g.Require().Equal(new(big.Int).Sub(balanceBeforeDelegate, new(big.Int).Mul(share, big.NewInt(2))),
+		g.stateDB.GetBalance(delegatorAddr))
+
+	// Node with pending delegation withdrawal is kept after node withdrawal.
+	input, err = GovernanceABI.ABI.Pack("unstake", amount)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	time.Sleep(time.Second * 2)
+	input, err = GovernanceABI.ABI.Pack("withdraw")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(1, int(g.s.LenNodes().Uint64()))
+
+	input, err = GovernanceABI.ABI.Pack("withdrawDelegation", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr2, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(0, int(g.s.LenDelegators(addr).Uint64()))
+	g.Require().Equal(big.NewInt(0).String(), g.s.Delegated(addr).String())
+	g.Require().Equal(big.NewInt(0).String(), g.s.TotalStaked().String())
+
+	// The node is removed with its last delegation.
+	g.Require().Equal(0, int(g.s.LenNodes().Uint64()))
+	g.Require().Equal(-1, int(g.s.NodesOffsetByAddress(addr).Int64()))
+}
+
+func (g *OracleContractsTestSuite) TestRewardDistribution() {
+	privKey, addr := newPrefundAccount(g.stateDB)
+	pk := crypto.FromECDSAPub(&privKey.PublicKey)
+	_, delegatorAddr := newPrefundAccount(g.stateDB)
+
+	amount := new(big.Int).Div(g.s.MinStake(), big.NewInt(4))
+	input, err := GovernanceABI.ABI.Pack("register", pk, "Test1", "test1@dexon.org", "Taipei", "https://dexon.org")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, amount)
+	g.Require().NoError(err)
+
+	input, err = GovernanceABI.ABI.Pack("delegate", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, new(big.Int).Mul(amount, big.NewInt(3)))
+	g.Require().NoError(err)
+
+	// Reward methods are not available before the fork.
+	chainConfig := *params.TestChainConfig
+	chainConfig.RewardDistributionBlock = big.NewInt(1)
+	g.chainConfig = &chainConfig
+	input, err = GovernanceABI.ABI.Pack("setCommissionRate", big.NewInt(1000))
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+	g.chainConfig = params.TestChainConfig
+
+	// Only node owner can set a commission rate within the base.
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, big.NewInt(0))
+	g.Require().Error(err)
+	input, err = GovernanceABI.ABI.Pack("setCommissionRate", big.NewInt(CommissionRateBase+1))
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	// 10% commission, pending for the lockup period.
+	input, err = GovernanceABI.ABI.Pack("setCommissionRate", big.NewInt(1000))
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	effectiveAt := g.s.PendingCommissionRateAt(addr)
+	g.Require().Equal(new(big.Int).Add(g.context.Time, g.s.LockupPeriod()), effectiveAt)
+
+	input, err = GovernanceABI.ABI.Pack("commissionRates", addr)
+	g.Require().NoError(err)
+	res, err := g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	var rate *big.Int
+	g.Require().NoError(GovernanceABI.ABI.Unpack(&rate, "commissionRates", res))
+	g.Require().Equal(int64(0), rate.Int64())
+
+	input, err = GovernanceABI.ABI.Pack("pendingCommissionRates", addr)
+	g.Require().NoError(err)
+	res, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	pending := struct {
+		Rate        *big.Int
+		EffectiveAt *big.Int
+	}{}
+	g.Require().NoError(GovernanceABI.ABI.Unpack(&pending, "pendingCommissionRates", res))
+	g.Require().Equal(int64(1000), pending.Rate.Int64())
+	g.Require().Equal(effectiveAt, pending.EffectiveAt)
+
+	// No commission before the change is effective: node owner takes 1/4,
+	// delegator takes 3/4.
+	balance := g.stateDB.GetBalance(GovernanceContractAddress)
+	g.s.DistributeBlockReward(addr, big.NewInt(1000003), new(big.Int).Sub(effectiveAt, big.NewInt(1)))
+	g.Require().Equal(new(big.Int).Add(balance, big.NewInt(1000003)), g.stateDB.GetBalance(GovernanceContractAddress))
+	g.Require().Equal(int64(250001), g.s.Reward(addr).Int64())
+	g.Require().Equal(int64(750002), g.s.Reward(delegatorAddr).Int64())
+
+	// Node owner takes 10% + 90% * 1/4, delegator takes 90% * 3/4.
+	g.s.DistributeBlockReward(addr, big.NewInt(1000003), effectiveAt)
+	g.Require().Equal(int64(1000), g.s.CommissionRate(addr).Int64())
+	g.Require().Equal(int64(0), g.s.PendingCommissionRateAt(addr).Int64())
+	g.Require().Equal(int64(250001+325001), g.s.Reward(addr).Int64())
+	g.Require().Equal(int64(750002+675002), g.s.Reward(delegatorAddr).Int64())
+
+	// Reward of unknown node goes to the coinbase.
+	_, otherAddr := newPrefundAccount(g.stateDB)
+	g.s.DistributeBlockReward(otherAddr, big.NewInt(100), effectiveAt)
+	g.Require().Equal(int64(100), g.s.Reward(otherAddr).Int64())
+
+	input, err = GovernanceABI.ABI.Pack("rewards", delegatorAddr)
+	g.Require().NoError(err)
+	res, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	var reward *big.Int
+	g.Require().NoError(GovernanceABI.ABI.Unpack(&reward, "rewards", res))
+	g.Require().Equal(int64(750002+675002), reward.Int64())
+
+	// Claim reward.
+	balance = g.stateDB.GetBalance(delegatorAddr)
+	input, err = GovernanceABI.ABI.Pack("claimReward")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(new(big.Int).Add(balance, big.NewInt(750002+675002)), g.stateDB.GetBalance(delegatorAddr))
+	g.Require().Equal(int64(0), g.s.Reward(delegatorAddr).Int64())
+
+	// Nothing left to claim.
+	_, err = g.call(GovernanceContractAddress, delegatorAddr, input, big.NewInt(0))
+	g.Require().Error(err)
+}
+
+func (g *OracleContractsTestSuite) TestLivenessPenalty() {
+	privKey, addr := newPrefundAccount(g.stateDB)
+	pk := crypto.FromECDSAPub(&privKey.PublicKey)
+
+	input, err := GovernanceABI.ABI.Pack("register", pk, "Test1", "test1@dexon.org", "Taipei", "https://dexon.org")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, g.s.MinStake())
+	g.Require().NoError(err)
+	g.Require().Equal(1, len(g.s.QualifiedNodes()))
+
+	config := g.s.Configuration()
+	config.LivenessWindow = 4
+	config.LivenessJailThreshold = 2
+	g.s.UpdateConfiguration(config)
+	node := g.s.Node(big.NewInt(0))
+	fine := g.s.FineValue(big.NewInt(FineTypeFailStop))
+
+	// A missed round slides out of the window.
+	jailed, err := g.s.RecordLiveness(node, false)
+	g.Require().NoError(err)
+	g.Require().False(jailed)
+	for i := 0; i < 4; i++ {
+		jailed, err = g.s.RecordLiveness(node, true)
+		g.Require().NoError(err)
+		g.Require().False(jailed)
+	}
+	g.Require().Equal(0, g.s.LivenessRecord(addr).Sign())
+	g.Require().Equal(0, g.s.Node(big.NewInt(0)).Fined.Sign())
+	g.Require().Equal(1, len(g.s.QualifiedNodes()))
+
+	// Missing the threshold within the window jails the node.
+	_, err = g.s.RecordLiveness(node, false)
+	g.Require().NoError(err)
+	_, err = g.s.RecordLiveness(node, true)
+	g.Require().NoError(err)
+	jailed, err = g.s.RecordLiveness(node, false)
+	g.Require().NoError(err)
+	g.Require().True(jailed)
+	g.Require().True(g.s.Jailed(addr))
+	g.Require().Equal(fine, g.s.Node(big.NewInt(0)).Fined)
+	g.Require().Equal(0, len(g.s.QualifiedNodes()))
+
+	// Jail methods are not available before the fork.
+	chainConfig := *params.TestChainConfig
+	chainConfig.LivenessPenaltyBlock = big.NewInt(1)
+	g.chainConfig = &chainConfig
+	input, err = GovernanceABI.ABI.Pack("unjail")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+	g.chainConfig = params.TestChainConfig
+
+	// Can not unjail with unpaid fine.
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	payFine, err := GovernanceABI.ABI.Pack("payFine", addr)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, payFine, fine)
+	g.Require().NoError(err)
+	g.Require().Equal(0, len(g.s.QualifiedNodes()))
+
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(1, len(g.s.QualifiedNodes()))
+
+	input, err = GovernanceABI.ABI.Pack("jailed", addr)
+	g.Require().NoError(err)
+	res, err := g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	var isJailed bool
+	g.Require().NoError(GovernanceABI.ABI.Unpack(&isJailed, "jailed", res))
+	g.Require().False(isJailed)
+
+	// Not jailed anymore.
+	input, err = GovernanceABI.ABI.Pack("unjail")
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	// The fine escalates with the times being jailed.
+	_, err = g.s.RecordLiveness(node, false)
+	g.Require().NoError(err)
+	jailed, err = g.s.RecordLiveness(node, false)
+	g.Require().NoError(err)
+	g.Require().True(jailed)
+	g.Require().Equal(new(big.Int).Mul(fine, big.NewInt(2)), g.s.Node(big.NewInt(0)).Fined)
+	g.Require().Equal(int64(2), g.s.JailCount(addr).Int64())
+}
+
+func (g *OracleContractsTestSuite) TestNodeKeySeparation() {
+	_, addr := newPrefundAccount(g.stateDB)
+	nodeKey, err := crypto.GenerateKey()
+	g.Require().NoError(err)
+	nodeKeyAddr := crypto.PubkeyToAddress(nodeKey.PublicKey)
+	_, operator := newPrefundAccount(g.stateDB)
+
+	// Register a node with a node key other than the owner.
+	input, err := GovernanceABI.ABI.Pack("register", crypto.FromECDSAPub(&nodeKey.PublicKey),
+		"Test1", "test1@dexon.org", "Taipei", "https://dexon.org")
+	g.Require().NoError(err)
+	amount := new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1e6))
+	_, err = g.call(GovernanceContractAddress, addr, input, amount)
+	g.Require().NoError(err)
+
+	g.context.Round = big.NewInt(0)
+	signer := coreUtils.NewSigner(coreEcdsa.NewPrivateKeyFromECDSA(nodeKey))
+	ready := &dkgTypes.MPKReady{
+		ProposerID: coreTypes.NewNodeID(coreEcdsa.NewPrivateKeyFromECDSA(nodeKey).PublicKey()),
+		Round:      1,
+	}
+	g.Require().NoError(signer.SignDKGMPKReady(ready))
+	readyInput, err := PackAddDKGMPKReady(ready)
+	g.Require().NoError(err)
+
+	p2pKey, err := crypto.GenerateKey()
+	g.Require().NoError(err)
+	p2pPK := crypto.FromECDSAPub(&p2pKey.PublicKey)
+	setInput, err := GovernanceABI.ABI.Pack("setP2PPublicKey", p2pPK)
+	g.Require().NoError(err)
+
+	// DKG messages relayed by the operator are accounted to the operator, and
+	// the p2p key can not be set before the fork.
+	chainConfig := *params.TestChainConfig
+	chainConfig.NodeKeySeparationBlock = big.NewInt(1)
+	g.chainConfig = &chainConfig
+	_, err = g.call(GovernanceContractAddress, operator, readyInput, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().True(g.s.DKGMPKReady(operator))
+	g.Require().False(g.s.DKGMPKReady(nodeKeyAddr))
+	_, err = g.call(GovernanceContractAddress, addr, setInput, big.NewInt(0))
+	g.Require().Error(err)
+	g.chainConfig = params.TestChainConfig
+
+	// DKG messages are accounted to the node signing them after the fork.
+	g.s.PutDKGMPKReady(operator, false)
+	g.s.ResetDKGMPKReadysCount()
+	_, err = g.call(GovernanceContractAddress, operator, readyInput, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().False(g.s.DKGMPKReady(operator))
+	g.Require().True(g.s.DKGMPKReady(nodeKeyAddr))
+	g.Require().Equal(int64(1), g.s.DKGMPKReadysCount().Int64())
+
+	// Only the node owner sets a valid p2p key.
+	_, err = g.call(GovernanceContractAddress, operator, setInput, big.NewInt(0))
+	g.Require().Error(err)
+	input, err = GovernanceABI.ABI.Pack("setP2PPublicKey", randomBytes(65, 65))
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().Error(err)
+
+	_, err = g.call(GovernanceContractAddress, addr, setInput, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(p2pPK, g.s.P2PPublicKey(nodeKeyAddr))
+
+	input, err = GovernanceABI.ABI.Pack("p2pPublicKeys", nodeKeyAddr)
+	g.Require().NoError(err)
+	res, err := g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	var pk []byte
+	g.Require().NoError(GovernanceABI.ABI.Unpack(&pk, "p2pPublicKeys", res))
+	g.Require().Equal(p2pPK, pk)
+
+	// Empty key falls back to the node key.
+	input, err = GovernanceABI.ABI.Pack("setP2PPublicKey", []byte{})
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, addr, input, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Len(g.s.P2PPublicKey(nodeKeyAddr), 0)
+}
+
 func (g *OracleContractsTestSuite) TestFine() {
 	privKey, addr := newPrefundAccount(g.stateDB)
 	pk := crypto.FromECDSAPub(&privKey.PublicKey)
@@ -625,18 +1076,21 @@ func (g *OracleContractsTestSuite) TestFine() {
 func (g *OracleContractsTestSuite) TestUpdateConfiguration() {
 	_, addr := newPrefundAccount(g.stateDB)
 
-	input, err := GovernanceABI.ABI.Pack("updateConfiguration",
+	configArgs := []interface{}{
 		new(big.Int).Mul(big.NewInt(1e18), big.NewInt(1e6)),
 		big.NewInt(1000),
 		big.NewInt(2e9),
 		big.NewInt(8000000),
 		big.NewInt(250),
 		big.NewInt(2500),
-		big.NewInt(int64(70.5*decimalMultiplier)),
-		big.NewInt(264*decimalMultiplier),
+		big.NewInt(int64(70.5 * decimalMultiplier)),
+		big.NewInt(264 * decimalMultiplier),
 		big.NewInt(600),
 		big.NewInt(900),
-		[]*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(1)})
+		[]*big.Int{big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(1), big.NewInt(1)},
+	}
+	input, err := GovernanceABI.ABI.Pack("updateConfiguration",
+		append(configArgs, big.NewInt(16), big.NewInt(4))...)
 	g.Require().NoError(err)
 
 	// Call with non-owner.
@@ -646,6 +1100,36 @@ func (g *OracleContractsTestSuite) TestUpdateConfiguration() {
 	// Call with owner.
 	_, err = g.call(GovernanceContractAddress, g.config.Owner, input, big.NewInt(0))
 	g.Require().NoError(err)
+	g.Require().Equal(uint64(16), g.s.LivenessWindow().Uint64())
+	g.Require().Equal(uint64(4), g.s.LivenessJailThreshold().Uint64())
+
+	// The jail threshold can not exceed the window.
+	invalid, err := GovernanceABI.ABI.Pack("updateConfiguration",
+		append(configArgs, big.NewInt(4), big.NewInt(5))...)
+	g.Require().NoError(err)
+	_, err = g.call(GovernanceContractAddress, g.config.Owner, invalid, big.NewInt(0))
+	g.Require().Error(err)
+
+	// The legacy form without the liveness parameters is rejected after the
+	// fork.
+	legacyArgs, err := legacyUpdateConfigurationMethod.Inputs.Pack(configArgs...)
+	g.Require().NoError(err)
+	legacy := append(legacyUpdateConfigurationMethod.Id(), legacyArgs...)
+	_, err = g.call(GovernanceContractAddress, g.config.Owner, legacy, big.NewInt(0))
+	g.Require().Error(err)
+
+	// Before the fork, only the legacy form is accepted and the liveness
+	// parameters are left untouched.
+	chainConfig := *params.TestChainConfig
+	chainConfig.LivenessPenaltyBlock = big.NewInt(1)
+	g.chainConfig = &chainConfig
+	defer func() { g.chainConfig = params.TestChainConfig }()
+	_, err = g.call(GovernanceContractAddress, g.config.Owner, input, big.NewInt(0))
+	g.Require().Error(err)
+	_, err = g.call(GovernanceContractAddress, g.config.Owner, legacy, big.NewInt(0))
+	g.Require().NoError(err)
+	g.Require().Equal(uint64(16), g.s.LivenessWindow().Uint64())
+	g.Require().Equal(uint64(4), g.s.LivenessJailThreshold().Uint64())
 }
 
 func (g *OracleContractsTestSuite) TestConfigurationReading() {
@@ -1200,3 +1684,72 @@ func (g *OracleContractsTestSuite) TestResetDKG() {
 func TestOracleContracts(t *testing.T) {
 	suite.Run(t, new(OracleContractsTestSuite))
 }
+
+func (g *OracleContractsTestSuite) TestDecodeEvent() {
+	_, addr := newPrefundAccount(g.stateDB)
+	amount := big.NewInt(1e18)
+	crs := crypto.Keccak256Hash([]byte("crs"))
+
+	g.stateDB.Prepare(common.Hash{}, common.Hash{}, 0)
+	g.s.emitStaked(addr, amount)
+	g.s.emitNodeAdded(addr)
+	g.s.emitCRSProposed(big.NewInt(3), crs)
+	g.s.emitReported(addr, big.NewInt(1), []byte{1, 2}, []byte{3})
+	g.s.emitNodeOwnershipTransfered(addr, GovernanceContractAddress)
+	g.s.emitDKGReset(big.NewInt(4), big.NewInt(100))
+
+	logs := g.stateDB.Logs()
+	g.Require().Len(logs, 6)
+
+	expected := []struct {
+		name string
+		args map[string]interface{}
+	}{
+		{"Staked", map[string]interface{}{"NodeAddress": addr, "Amount": amount}},
+		{"NodeAdded", map[string]interface{}{"NodeAddress": addr}},
+		{"CRSProposed", map[string]interface{}{"Round": big.NewInt(3), "CRS": [32]byte(crs)}},
+		{"Reported", map[string]interface{}{"NodeAddress": addr, "Type": big.NewInt(1),
+			"Arg1": []byte{1, 2}, "Arg2": []byte{3}}},
+		{"NodeOwnershipTransfered", map[string]interface{}{"NodeAddress": addr,
+			"NewOwnerAddress": GovernanceContractAddress}},
+		{"DKGReset", map[string]interface{}{"Round": big.NewInt(4), "BlockHeight": big.NewInt(100)}},
+	}
+	for i, log := range logs {
+		event, args, err := GovernanceABI.DecodeEvent(log)
+		g.Require().NoError(err)
+		g.Require().Equal(expected[i].name, event.Name)
+		g.Require().Equal(expected[i].args, args)
+	}
+
+	// Unknown event.
+	_, _, err := GovernanceABI.DecodeEvent(&types.Log{Topics: []common.Hash{{}}})
+	g.Require().Error(err)
+	_, _, err = GovernanceABI.DecodeEvent(&types.Log{})
+	g.Require().Error(err)
+}
+
+func (g *OracleContractsTestSuite) TestEventTopics() {
+	addr := common.HexToAddress("0x1234")
+	staked := GovernanceABI.Events["Staked"].Id()
+	fined := GovernanceABI.Events["Fined"].Id()
+
+	topics, err := GovernanceABI.EventTopics(nil)
+	g.Require().NoError(err)
+	g.Require().Empty(topics)
+
+	topics, err = GovernanceABI.EventTopics([]string{"Staked", "Fined"})
+	g.Require().NoError(err)
+	g.Require().Equal([][]common.Hash{{staked, fined}}, topics)
+
+	topics, err = GovernanceABI.EventTopics(nil, addr.Hash())
+	g.Require().NoError(err)
+	g.Require().Equal([][]common.Hash{{}, {addr.Hash()}}, topics)
+
+	topics, err = GovernanceABI.EventTopics([]string{"Staked"}, addr.Hash())
+	g.Require().NoError(err)
+	g.Require().Equal([][]common.Hash{{staked}, {addr.Hash()}}, topics)
+
+	// Unknown event.
+	_, err = GovernanceABI.EventTopics([]string{"Unknown"})
+	g.Require().Error(err)
+}
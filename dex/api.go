@@ -157,11 +157,9 @@ func NewPublicDebugAPI(dex *Dexon) *PublicDebugAPI {
 
 // DumpBlock retrieves the entire state of the database at a given block.
 func (api *PublicDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
-	var block *types.Block
-	if blockNr == rpc.LatestBlockNumber {
-		block = api.dex.blockchain.CurrentBlock()
-	} else {
-		block = api.dex.blockchain.GetBlockByNumber(uint64(blockNr))
+	block, err := api.dex.APIBackend.BlockByNumber(context.Background(), blockNr)
+	if err != nil {
+		return state.Dump{}, err
 	}
 	if block == nil {
 		return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
@@ -57,11 +57,26 @@ func (b *DexAPIBackend) SetHead(number uint64) {
 	b.dex.blockchain.SetHead(number)
 }
 
+// resolveRoundStart resolves the alias of the first block of a round to the
+// block number, returning false if the round has not begun.
+func (b *DexAPIBackend) resolveRoundStart(blockNr rpc.BlockNumber) (rpc.BlockNumber, bool) {
+	round, ok := blockNr.RoundStart()
+	if !ok {
+		return blockNr, true
+	}
+	height, ok := b.dex.blockchain.GetRoundHeight(round)
+	return rpc.BlockNumber(height), ok
+}
+
 func (b *DexAPIBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*types.Header, error) {
 	// Otherwise resolve and return the block
 	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
 		return b.dex.blockchain.CurrentBlock().Header(), nil
 	}
+	blockNr, ok := b.resolveRoundStart(blockNr)
+	if !ok {
+		return nil, nil
+	}
 	return b.dex.blockchain.GetHeaderByNumber(uint64(blockNr)), nil
 }
 
@@ -74,10 +89,18 @@ func (b *DexAPIBackend) BlockByNumber(ctx context.Context, blockNr rpc.BlockNumb
 	if blockNr == rpc.LatestBlockNumber {
 		return b.dex.blockchain.CurrentBlock(), nil
 	}
+	blockNr, ok := b.resolveRoundStart(blockNr)
+	if !ok {
+		return nil, nil
+	}
 	return b.dex.blockchain.GetBlockByNumber(uint64(blockNr)), nil
 }
 
 func (b *DexAPIBackend) StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error) {
+	// The pending state applies the blocks confirmed but not yet delivered.
+	if blockNr == rpc.PendingBlockNumber {
+		return b.dex.app.pendingState()
+	}
 	header, err := b.HeaderByNumber(ctx, blockNr)
 	if header == nil || err != nil {
 		return nil, nil, err
@@ -163,7 +186,11 @@ func (b *DexAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 }
 
 func (b *DexAPIBackend) GetPoolNonce(ctx context.Context, addr common.Address) (uint64, error) {
-	return b.dex.txPool.State().GetNonce(addr), nil
+	nonce := b.dex.txPool.State().GetNonce(addr)
+	if confirmed, ok := b.dex.app.pendingNonce(addr); ok && confirmed > nonce {
+		nonce = confirmed
+	}
+	return nonce, nil
 }
 
 func (b *DexAPIBackend) Stats() (pending int, queued int) {
@@ -0,0 +1,216 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/rawdb"
+	"github.com/dexon-foundation/dexon/internal/ethapi"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+// Lifecycle states of a transaction, from the earliest to the latest.
+const (
+	TxStateUnknown   = "unknown"
+	TxStateQueued    = "queued"
+	TxStatePending   = "pending"
+	TxStateConfirmed = "confirmed"
+	TxStateDelivered = "delivered"
+	TxStateFinalized = "finalized"
+)
+
+// TxSkip is why the proposer last skipped a transaction.
+type TxSkip struct {
+	Reason string         `json:"reason"`
+	Height hexutil.Uint64 `json:"height"`
+	Time   hexutil.Uint64 `json:"time"`
+}
+
+// TxConfirmation is the consensus block confirming a transaction which is not
+// delivered yet.
+type TxConfirmation struct {
+	BlockHash common.Hash    `json:"blockHash"`
+	Round     hexutil.Uint64 `json:"round"`
+	Height    hexutil.Uint64 `json:"height"`
+}
+
+// TxDelivery is the block a transaction is delivered in.
+type TxDelivery struct {
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Index       hexutil.Uint64 `json:"transactionIndex"`
+}
+
+// TxStatus is the lifecycle status of a transaction.
+type TxStatus struct {
+	// State is the latest state the transaction reached.
+	State string `json:"state"`
+
+	// Pool is the state of the transaction in the pool, pending or queued,
+	// empty if it is not in the pool.
+	Pool string `json:"pool"`
+
+	Skipped   *TxSkip         `json:"skipped"`
+	Confirmed *TxConfirmation `json:"confirmed"`
+	Delivered *TxDelivery     `json:"delivered"`
+}
+
+// RoundInfo summarizes the blocks of a round.
+type RoundInfo struct {
+	Round       hexutil.Uint64 `json:"round"`
+	StartHeight hexutil.Uint64 `json:"startHeight"`
+	EndHeight   hexutil.Uint64 `json:"endHeight"`
+	BlockCount  hexutil.Uint64 `json:"blockCount"`
+	TotalReward *hexutil.Big   `json:"totalReward"`
+	GasUsed     hexutil.Uint64 `json:"gasUsed"`
+
+	// Extended tells whether the round runs beyond its length, in which
+	// blocks carry no reward.
+	Extended bool `json:"extended"`
+
+	// Completed tells whether the next round has begun. The end height of an
+	// ongoing round is the current block.
+	Completed bool `json:"completed"`
+}
+
+// PublicDexAPI provides the DEXON specific information of the chain.
+type PublicDexAPI struct {
+	dex *Dexon
+}
+
+// NewPublicDexAPI creates a new DEXON protocol API.
+func NewPublicDexAPI(dex *Dexon) *PublicDexAPI {
+	return &PublicDexAPI{dex: dex}
+}
+
+// GetTransactionStatus returns the lifecycle status of a transaction: its
+// state in the pool, the reason the proposer last skipped it, and whether it
+// is confirmed by consensus, delivered in a block, or finalized by the
+// witness of a later block.
+func (api *PublicDexAPI) GetTransactionStatus(ctx context.Context, hash common.Hash) (*TxStatus, error) {
+	status := &TxStatus{State: TxStateUnknown}
+
+	switch api.dex.txPool.Status([]common.Hash{hash})[0] {
+	case core.TxStatusPending:
+		status.Pool = TxStatePending
+		status.State = TxStatePending
+	case core.TxStatusQueued:
+		status.Pool = TxStateQueued
+		status.State = TxStateQueued
+	}
+
+	if skip, ok := api.dex.app.txSkipped(hash); ok {
+		status.Skipped = &TxSkip{
+			Reason: skip.reason,
+			Height: hexutil.Uint64(skip.height),
+			Time:   hexutil.Uint64(skip.time.Unix()),
+		}
+	}
+
+	if block, ok := api.dex.app.txConfirmed(hash); ok {
+		status.Confirmed = &TxConfirmation{
+			BlockHash: common.Hash(block.Hash),
+			Round:     hexutil.Uint64(block.Position.Round),
+			Height:    hexutil.Uint64(block.Position.Height),
+		}
+		status.State = TxStateConfirmed
+	}
+
+	tx, blockHash, number, index := rawdb.ReadTransaction(api.dex.chainDb, hash)
+	if tx != nil {
+		status.Delivered = &TxDelivery{
+			BlockHash:   blockHash,
+			BlockNumber: hexutil.Uint64(number),
+			Index:       hexutil.Uint64(index),
+		}
+		status.State = TxStateDelivered
+		if api.witnessed(number) {
+			status.State = TxStateFinalized
+		}
+	}
+	return status, nil
+}
+
+// witnessed tells whether the state after the block at the given number is
+// witnessed by the current block.
+func (api *PublicDexAPI) witnessed(number uint64) bool {
+	var coreBlock coreTypes.Block
+	head := api.dex.blockchain.CurrentBlock()
+	if err := rlp.DecodeBytes(head.Header().DexconMeta, &coreBlock); err != nil {
+		return false
+	}
+	return coreBlock.Witness.Height >= number
+}
+
+// GetBlockByRound returns the first block of the round.
+func (api *PublicDexAPI) GetBlockByRound(ctx context.Context, round hexutil.Uint64, fullTx bool) (map[string]interface{}, error) {
+	return ethapi.NewPublicBlockChainAPI(api.dex.APIBackend).GetBlockByNumber(ctx,
+		rpc.RoundStartBlockNumber(uint64(round)), fullTx)
+}
+
+// GetRound returns the heights, block count, total rewards and gas used of
+// the round.
+func (api *PublicDexAPI) GetRound(ctx context.Context, round hexutil.Uint64) (*RoundInfo, error) {
+	chain := api.dex.blockchain
+	start, ok := chain.GetRoundHeight(uint64(round))
+	if !ok {
+		return nil, fmt.Errorf("round %d not begun", round)
+	}
+
+	info := &RoundInfo{
+		Round:       round,
+		StartHeight: hexutil.Uint64(start),
+	}
+	end := chain.CurrentBlock().NumberU64()
+	if next, ok := chain.GetRoundHeight(uint64(round) + 1); ok {
+		end = next - 1
+		info.Completed = true
+	}
+
+	reward := new(big.Int)
+	for number := start; number <= end; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			return nil, fmt.Errorf("header %d not found", number)
+		}
+		if header.Reward != nil {
+			reward.Add(reward, header.Reward)
+		}
+		info.GasUsed += hexutil.Uint64(header.GasUsed)
+	}
+	info.EndHeight = hexutil.Uint64(end)
+	info.BlockCount = hexutil.Uint64(end - start + 1)
+	info.TotalReward = (*hexutil.Big)(reward)
+
+	// Round 0 starts at height 0 instead of height 1.
+	length := api.dex.governance.DexconConfiguration(uint64(round)).RoundLength
+	if round == 0 {
+		length++
+	}
+	info.Extended = end >= start+length
+	return info, nil
+}
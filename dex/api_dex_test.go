@@ -0,0 +1,213 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
+	coreEcdsa "github.com/dexon-foundation/dexon-consensus/core/crypto/ecdsa"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+func TestGetTransactionStatus(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Generate key fail: %v", err)
+	}
+	dex, keys, err := newDexon(masterKey, 3)
+	if err != nil {
+		t.Fatalf("New dexon fail: %v", err)
+	}
+	api := NewPublicDexAPI(dex)
+
+	signer := types.NewEIP155Signer(dex.chainConfig.ChainID)
+	minGasPrice := dex.chainConfig.Dexcon.MinGasPrice
+	newTx := func(key *ecdsa.PrivateKey, nonce uint64, gas uint64, price int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, nil, gas,
+			new(big.Int).Mul(minGasPrice, big.NewInt(price)), nil), signer, key)
+		if err != nil {
+			t.Fatalf("Sign tx fail: %v", err)
+		}
+		return tx
+	}
+	status := func(tx *types.Transaction) *TxStatus {
+		status, err := api.GetTransactionStatus(context.Background(), tx.Hash())
+		if err != nil {
+			t.Fatalf("Get transaction status fail: %v", err)
+		}
+		return status
+	}
+
+	blockGasLimit := dex.chainConfig.Dexcon.BlockGasLimit
+	txs := []*types.Transaction{
+		newTx(keys[0], 0, 21000, 2),
+		newTx(keys[1], 0, blockGasLimit-21000+1, 1),
+		newTx(keys[2], 1, 21000, 1),
+	}
+	for _, err := range dex.txPool.AddRemotes(txs) {
+		if err != nil {
+			t.Fatalf("Add tx fail: %v", err)
+		}
+	}
+
+	unknown := newTx(keys[0], 1, 21000, 1)
+	if s := status(unknown); s.State != TxStateUnknown || s.Pool != "" {
+		t.Errorf("Unknown tx status mismatch: %+v", s)
+	}
+	if s := status(txs[2]); s.State != TxStateQueued || s.Pool != TxStateQueued {
+		t.Errorf("Queued tx status mismatch: %+v", s)
+	}
+
+	payload, err := dex.app.preparePayload(context.Background(), coreTypes.Position{Height: 1})
+	if err != nil {
+		t.Fatalf("Prepare payload fail: %v", err)
+	}
+
+	// The second transaction does not fit in the block.
+	s := status(txs[1])
+	if s.State != TxStatePending || s.Pool != TxStatePending {
+		t.Errorf("Pending tx status mismatch: %+v", s)
+	}
+	if s.Skipped == nil || s.Skipped.Reason != SkipReasonBlockGasLimit || s.Skipped.Height != 1 {
+		t.Errorf("Skipped reason mismatch: %+v", s.Skipped)
+	}
+	if s := status(txs[0]); s.State != TxStatePending || s.Skipped != nil {
+		t.Errorf("Packed tx status mismatch: %+v", s)
+	}
+
+	genesis := dex.blockchain.Genesis()
+	witnessData, err := rlp.EncodeToBytes(genesis.Hash())
+	if err != nil {
+		t.Fatalf("Encode witness fail: %v", err)
+	}
+	block := coreTypes.Block{
+		ProposerID: coreTypes.NewNodeID(coreEcdsa.NewPrivateKeyFromECDSA(masterKey).PublicKey()),
+		Hash:       coreCommon.NewRandomHash(),
+		Position:   coreTypes.Position{Height: 1},
+		Timestamp:  time.Now(),
+		Payload:    payload,
+		Witness:    coreTypes.Witness{Height: 0, Data: witnessData},
+	}
+	dex.app.BlockConfirmed(block)
+	s = status(txs[0])
+	if s.State != TxStateConfirmed || s.Confirmed == nil ||
+		s.Confirmed.BlockHash != common.Hash(block.Hash) || s.Confirmed.Height != 1 {
+		t.Errorf("Confirmed tx status mismatch: %+v", s)
+	}
+
+	dex.app.BlockDelivered(block.Hash, block.Position, []byte{1})
+	s = status(txs[0])
+	if s.State != TxStateDelivered || s.Confirmed != nil || s.Delivered == nil ||
+		s.Delivered.BlockNumber != 1 || s.Delivered.Index != 0 {
+		t.Errorf("Delivered tx status mismatch: %+v", s)
+	}
+
+	// The state after the transaction is finalized once witnessed.
+	witnessData, err = rlp.EncodeToBytes(dex.blockchain.CurrentBlock().Hash())
+	if err != nil {
+		t.Fatalf("Encode witness fail: %v", err)
+	}
+	empty := coreTypes.Block{
+		Hash:      coreCommon.NewRandomHash(),
+		Position:  coreTypes.Position{Height: 2},
+		Timestamp: time.Now(),
+		Witness:   coreTypes.Witness{Height: 1, Data: witnessData},
+	}
+	dex.app.BlockConfirmed(empty)
+	dex.app.BlockDelivered(empty.Hash, empty.Position, []byte{2})
+	if s := status(txs[0]); s.State != TxStateFinalized {
+		t.Errorf("Finalized tx status mismatch: %+v", s)
+	}
+}
+
+func TestGetRound(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Generate key fail: %v", err)
+	}
+	dex, _, err := newDexon(masterKey, 1)
+	if err != nil {
+		t.Fatalf("New dexon fail: %v", err)
+	}
+	api := NewPublicDexAPI(dex)
+
+	// Deliver a block proposed by the node and an empty block.
+	proposer := coreTypes.NewNodeID(coreEcdsa.NewPrivateKeyFromECDSA(masterKey).PublicKey())
+	for _, proposerID := range []coreTypes.NodeID{proposer, {}} {
+		witnessData, err := rlp.EncodeToBytes(dex.blockchain.CurrentBlock().Hash())
+		if err != nil {
+			t.Fatalf("Encode witness fail: %v", err)
+		}
+		block := coreTypes.Block{
+			ProposerID: proposerID,
+			Hash:       coreCommon.NewRandomHash(),
+			Position:   coreTypes.Position{Height: dex.blockchain.CurrentBlock().NumberU64() + 1},
+			Timestamp:  time.Now(),
+			Witness: coreTypes.Witness{
+				Height: dex.blockchain.CurrentBlock().NumberU64(),
+				Data:   witnessData,
+			},
+		}
+		dex.app.BlockConfirmed(block)
+		dex.app.BlockDelivered(block.Hash, block.Position, []byte{1})
+	}
+
+	info, err := api.GetRound(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("Get round fail: %v", err)
+	}
+	reward := dex.blockchain.GetHeaderByNumber(1).Reward
+	if reward.Sign() <= 0 {
+		t.Fatalf("No reward for proposed block")
+	}
+	if info.StartHeight != 0 || info.EndHeight != 2 || info.BlockCount != 3 ||
+		info.TotalReward.ToInt().Cmp(reward) != 0 || info.Extended || info.Completed {
+		t.Errorf("Round info mismatch: %+v", info)
+	}
+	if _, err := api.GetRound(context.Background(), 1); err == nil {
+		t.Errorf("Got info of unbegun round")
+	}
+
+	block, err := api.GetBlockByRound(context.Background(), 0, false)
+	if err != nil {
+		t.Fatalf("Get block by round fail: %v", err)
+	}
+	if block["hash"] != dex.blockchain.Genesis().Hash() {
+		t.Errorf("Round start block mismatch: have %v, want %v", block["hash"], dex.blockchain.Genesis().Hash())
+	}
+	if block, err := api.GetBlockByRound(context.Background(), 1, false); block != nil || err != nil {
+		t.Errorf("Got block of unbegun round: %v, %v", block, err)
+	}
+
+	// The round start alias resolves in other calls.
+	header, err := dex.APIBackend.HeaderByNumber(context.Background(), rpc.RoundStartBlockNumber(0))
+	if err != nil || header == nil || header.Number.Sign() != 0 {
+		t.Errorf("Round start header mismatch: %v, %v", header, err)
+	}
+}
@@ -0,0 +1,282 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/eth/filters"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+// GovernanceEventQuery selects governance events. All fields are optional.
+type GovernanceEventQuery struct {
+	// NodeAddress restricts the result to events whose NodeAddress matches.
+	NodeAddress *common.Address `json:"nodeAddress"`
+
+	// Events restricts the result to the named events, e.g. "Staked".
+	Events []string `json:"events"`
+
+	// FromRound and ToRound select an inclusive round range. They default
+	// to the genesis round and the latest round.
+	FromRound *hexutil.Uint64 `json:"fromRound"`
+	ToRound   *hexutil.Uint64 `json:"toRound"`
+}
+
+// GovernanceEvent is a decoded governance contract event.
+type GovernanceEvent struct {
+	Event       string                 `json:"event"`
+	Args        map[string]interface{} `json:"args"`
+	Round       hexutil.Uint64         `json:"round"`
+	BlockNumber hexutil.Uint64         `json:"blockNumber"`
+	BlockHash   common.Hash            `json:"blockHash"`
+	TxHash      common.Hash            `json:"transactionHash"`
+	TxIndex     hexutil.Uint           `json:"transactionIndex"`
+	LogIndex    hexutil.Uint           `json:"logIndex"`
+}
+
+// NotarySetMember is a node in the notary set of a round.
+type NotarySetMember struct {
+	ID             common.Hash    `json:"id"`
+	NodeKeyAddress common.Address `json:"nodeKeyAddress"`
+	Owner          common.Address `json:"owner"`
+	PublicKey      hexutil.Bytes  `json:"publicKey"`
+	Staked         *hexutil.Big   `json:"staked"`
+	Delegated      *hexutil.Big   `json:"delegated"`
+}
+
+// DKGComplaint is a complaint against the private share of a DKG member.
+// A nack complaint reports a missing share, others an invalid one.
+type DKGComplaint struct {
+	ProposerID common.Hash `json:"proposerID"`
+	AccusedID  common.Hash `json:"accusedID"`
+	Nack       bool        `json:"nack"`
+}
+
+// DKGResult is the outcome of the DKG of a round.
+type DKGResult struct {
+	Round        hexutil.Uint64  `json:"round"`
+	Reset        hexutil.Uint64  `json:"reset"`
+	Threshold    hexutil.Uint64  `json:"threshold"`
+	Qualified    []common.Hash   `json:"qualified"`
+	Disqualified []common.Hash   `json:"disqualified"`
+	Complaints   []*DKGComplaint `json:"complaints"`
+	MPKReadys    hexutil.Uint64  `json:"mpkReadys"`
+	MPKReady     bool            `json:"mpkReady"`
+	Finalizeds   hexutil.Uint64  `json:"finalizeds"`
+	Final        bool            `json:"final"`
+	Successes    hexutil.Uint64  `json:"successes"`
+	Success      bool            `json:"success"`
+}
+
+// PublicGovernanceAPI provides the decoded history of governance contract
+// events.
+type PublicGovernanceAPI struct {
+	dex *Dexon
+}
+
+// NewPublicGovernanceAPI creates a new governance event API.
+func NewPublicGovernanceAPI(dex *Dexon) *PublicGovernanceAPI {
+	return &PublicGovernanceAPI{dex: dex}
+}
+
+// EventNames returns the names of all governance contract events.
+func (api *PublicGovernanceAPI) EventNames() []string {
+	names := make([]string, 0, len(vm.GovernanceABI.Events))
+	for name := range vm.GovernanceABI.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetEvents returns the governance events matching the given query.
+func (api *PublicGovernanceAPI) GetEvents(ctx context.Context, query GovernanceEventQuery) ([]*GovernanceEvent, error) {
+	topics, err := governanceEventTopics(query)
+	if err != nil {
+		return nil, err
+	}
+	begin, end, err := api.blockRange(query)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := filters.NewRangeFilter(api.dex.APIBackend, begin, end,
+		[]common.Address{vm.GovernanceContractAddress}, topics)
+	logs, err := filter.Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rounds := make(map[common.Hash]uint64)
+	events := make([]*GovernanceEvent, 0, len(logs))
+	for _, log := range logs {
+		round, ok := rounds[log.BlockHash]
+		if !ok {
+			header := api.dex.blockchain.GetHeaderByHash(log.BlockHash)
+			if header == nil {
+				return nil, fmt.Errorf("header %x not found", log.BlockHash)
+			}
+			round = header.Round
+			rounds[log.BlockHash] = round
+		}
+		event, err := newGovernanceEvent(log, round)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// GetNotarySet returns the notary set of a begun round, computed from the
+// governance state the round is configured by.
+func (api *PublicGovernanceAPI) GetNotarySet(round hexutil.Uint64) ([]*NotarySetMember, error) {
+	nodes, err := api.dex.governance.NotarySetNodesAtRound(uint64(round))
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*NotarySetMember, 0, len(nodes))
+	for _, n := range nodes {
+		members = append(members, &NotarySetMember{
+			ID:             common.Hash(n.ID.Hash),
+			NodeKeyAddress: vm.IdToAddress(n.ID),
+			Owner:          n.Owner,
+			PublicKey:      n.PublicKey,
+			Staked:         (*hexutil.Big)(n.Staked),
+			Delegated:      (*hexutil.Big)(n.Delegated),
+		})
+	}
+	return members, nil
+}
+
+// GetDKGResult returns the qualified and disqualified members, complaints
+// and progress of the DKG of a round.
+func (api *PublicGovernanceAPI) GetDKGResult(round hexutil.Uint64) (*DKGResult, error) {
+	dkg, err := api.dex.governance.DKGResultAtRound(uint64(round))
+	if err != nil {
+		return nil, err
+	}
+	result := &DKGResult{
+		Round:        round,
+		Reset:        hexutil.Uint64(dkg.Reset),
+		Threshold:    hexutil.Uint64(dkg.Threshold),
+		Qualified:    []common.Hash{},
+		Disqualified: []common.Hash{},
+		Complaints:   make([]*DKGComplaint, 0, len(dkg.Complaints)),
+		MPKReadys:    hexutil.Uint64(dkg.MPKReadys),
+		MPKReady:     dkg.MPKReady,
+		Finalizeds:   hexutil.Uint64(dkg.Finalizeds),
+		Final:        dkg.Final,
+		Successes:    hexutil.Uint64(dkg.Successes),
+		Success:      dkg.Success,
+	}
+	for _, mpk := range dkg.MasterPublicKeys {
+		id := common.Hash(mpk.ProposerID.Hash)
+		if _, ok := dkg.Qualified[mpk.ProposerID]; ok {
+			result.Qualified = append(result.Qualified, id)
+		} else {
+			result.Disqualified = append(result.Disqualified, id)
+		}
+	}
+	for _, c := range dkg.Complaints {
+		result.Complaints = append(result.Complaints, &DKGComplaint{
+			ProposerID: common.Hash(c.ProposerID.Hash),
+			AccusedID:  common.Hash(c.PrivateShare.ProposerID.Hash),
+			Nack:       c.IsNack(),
+		})
+	}
+	return result, nil
+}
+
+// blockRange converts the round range of the query into a block range.
+func (api *PublicGovernanceAPI) blockRange(query GovernanceEventQuery) (int64, int64, error) {
+	var from, to uint64
+	if query.FromRound != nil {
+		from = uint64(*query.FromRound)
+	}
+	if query.ToRound != nil {
+		to = uint64(*query.ToRound)
+		if to < from {
+			return 0, 0, fmt.Errorf("invalid round range %d-%d", from, to)
+		}
+	}
+
+	gs := api.dex.governance.GetHeadState()
+	roundHeight := func(round uint64) (uint64, bool) {
+		height := gs.RoundHeight(new(big.Int).SetUint64(round)).Uint64()
+		return height, round == 0 || height != 0
+	}
+
+	begin, ok := roundHeight(from)
+	if !ok {
+		return 0, 0, fmt.Errorf("round %d not started", from)
+	}
+	end := int64(rpc.LatestBlockNumber)
+	if query.ToRound != nil {
+		if next, ok := roundHeight(to + 1); ok {
+			end = int64(next) - 1
+		}
+	}
+	return int64(begin), end, nil
+}
+
+// governanceEventTopics builds the log filter topics of the query.
+func governanceEventTopics(query GovernanceEventQuery) ([][]common.Hash, error) {
+	var indexed []common.Hash
+	if query.NodeAddress != nil {
+		indexed = append(indexed, query.NodeAddress.Hash())
+	}
+	return vm.GovernanceABI.EventTopics(query.Events, indexed...)
+}
+
+// newGovernanceEvent decodes the log and converts the arguments into their
+// RPC representation.
+func newGovernanceEvent(log *types.Log, round uint64) (*GovernanceEvent, error) {
+	event, args, err := vm.GovernanceABI.DecodeEvent(log)
+	if err != nil {
+		return nil, err
+	}
+	for name, arg := range args {
+		switch v := arg.(type) {
+		case *big.Int:
+			args[name] = (*hexutil.Big)(v)
+		case []byte:
+			args[name] = hexutil.Bytes(v)
+		case [32]byte:
+			args[name] = common.Hash(v)
+		}
+	}
+	return &GovernanceEvent{
+		Event:       event.Name,
+		Args:        args,
+		Round:       hexutil.Uint64(round),
+		BlockNumber: hexutil.Uint64(log.BlockNumber),
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		TxIndex:     hexutil.Uint(log.TxIndex),
+		LogIndex:    hexutil.Uint(log.Index),
+	}, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+)
+
+func TestGovernanceEventTopics(t *testing.T) {
+	addr := common.HexToAddress("0x1234")
+	staked := vm.GovernanceABI.Events["Staked"].Id()
+	fined := vm.GovernanceABI.Events["Fined"].Id()
+
+	topics, err := governanceEventTopics(GovernanceEventQuery{})
+	if err != nil || len(topics) != 0 {
+		t.Errorf("empty query mismatch: have %v, %v", topics, err)
+	}
+
+	topics, err = governanceEventTopics(GovernanceEventQuery{Events: []string{"Staked", "Fined"}})
+	if err != nil {
+		t.Fatalf("failed to build topics: %v", err)
+	}
+	if len(topics) != 1 || len(topics[0]) != 2 || topics[0][0] != staked || topics[0][1] != fined {
+		t.Errorf("event topics mismatch: have %v", topics)
+	}
+
+	topics, err = governanceEventTopics(GovernanceEventQuery{NodeAddress: &addr})
+	if err != nil {
+		t.Fatalf("failed to build topics: %v", err)
+	}
+	if len(topics) != 2 || len(topics[0]) != 0 || len(topics[1]) != 1 || topics[1][0] != addr.Hash() {
+		t.Errorf("node topics mismatch: have %v", topics)
+	}
+
+	if _, err := governanceEventTopics(GovernanceEventQuery{Events: []string{"Unknown"}}); err == nil {
+		t.Error("expected error for unknown event")
+	}
+}
+
+func TestNewGovernanceEvent(t *testing.T) {
+	addr := common.HexToAddress("0x1234")
+	log := &types.Log{
+		Address:     vm.GovernanceContractAddress,
+		Topics:      []common.Hash{vm.GovernanceABI.Events["Staked"].Id(), addr.Hash()},
+		Data:        common.BigToHash(big.NewInt(1000)).Bytes(),
+		BlockNumber: 10,
+		BlockHash:   common.HexToHash("0xabcd"),
+		TxHash:      common.HexToHash("0xef"),
+		TxIndex:     1,
+		Index:       2,
+	}
+	event, err := newGovernanceEvent(log, 3)
+	if err != nil {
+		t.Fatalf("failed to decode event: %v", err)
+	}
+	if event.Event != "Staked" || event.Round != 3 || event.BlockNumber != 10 ||
+		event.BlockHash != log.BlockHash || event.TxHash != log.TxHash ||
+		event.TxIndex != 1 || event.LogIndex != 2 {
+		t.Errorf("event mismatch: have %+v", event)
+	}
+	if have := event.Args["NodeAddress"]; have != addr {
+		t.Errorf("node address mismatch: have %v, want %v", have, addr)
+	}
+	amount, ok := event.Args["Amount"].(*hexutil.Big)
+	if !ok || amount.ToInt().Cmp(big.NewInt(1000)) != 0 {
+		t.Errorf("amount mismatch: have %v", event.Args["Amount"])
+	}
+	if _, err := json.Marshal(event); err != nil {
+		t.Errorf("failed to marshal event: %v", err)
+	}
+}
+
+func TestGetNotarySet(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	dex, _, err := newDexon(masterKey, 1)
+	if err != nil {
+		t.Fatalf("failed to create dexon: %v", err)
+	}
+	api := NewPublicGovernanceAPI(dex)
+
+	// The notary set matches the one used by consensus.
+	members, err := api.GetNotarySet(0)
+	if err != nil {
+		t.Fatalf("failed to get notary set: %v", err)
+	}
+	notarySet, err := dex.governance.NotarySet(0)
+	if err != nil {
+		t.Fatalf("failed to get notary set: %v", err)
+	}
+	if len(members) == 0 || len(members) != len(notarySet) {
+		t.Fatalf("notary set size mismatch: have %d, want %d", len(members), len(notarySet))
+	}
+	gs := dex.governance.GetHeadState()
+	for _, m := range members {
+		if _, ok := notarySet[hex.EncodeToString(m.PublicKey)]; !ok {
+			t.Errorf("node %x not in notary set", m.PublicKey)
+		}
+		pk, err := crypto.UnmarshalPubkey(m.PublicKey)
+		if err != nil {
+			t.Fatalf("failed to unmarshal public key: %v", err)
+		}
+		node := gs.Node(gs.NodesOffsetByNodeKeyAddress(crypto.PubkeyToAddress(*pk)))
+		if m.NodeKeyAddress != crypto.PubkeyToAddress(*pk) || m.Owner != node.Owner ||
+			m.Staked.ToInt().Cmp(node.Staked) != 0 {
+			t.Errorf("member mismatch: %+v", m)
+		}
+	}
+
+	if _, err := api.GetNotarySet(1); err == nil {
+		t.Error("expected error for round not begun")
+	}
+}
+
+func TestGetDKGResult(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	dex, _, err := newDexon(masterKey, 1)
+	if err != nil {
+		t.Fatalf("failed to create dexon: %v", err)
+	}
+	api := NewPublicGovernanceAPI(dex)
+
+	// No DKG is run for the rounds before DKGDelayRound.
+	if _, err := api.GetDKGResult(0); err == nil {
+		t.Error("expected error for round without DKG")
+	}
+
+	// The DKG of the first round runs from the genesis.
+	result, err := api.GetDKGResult(1)
+	if err != nil {
+		t.Fatalf("failed to get DKG result: %v", err)
+	}
+	if result.Round != 1 || len(result.Qualified) != 0 || len(result.Disqualified) != 0 ||
+		len(result.Complaints) != 0 || result.MPKReady || result.Final || result.Success {
+		t.Errorf("DKG result mismatch: %+v", result)
+	}
+
+	// The DKG of the next round is not begun until the CRS is proposed.
+	if _, err := api.GetDKGResult(2); err == nil {
+		t.Error("expected error for DKG not begun")
+	}
+}
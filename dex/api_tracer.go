@@ -103,19 +103,13 @@ type txTraceTask struct {
 // between two blocks (excluding start) and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceChain(ctx context.Context, start, end rpc.BlockNumber, config *TraceConfig) (*rpc.Subscription, error) {
 	// Fetch the block interval that we want to trace
-	var from, to *types.Block
-
-	switch start {
-	case rpc.LatestBlockNumber:
-		from = api.dex.blockchain.CurrentBlock()
-	default:
-		from = api.dex.blockchain.GetBlockByNumber(uint64(start))
+	from, err := api.dex.APIBackend.BlockByNumber(ctx, start)
+	if err != nil {
+		return nil, err
 	}
-	switch end {
-	case rpc.LatestBlockNumber:
-		to = api.dex.blockchain.CurrentBlock()
-	default:
-		to = api.dex.blockchain.GetBlockByNumber(uint64(end))
+	to, err := api.dex.APIBackend.BlockByNumber(ctx, end)
+	if err != nil {
+		return nil, err
 	}
 	// Trace the chain if we've found all our blocks
 	if from == nil {
@@ -349,13 +343,9 @@ func (api *PrivateDebugAPI) traceChain(ctx context.Context, start, end *types.Bl
 // EVM and returns them as a JSON object.
 func (api *PrivateDebugAPI) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
 	// Fetch the block that we want to trace
-	var block *types.Block
-
-	switch number {
-	case rpc.LatestBlockNumber:
-		block = api.dex.blockchain.CurrentBlock()
-	default:
-		block = api.dex.blockchain.GetBlockByNumber(uint64(number))
+	block, err := api.dex.APIBackend.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
 	}
 	// Trace the block if it was found
 	if block == nil {
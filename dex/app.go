@@ -21,22 +21,49 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"math"
 	"math/big"
+	"runtime"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
 	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/state"
 	"github.com/dexon-foundation/dexon/core/types"
 	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/event"
 	"github.com/dexon-foundation/dexon/log"
+	"github.com/dexon-foundation/dexon/params"
 	"github.com/dexon-foundation/dexon/rlp"
 )
 
+// skippedTxsLimit is the number of transactions skipped by the proposer whose
+// reasons are remembered.
+const skippedTxsLimit = 4096
+
+// Reasons the proposer skips a pending transaction when preparing payload.
+const (
+	SkipReasonNonceGap          = "nonce gap"
+	SkipReasonGasPrice          = "gas price below minimum"
+	SkipReasonIntrinsicGas      = "intrinsic gas too low"
+	SkipReasonInsufficientFunds = "insufficient funds for gas * price + value"
+	SkipReasonBlockGasLimit     = "exceeds remaining block gas"
+)
+
+// txSkip records why the proposer last skipped a transaction.
+type txSkip struct {
+	reason string
+	height uint64
+	time   time.Time
+}
+
 // DexconApp implements the DEXON consensus core application interface.
 type DexconApp struct {
 	txPool     *core.TxPool
@@ -56,10 +83,31 @@ type DexconApp struct {
 	addressCounter  map[common.Address]uint64
 	undeliveredNum  uint64
 	deliveredHeight uint64
+
+	// confirmedTxs maps the transactions in confirmed but undelivered blocks
+	// to the hash of their blocks.
+	confirmedTxs map[common.Hash]coreCommon.Hash
+	skippedTxs   *lru.Cache
+
+	// confirmedGen is bumped whenever the confirmed but undelivered blocks
+	// change, invalidating pendingStateCache.
+	confirmedGen      uint64
+	pendingStateMu    sync.Mutex
+	pendingStateCache *pendingStateCache
+}
+
+// pendingStateCache is the pending state of a generation of confirmed blocks
+// on top of a head block.
+type pendingStateCache struct {
+	gen     uint64
+	head    common.Hash
+	statedb *state.StateDB
+	header  *types.Header
 }
 
 func NewDexconApp(txPool *core.TxPool, blockchain *core.BlockChain, gov *DexconGovernance,
 	chainDB ethdb.Database, config *Config) *DexconApp {
+	skippedTxs, _ := lru.New(skippedTxsLimit)
 	return &DexconApp{
 		txPool:          txPool,
 		blockchain:      blockchain,
@@ -71,46 +119,97 @@ func NewDexconApp(txPool *core.TxPool, blockchain *core.BlockChain, gov *DexconG
 		addressCost:     map[common.Address]*big.Int{},
 		addressCounter:  map[common.Address]uint64{},
 		deliveredHeight: blockchain.CurrentBlock().NumberU64(),
+		confirmedTxs:    map[common.Hash]coreCommon.Hash{},
+		skippedTxs:      skippedTxs,
 	}
 }
 
-// validateNonce check if nonce is in order and return first nonce of every address.
-func (d *DexconApp) validateNonce(txs types.Transactions) (map[common.Address]uint64, error) {
-	addressFirstNonce := map[common.Address]uint64{}
-	addressNonce := map[common.Address]uint64{}
+// verifyWorkers is the number of workers checking the transactions of a block
+// in parallel.
+var verifyWorkers = runtime.NumCPU()
+
+// validateTxs recovers the senders of the transactions and checks their gas
+// prices and intrinsic gas in parallel. These checks do not depend on the
+// state, so they are done without holding appMu.
+func (d *DexconApp) validateTxs(txs types.Transactions, round uint64) ([]common.Address, error) {
+	var (
+		signer      = types.MakeSigner(d.blockchain.Config(), new(big.Int))
+		minGasPrice = d.gov.MinGasPrice(round)
+		senders     = make([]common.Address, len(txs))
+		errs        = make([]error, len(txs))
+		next        int64
+		failed      int32
+		wg          sync.WaitGroup
+	)
+	validate := func(tx *types.Transaction) (common.Address, error) {
+		if minGasPrice.Cmp(tx.GasPrice()) > 0 {
+			return common.Address{}, fmt.Errorf("gas price %v lower than %v", tx.GasPrice(), minGasPrice)
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return common.Address{}, err
+		}
+		intrGas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true)
+		if err != nil {
+			return common.Address{}, err
+		}
+		if tx.Gas() < intrGas {
+			return common.Address{}, fmt.Errorf("intrinsic gas too low: %d < %d", tx.Gas(), intrGas)
+		}
+		return sender, nil
+	}
+
+	workers := verifyWorkers
+	if workers > len(txs) {
+		workers = len(txs)
+	}
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for atomic.LoadInt32(&failed) == 0 {
+				i := int(atomic.AddInt64(&next, 1) - 1)
+				if i >= len(txs) {
+					return
+				}
+				senders[i], errs[i] = validate(txs[i])
+				if errs[i] != nil {
+					atomic.StoreInt32(&failed, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
 
-	for _, tx := range txs {
-		msg, err := tx.AsMessage(types.MakeSigner(d.blockchain.Config(), new(big.Int)))
+	for i, err := range errs {
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("tx %v: %v", txs[i].Hash(), err)
 		}
+	}
+	return senders, nil
+}
+
+// validateNonce check if nonce is in order and return first nonce of every address.
+func validateNonce(txs types.Transactions, senders []common.Address) (map[common.Address]uint64, error) {
+	addressFirstNonce := map[common.Address]uint64{}
+	addressNonce := map[common.Address]uint64{}
 
-		if _, exist := addressFirstNonce[msg.From()]; exist {
-			if addressNonce[msg.From()]+1 != msg.Nonce() {
+	for i, tx := range txs {
+		sender := senders[i]
+		if _, exist := addressFirstNonce[sender]; exist {
+			if addressNonce[sender]+1 != tx.Nonce() {
 				return nil, fmt.Errorf("address nonce check error: expect %v actual %v",
-					addressNonce[msg.From()]+1, msg.Nonce())
+					addressNonce[sender]+1, tx.Nonce())
 			}
-			addressNonce[msg.From()] = msg.Nonce()
+			addressNonce[sender] = tx.Nonce()
 		} else {
-			addressNonce[msg.From()] = msg.Nonce()
-			addressFirstNonce[msg.From()] = msg.Nonce()
+			addressNonce[sender] = tx.Nonce()
+			addressFirstNonce[sender] = tx.Nonce()
 		}
 	}
 	return addressFirstNonce, nil
 }
 
-// validateGasPrice checks if no gas price is lower than minGasPrice defined in
-// governance contract.
-func (d *DexconApp) validateGasPrice(txs types.Transactions, round uint64) bool {
-	minGasPrice := d.gov.MinGasPrice(round)
-	for _, tx := range txs {
-		if minGasPrice.Cmp(tx.GasPrice()) > 0 {
-			return false
-		}
-	}
-	return true
-}
-
 // PreparePayload is called when consensus core is preparing payload for block.
 func (d *DexconApp) PreparePayload(position coreTypes.Position) (payload []byte, err error) {
 	// softLimit limits the runtime of inner call to preparePayload.
@@ -180,24 +279,21 @@ func (d *DexconApp) preparePayload(ctx context.Context, position coreTypes.Posit
 		return
 	}
 
-	blockGasLimit := new(big.Int).SetUint64(d.gov.DexconConfiguration(position.Round).BlockGasLimit)
+	blockGasLimit := d.gov.DexconConfiguration(position.Round).BlockGasLimit
 	minGasPrice := d.gov.DexconConfiguration(position.Round).MinGasPrice
-	blockGasUsed := new(big.Int)
+	blockGasUsed := uint64(0)
 	allTxs := make([]*types.Transaction, 0, 10000)
 
-addressMap:
+	// Drop the transactions which are already confirmed, so the first
+	// transaction of every address is the one expected next.
+	balances := make(map[common.Address]*big.Int, len(txsMap))
 	for address, txs := range txsMap {
-		select {
-		case <-ctx.Done():
-			break addressMap
-		default:
-		}
-
 		balance := state.GetBalance(address)
 		cost, exist := d.addressCost[address]
 		if exist {
 			balance = new(big.Int).Sub(balance, cost)
 		}
+		balances[address] = balance
 
 		var expectNonce uint64
 		lastConfirmedNonce, exist := d.addressNonce[address]
@@ -207,49 +303,118 @@ addressMap:
 			expectNonce = lastConfirmedNonce + 1
 		}
 
-		if len(txs) == 0 {
+		// Warning: the pending tx will also affect by syncing, so the expected
+		// nonce maybe lower than the first nonce.
+		if len(txs) > 0 && expectNonce < txs[0].Nonce() {
+			d.skipTx(txs[0], SkipReasonNonceGap, position.Height)
+		}
+		if len(txs) == 0 || expectNonce < txs[0].Nonce() ||
+			expectNonce-txs[0].Nonce() >= uint64(len(txs)) {
+			delete(txsMap, address)
 			continue
 		}
+		txsMap[address] = txs[expectNonce-txs[0].Nonce():]
+	}
 
-		firstNonce := txs[0].Nonce()
-		startIndex := int(expectNonce - firstNonce)
+	// Pack the transactions by price while keeping the nonce order of every
+	// address.
+	signer := types.MakeSigner(d.blockchain.Config(), new(big.Int))
+	txsByPrice := types.NewTransactionsByPriceAndNonce(signer, txsMap)
 
-		// Warning: the pending tx will also affect by syncing, so startIndex maybe negative
-		for i := startIndex; i >= 0 && i < len(txs); i++ {
-			tx := txs[i]
-			if minGasPrice.Cmp(tx.GasPrice()) > 0 {
-				log.Error("Invalid gas price minGas(%v) > get(%v)", minGasPrice, tx.GasPrice())
-				break
-			}
+txLoop:
+	for {
+		select {
+		case <-ctx.Done():
+			break txLoop
+		default:
+		}
 
-			intrGas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true)
-			if err != nil {
-				log.Error("Failed to calculate intrinsic gas", "error", err)
-				return nil, fmt.Errorf("calculate intrinsic gas error: %v", err)
-			}
-			if tx.Gas() < intrGas {
-				log.Error("Intrinsic gas too low", "txHash", tx.Hash().String())
-				break
-			}
+		// No more transaction can fit in the block.
+		if blockGasLimit-blockGasUsed < params.TxGas {
+			break
+		}
 
-			balance = new(big.Int).Sub(balance, tx.Cost())
-			if balance.Cmp(big.NewInt(0)) < 0 {
-				log.Warn("Insufficient funds for gas * price + value", "txHash", tx.Hash().String())
-				break
-			}
+		tx := txsByPrice.Peek()
+		if tx == nil {
+			break
+		}
+		address, _ := types.Sender(signer, tx)
 
-			blockGasUsed = new(big.Int).Add(blockGasUsed, big.NewInt(int64(tx.Gas())))
-			if blockGasUsed.Cmp(blockGasLimit) > 0 {
-				break addressMap
-			}
+		if minGasPrice.Cmp(tx.GasPrice()) > 0 {
+			log.Error("Invalid gas price minGas(%v) > get(%v)", minGasPrice, tx.GasPrice())
+			d.skipTx(tx, SkipReasonGasPrice, position.Height)
+			txsByPrice.Pop()
+			continue
+		}
 
-			allTxs = append(allTxs, tx)
+		intrGas, err := core.IntrinsicGas(tx.Data(), tx.To() == nil, true)
+		if err != nil {
+			log.Error("Failed to calculate intrinsic gas", "error", err)
+			return nil, fmt.Errorf("calculate intrinsic gas error: %v", err)
 		}
+		if tx.Gas() < intrGas {
+			log.Error("Intrinsic gas too low", "txHash", tx.Hash().String())
+			d.skipTx(tx, SkipReasonIntrinsicGas, position.Height)
+			txsByPrice.Pop()
+			continue
+		}
+
+		balance := new(big.Int).Sub(balances[address], tx.Cost())
+		if balance.Cmp(big.NewInt(0)) < 0 {
+			log.Warn("Insufficient funds for gas * price + value", "txHash", tx.Hash().String())
+			d.skipTx(tx, SkipReasonInsufficientFunds, position.Height)
+			txsByPrice.Pop()
+			continue
+		}
+
+		// Skip the rest of the address if the transaction does not fit,
+		// smaller transactions of other addresses may still fit.
+		if tx.Gas() > blockGasLimit-blockGasUsed {
+			d.skipTx(tx, SkipReasonBlockGasLimit, position.Height)
+			txsByPrice.Pop()
+			continue
+		}
+
+		d.skippedTxs.Remove(tx.Hash())
+		balances[address] = balance
+		blockGasUsed += tx.Gas()
+		allTxs = append(allTxs, tx)
+		txsByPrice.Shift()
 	}
 
 	return rlp.EncodeToBytes(&allTxs)
 }
 
+// skipTx records the reason the transaction is skipped when preparing the
+// payload at the height.
+func (d *DexconApp) skipTx(tx *types.Transaction, reason string, height uint64) {
+	d.skippedTxs.Add(tx.Hash(), &txSkip{reason: reason, height: height, time: time.Now()})
+}
+
+// txSkipped returns why the proposer last skipped the transaction, if it was
+// skipped recently.
+func (d *DexconApp) txSkipped(hash common.Hash) (*txSkip, bool) {
+	skip, ok := d.skippedTxs.Get(hash)
+	if !ok {
+		return nil, false
+	}
+	return skip.(*txSkip), true
+}
+
+// txConfirmed returns the confirmed but undelivered block containing the
+// transaction.
+func (d *DexconApp) txConfirmed(hash common.Hash) (*coreTypes.Block, bool) {
+	d.appMu.RLock()
+	defer d.appMu.RUnlock()
+
+	blockHash, ok := d.confirmedTxs[hash]
+	if !ok {
+		return nil, false
+	}
+	block, _ := d.getConfirmedBlockByHash(blockHash)
+	return block, block != nil
+}
+
 // PrepareWitness will return the witness data no lower than consensusHeight.
 func (d *DexconApp) PrepareWitness(consensusHeight uint64) (witness coreTypes.Witness, err error) {
 	var witnessBlock *types.Block
@@ -305,15 +470,58 @@ func (d *DexconApp) VerifyBlock(block *coreTypes.Block) coreTypes.BlockVerifySta
 		return coreTypes.VerifyInvalidBlock
 	}
 
+	if !d.verifiable(block.Position) {
+		return coreTypes.VerifyRetryLater
+	}
+
+	// Check the transactions which do not depend on the state before
+	// acquiring the lock.
+	var (
+		transactions types.Transactions
+		senders      []common.Address
+		addressNonce map[common.Address]uint64
+	)
+	if len(block.Payload) != 0 {
+		err = rlp.DecodeBytes(block.Payload, &transactions)
+		if err != nil {
+			log.Error("Payload rlp decode", "error", err)
+			return coreTypes.VerifyInvalidBlock
+		}
+
+		senders, err = d.validateTxs(transactions, block.Position.Round)
+		if err != nil {
+			log.Error("Validate transactions failed", "error", err)
+			return coreTypes.VerifyInvalidBlock
+		}
+		// The senders are cached in the transactions, cache them globally
+		// for the block to be confirmed.
+		types.GlobalSigCache.Add(types.NewEIP155Signer(d.blockchain.Config().ChainID), transactions)
+
+		addressNonce, err = validateNonce(transactions, senders)
+		if err != nil {
+			log.Error("Validate nonce failed", "error", err)
+			return coreTypes.VerifyInvalidBlock
+		}
+
+		var blockGasUsed uint64
+		blockGasLimit := d.gov.DexconConfiguration(block.Position.Round).BlockGasLimit
+		for _, tx := range transactions {
+			blockGasUsed += tx.Gas()
+			if blockGasUsed < tx.Gas() || blockGasUsed > blockGasLimit {
+				log.Error("Reach block gas limit", "gasUsed", blockGasUsed)
+				return coreTypes.VerifyInvalidBlock
+			}
+		}
+	}
+
 	d.appMu.RLock()
 	defer d.appMu.RUnlock()
 
-	// deliver height + 1 = position height
+	// Blocks may be confirmed while checking the transactions.
 	if d.deliveredHeight+d.undeliveredNum+1 != block.Position.Height {
 		return coreTypes.VerifyRetryLater
 	}
 
-	var transactions types.Transactions
 	if len(block.Payload) == 0 {
 		return coreTypes.VerifyOK
 	}
@@ -324,28 +532,6 @@ func (d *DexconApp) VerifyBlock(block *coreTypes.Block) coreTypes.BlockVerifySta
 		return coreTypes.VerifyInvalidBlock
 	}
 
-	err = rlp.DecodeBytes(block.Payload, &transactions)
-	if err != nil {
-		log.Error("Payload rlp decode", "error", err)
-		return coreTypes.VerifyInvalidBlock
-	}
-
-	_, err = types.GlobalSigCache.Add(types.NewEIP155Signer(d.blockchain.Config().ChainID), transactions)
-	if err != nil {
-		log.Error("Failed to calculate sender", "error", err)
-		return coreTypes.VerifyInvalidBlock
-	}
-
-	addressNonce, err := d.validateNonce(transactions)
-	if err != nil {
-		log.Error("Validate nonce failed", "error", err)
-		return coreTypes.VerifyInvalidBlock
-	}
-	if !d.validateGasPrice(transactions, block.Position.Round) {
-		log.Error("Validate gas price failed")
-		return coreTypes.VerifyInvalidBlock
-	}
-
 	for address, firstNonce := range addressNonce {
 		var expectNonce uint64
 		nonce, exist := d.addressNonce[address]
@@ -373,43 +559,28 @@ func (d *DexconApp) VerifyBlock(block *coreTypes.Block) coreTypes.BlockVerifySta
 	}
 
 	// Validate if balance is enough for TXs in this block.
-	blockGasLimit := new(big.Int).SetUint64(d.gov.DexconConfiguration(block.Position.Round).BlockGasLimit)
-	blockGasUsed := new(big.Int)
-
-	for _, tx := range transactions {
-		msg, err := tx.AsMessage(types.MakeSigner(d.blockchain.Config(), new(big.Int)))
-		if err != nil {
-			log.Error("Failed to convert tx to message", "error", err)
-			return coreTypes.VerifyInvalidBlock
-		}
-		balance := addressesBalance[msg.From()]
-		intrGas, err := core.IntrinsicGas(msg.Data(), msg.To() == nil, true)
-		if err != nil {
-			log.Error("Failed to calculate intrinsic gas", "err", err)
-			return coreTypes.VerifyInvalidBlock
-		}
-		if tx.Gas() < intrGas {
-			log.Error("Intrinsic gas too low", "txHash", tx.Hash().String(), "intrinsic", intrGas, "gas", tx.Gas())
-			return coreTypes.VerifyInvalidBlock
-		}
-
-		balance = new(big.Int).Sub(balance, tx.Cost())
-		if balance.Cmp(big.NewInt(0)) < 0 {
+	for i, tx := range transactions {
+		balance := new(big.Int).Sub(addressesBalance[senders[i]], tx.Cost())
+		if balance.Sign() < 0 {
 			log.Error("Insufficient funds for gas * price + value", "txHash", tx.Hash().String())
 			return coreTypes.VerifyInvalidBlock
 		}
-
-		blockGasUsed = new(big.Int).Add(blockGasUsed, new(big.Int).SetUint64(tx.Gas()))
-		if blockGasUsed.Cmp(blockGasLimit) > 0 {
-			log.Error("Reach block gas limit", "gasUsed", blockGasUsed)
-			return coreTypes.VerifyInvalidBlock
-		}
-		addressesBalance[msg.From()] = balance
+		addressesBalance[senders[i]] = balance
 	}
 
 	return coreTypes.VerifyOK
 }
 
+// verifiable tells whether the block at the position is the next block to be
+// confirmed.
+func (d *DexconApp) verifiable(position coreTypes.Position) bool {
+	d.appMu.RLock()
+	defer d.appMu.RUnlock()
+
+	// deliver height + 1 = position height
+	return d.deliveredHeight+d.undeliveredNum+1 == position.Height
+}
+
 // BlockDelivered is called when a block is add to the compaction chain.
 func (d *DexconApp) BlockDelivered(
 	blockHash coreCommon.Hash,
@@ -434,14 +605,9 @@ func (d *DexconApp) BlockDelivered(
 		panic(err)
 	}
 
-	var owner common.Address
-	if !block.IsEmpty() {
-		gs := d.gov.GetStateForConfigAtRound(block.Position.Round)
-		node, err := gs.GetNodeByID(block.ProposerID)
-		if err != nil {
-			panic(err)
-		}
-		owner = node.Owner
+	owner, err := d.blockOwner(block)
+	if err != nil {
+		panic(err)
 	}
 
 	newBlock := types.NewBlock(&types.Header{
@@ -476,6 +642,121 @@ func (d *DexconApp) BlockDelivered(
 	go d.finalizedBlockFeed.Send(core.NewFinalizedBlockEvent{Block: d.blockchain.CurrentBlock()})
 }
 
+// blockOwner returns the owner of the node proposing the block, the zero
+// address if the block is empty.
+func (d *DexconApp) blockOwner(block *coreTypes.Block) (common.Address, error) {
+	if block.IsEmpty() {
+		return common.Address{}, nil
+	}
+	gs := d.gov.GetStateForConfigAtRound(block.Position.Round)
+	node, err := gs.GetNodeByID(block.ProposerID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return node.Owner, nil
+}
+
+// pendingState returns the state of the current block with the transactions
+// of the confirmed but undelivered blocks applied, and the header of the last
+// of these blocks. The randomness of the blocks is not known yet, and block
+// rewards are not applied. The state is cached until the confirmed blocks or
+// the current block change, and is the state of the current block if the
+// transactions fail to apply.
+func (d *DexconApp) pendingState() (*state.StateDB, *types.Header, error) {
+	d.appMu.RLock()
+	gen := d.confirmedGen
+	head := d.blockchain.CurrentBlock().Header()
+	infos := make([]*blockInfo, 0, len(d.confirmedBlocks))
+	for _, info := range d.confirmedBlocks {
+		infos = append(infos, info)
+	}
+	d.appMu.RUnlock()
+
+	d.pendingStateMu.Lock()
+	defer d.pendingStateMu.Unlock()
+
+	cache := d.pendingStateCache
+	if cache == nil || cache.gen != gen || cache.head != head.Hash() {
+		statedb, header, err := d.applyConfirmedBlocks(head, infos)
+		if err != nil {
+			// Serve the current state rather than failing the request.
+			log.Debug("Failed to apply confirmed blocks to pending state", "err", err)
+			statedb, err = d.blockchain.StateAt(head.Root)
+			if err != nil {
+				return nil, nil, err
+			}
+			header = head
+		}
+		cache = &pendingStateCache{
+			gen:     gen,
+			head:    head.Hash(),
+			statedb: statedb,
+			header:  header,
+		}
+		d.pendingStateCache = cache
+	}
+	return cache.statedb.Copy(), types.CopyHeader(cache.header), nil
+}
+
+// applyConfirmedBlocks applies the transactions of the confirmed blocks to
+// the state of the head block in order of height.
+func (d *DexconApp) applyConfirmedBlocks(head *types.Header, infos []*blockInfo) (
+	*state.StateDB, *types.Header, error) {
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].block.Position.Height < infos[j].block.Position.Height
+	})
+
+	statedb, err := d.blockchain.StateAt(head.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	header := head
+	for _, info := range infos {
+		block := info.block
+		owner, err := d.blockOwner(block)
+		if err != nil {
+			return nil, nil, err
+		}
+		header = &types.Header{
+			ParentHash: header.Hash(),
+			Number:     new(big.Int).SetUint64(block.Position.Height),
+			Time:       uint64(block.Timestamp.UnixNano() / 1000000),
+			Coinbase:   owner,
+			GasLimit:   d.gov.DexconConfiguration(block.Position.Round).BlockGasLimit,
+			Difficulty: big.NewInt(1),
+			Round:      block.Position.Round,
+		}
+
+		var (
+			gp      = new(core.GasPool).AddGas(math.MaxUint64)
+			usedGas uint64
+		)
+		for i, tx := range info.txs {
+			statedb.Prepare(tx.Hash(), common.Hash{}, i)
+			_, _, err := core.ApplyTransaction(d.blockchain.Config(), d.blockchain, nil, gp,
+				statedb, header, tx, &usedGas, *d.blockchain.GetVMConfig())
+			if err != nil {
+				return nil, nil, fmt.Errorf("apply transaction %x error: %v", tx.Hash(), err)
+			}
+		}
+		header.GasUsed = usedGas
+	}
+	return statedb, header, nil
+}
+
+// pendingNonce returns the nonce of the address after the confirmed but
+// undelivered blocks, false if the address sends no transactions in them.
+func (d *DexconApp) pendingNonce(address common.Address) (uint64, bool) {
+	d.appMu.RLock()
+	defer d.appMu.RUnlock()
+
+	nonce, exist := d.addressNonce[address]
+	if !exist {
+		return 0, false
+	}
+	return nonce + 1, true
+}
+
 // BlockConfirmed is called when a block is confirmed.
 func (d *DexconApp) BlockConfirmed(block coreTypes.Block) {
 	propBlockConfirmLatency.Update(time.Since(block.Timestamp).Nanoseconds() / 1000)
@@ -542,6 +823,10 @@ func (d *DexconApp) addConfirmedBlock(block *coreTypes.Block) error {
 	for addr := range addressMap {
 		d.addressCounter[addr]++
 	}
+	for _, tx := range transactions {
+		d.confirmedTxs[tx.Hash()] = block.Hash
+		d.skippedTxs.Remove(tx.Hash())
+	}
 
 	d.confirmedBlocks[block.Hash] = &blockInfo{
 		addresses: addressMap,
@@ -550,6 +835,7 @@ func (d *DexconApp) addConfirmedBlock(block *coreTypes.Block) error {
 	}
 
 	d.undeliveredNum++
+	d.confirmedGen++
 	return nil
 }
 
@@ -565,8 +851,12 @@ func (d *DexconApp) removeConfirmedBlock(hash coreCommon.Hash) {
 		}
 	}
 
+	for _, tx := range blockInfo.txs {
+		delete(d.confirmedTxs, tx.Hash())
+	}
 	delete(d.confirmedBlocks, hash)
 	d.undeliveredNum--
+	d.confirmedGen++
 }
 
 func (d *DexconApp) getConfirmedBlockByHash(hash coreCommon.Hash) (*coreTypes.Block, types.Transactions) {
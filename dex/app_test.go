@@ -1,11 +1,13 @@
 package dex
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
 	"math/rand"
 	"reflect"
+	"runtime"
 	"sync"
 	"testing"
 	"time"
@@ -25,6 +27,7 @@ import (
 	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/event"
 	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
 )
 
 type singnal int
@@ -2292,7 +2295,232 @@ func TestDexonApp(t *testing.T) {
 	}
 }
 
+func TestPreparePayloadOrdering(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Generate key fail: %v", err)
+	}
+
+	dex, keys, err := newDexon(masterKey, 3)
+	if err != nil {
+		t.Fatalf("New dexon fail: %v", err)
+	}
+
+	signer := types.NewEIP155Signer(dex.chainConfig.ChainID)
+	minGasPrice := dex.chainConfig.Dexcon.MinGasPrice
+	newTx := func(key *ecdsa.PrivateKey, nonce uint64, gas uint64, price int64) *types.Transaction {
+		tx, err := types.SignTx(types.NewTransaction(nonce, common.Address{}, nil, gas,
+			new(big.Int).Mul(minGasPrice, big.NewInt(price)), nil), signer, key)
+		if err != nil {
+			t.Fatalf("Sign tx fail: %v", err)
+		}
+		return tx
+	}
+
+	// The transaction of the second account is too large to fit in the block
+	// after the first transaction is packed.
+	blockGasLimit := dex.chainConfig.Dexcon.BlockGasLimit
+	txs := []*types.Transaction{
+		newTx(keys[0], 0, 21000, 4),
+		newTx(keys[0], 1, 21000, 1),
+		newTx(keys[1], 0, blockGasLimit-21000+1, 3),
+		newTx(keys[2], 0, 21000, 2),
+	}
+	for _, err := range dex.txPool.AddRemotes(txs) {
+		if err != nil {
+			t.Fatalf("Add tx fail: %v", err)
+		}
+	}
+
+	payload, err := dex.app.preparePayload(context.Background(), coreTypes.Position{Height: 1})
+	if err != nil {
+		t.Fatalf("Prepare payload fail: %v", err)
+	}
+
+	var packed []*types.Transaction
+	if err := rlp.DecodeBytes(payload, &packed); err != nil {
+		t.Fatalf("Decode payload fail: %v", err)
+	}
+
+	expected := []*types.Transaction{txs[0], txs[3], txs[1]}
+	if len(packed) != len(expected) {
+		t.Fatalf("Packed tx count mismatch: have %d, want %d", len(packed), len(expected))
+	}
+	for i, tx := range packed {
+		if tx.Hash() != expected[i].Hash() {
+			t.Errorf("Packed tx %d mismatch: have %v, want %v", i, tx.Hash(), expected[i].Hash())
+		}
+	}
+
+	// Nothing is packed after the deadline.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	payload, err = dex.app.preparePayload(ctx, coreTypes.Position{Height: 1})
+	if err != nil {
+		t.Fatalf("Prepare payload fail: %v", err)
+	}
+	if len(payload) != 0 {
+		t.Errorf("Payload prepared after deadline: %x", payload)
+	}
+}
+
+func TestPendingState(t *testing.T) {
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("Generate key fail: %v", err)
+	}
+
+	dex, keys, err := newDexon(masterKey, 1)
+	if err != nil {
+		t.Fatalf("New dexon fail: %v", err)
+	}
+	backend := dex.APIBackend
+	sender := crypto.PubkeyToAddress(keys[0].PublicKey)
+	recipient := common.Address{0x01}
+	value := big.NewInt(1000)
+
+	signer := types.NewEIP155Signer(dex.chainConfig.ChainID)
+	var txs types.Transactions
+	for nonce := uint64(0); nonce < 2; nonce++ {
+		tx, err := types.SignTx(types.NewTransaction(nonce, recipient, value, 21000,
+			dex.chainConfig.Dexcon.MinGasPrice, nil), signer, keys[0])
+		if err != nil {
+			t.Fatalf("Sign tx fail: %v", err)
+		}
+		txs = append(txs, tx)
+	}
+	payload, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		t.Fatalf("Encode payload fail: %v", err)
+	}
+	witnessData, err := rlp.EncodeToBytes(dex.blockchain.Genesis().Hash())
+	if err != nil {
+		t.Fatalf("Encode witness fail: %v", err)
+	}
+	block := coreTypes.Block{
+		ProposerID: coreTypes.NewNodeID(coreEcdsa.NewPrivateKeyFromECDSA(masterKey).PublicKey()),
+		Hash:       coreCommon.NewRandomHash(),
+		Position:   coreTypes.Position{Height: 1},
+		Timestamp:  time.Now(),
+		Payload:    payload,
+		Witness:    coreTypes.Witness{Height: 0, Data: witnessData},
+	}
+	dex.app.BlockConfirmed(block)
+
+	check := func(blockNr rpc.BlockNumber, nonce uint64, balance *big.Int, number uint64) {
+		statedb, header, err := backend.StateAndHeaderByNumber(context.Background(), blockNr)
+		if err != nil {
+			t.Fatalf("Get state fail: %v", err)
+		}
+		if have := statedb.GetNonce(sender); have != nonce {
+			t.Errorf("Nonce mismatch at %d: have %d, want %d", blockNr, have, nonce)
+		}
+		if have := statedb.GetBalance(recipient); have.Cmp(balance) != 0 {
+			t.Errorf("Balance mismatch at %d: have %v, want %v", blockNr, have, balance)
+		}
+		if header.Number.Uint64() != number {
+			t.Errorf("Header number mismatch at %d: have %d, want %d", blockNr, header.Number, number)
+		}
+	}
+	transferred := new(big.Int).Mul(value, big.NewInt(2))
+	check(rpc.LatestBlockNumber, 0, big.NewInt(0), 0)
+	check(rpc.PendingBlockNumber, 2, transferred, 1)
+
+	// The transactions are not in the pool.
+	nonce, err := backend.GetPoolNonce(context.Background(), sender)
+	if err != nil {
+		t.Fatalf("Get pool nonce fail: %v", err)
+	}
+	if nonce != 2 {
+		t.Errorf("Pool nonce mismatch: have %d, want %d", nonce, 2)
+	}
+
+	// The cached state is not modified by callers.
+	statedb, _, err := backend.StateAndHeaderByNumber(context.Background(), rpc.PendingBlockNumber)
+	if err != nil {
+		t.Fatalf("Get state fail: %v", err)
+	}
+	statedb.SetNonce(sender, 100)
+	check(rpc.PendingBlockNumber, 2, transferred, 1)
+
+	dex.app.BlockDelivered(block.Hash, block.Position, []byte{1})
+	check(rpc.LatestBlockNumber, 2, transferred, 1)
+	check(rpc.PendingBlockNumber, 2, transferred, 1)
+
+	// The current state is served if the transactions fail to apply.
+	tx, err := types.SignTx(types.NewTransaction(5, recipient, value, 21000,
+		dex.chainConfig.Dexcon.MinGasPrice, nil), signer, keys[0])
+	if err != nil {
+		t.Fatalf("Sign tx fail: %v", err)
+	}
+	payload, err = rlp.EncodeToBytes(types.Transactions{tx})
+	if err != nil {
+		t.Fatalf("Encode payload fail: %v", err)
+	}
+	block.Hash = coreCommon.NewRandomHash()
+	block.Position.Height = 2
+	block.Payload = payload
+	dex.app.BlockConfirmed(block)
+	check(rpc.PendingBlockNumber, 2, transferred, 1)
+}
+
+// BenchmarkVerifyBlock verifies blocks of 2000 transactions, checked by as
+// many workers as GOMAXPROCS, so run it with -cpu to compare worker counts.
+func BenchmarkVerifyBlock(b *testing.B) {
+	defer func(workers int) { verifyWorkers = workers }(verifyWorkers)
+	verifyWorkers = runtime.GOMAXPROCS(0)
+	txNum := 2000
+
+	masterKey, err := crypto.GenerateKey()
+	if err != nil {
+		b.Fatalf("Generate key fail: %v", err)
+	}
+	dex, keys, err := newDexonWithBlockGasLimit(masterKey, txNum/10, uint64(txNum)*21000)
+	if err != nil {
+		b.Fatalf("New dexon fail: %v", err)
+	}
+	witnessData, err := rlp.EncodeToBytes(dex.blockchain.Genesis().Hash())
+	if err != nil {
+		b.Fatalf("Encode witness fail: %v", err)
+	}
+	signer := types.NewEIP155Signer(dex.chainConfig.ChainID)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		// Sign new transactions in every round, as the senders are cached.
+		b.StopTimer()
+		txs := make(types.Transactions, txNum)
+		for j := range txs {
+			key := keys[j%len(keys)]
+			txs[j], err = types.SignTx(types.NewTransaction(uint64(j/len(keys)), common.Address{},
+				big.NewInt(int64(i)), 21000, dex.chainConfig.Dexcon.MinGasPrice, nil), signer, key)
+			if err != nil {
+				b.Fatalf("Sign tx fail: %v", err)
+			}
+		}
+		payload, err := rlp.EncodeToBytes(txs)
+		if err != nil {
+			b.Fatalf("Encode payload fail: %v", err)
+		}
+		block := &coreTypes.Block{
+			Position: coreTypes.Position{Height: 1},
+			Payload:  payload,
+			Witness:  coreTypes.Witness{Height: 0, Data: witnessData},
+		}
+		b.StartTimer()
+
+		if status := dex.app.VerifyBlock(block); status != coreTypes.VerifyOK {
+			b.Fatalf("Verify block fail: %v", status)
+		}
+	}
+}
+
 func newDexon(masterKey *ecdsa.PrivateKey, accountNum int) (*Dexon, []*ecdsa.PrivateKey, error) {
+	return newDexonWithBlockGasLimit(masterKey, accountNum, 2000000)
+}
+
+func newDexonWithBlockGasLimit(masterKey *ecdsa.PrivateKey, accountNum int,
+	blockGasLimit uint64) (*Dexon, []*ecdsa.PrivateKey, error) {
 	db := ethdb.NewMemDatabase()
 
 	genesis := core.DefaultTestnetGenesisBlock()
@@ -2316,7 +2544,7 @@ func newDexon(masterKey *ecdsa.PrivateKey, accountNum int) (*Dexon, []*ecdsa.Pri
 		accounts = append(accounts, key)
 	}
 
-	genesis.Config.Dexcon.BlockGasLimit = 2000000
+	genesis.Config.Dexcon.BlockGasLimit = blockGasLimit
 	genesis.Config.Dexcon.RoundLength = 600
 	genesis.Config.Dexcon.Owner = crypto.PubkeyToAddress(masterKey.PublicKey)
 
@@ -2346,7 +2574,8 @@ func newDexon(masterKey *ecdsa.PrivateKey, accountNum int) (*Dexon, []*ecdsa.Pri
 	dex.txPool = core.NewTxPool(txPoolConfig, chainConfig, dex.blockchain)
 
 	dex.APIBackend = &DexAPIBackend{dex, nil}
-	dex.governance = NewDexconGovernance(dex.APIBackend, dex.chainConfig, config.PrivateKey)
+	dex.signer = NewKeySigner(config.PrivateKey)
+	dex.governance = NewDexconGovernance(dex.APIBackend, dex.chainConfig, dex.signer, nil)
 	engine.SetGovStateFetcher(dex.governance)
 	dex.app = NewDexconApp(dex.txPool, dex.blockchain, dex.governance, db, &config)
 
@@ -18,17 +18,22 @@
 package dex
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/dexon-foundation/dexon-consensus/core/syncer"
 	"github.com/dexon-foundation/dexon/accounts"
+	"github.com/dexon-foundation/dexon/accounts/keystore"
 	"github.com/dexon-foundation/dexon/consensus"
 	"github.com/dexon-foundation/dexon/consensus/dexcon"
 	"github.com/dexon-foundation/dexon/core"
 	"github.com/dexon-foundation/dexon/core/bloombits"
 	"github.com/dexon-foundation/dexon/core/rawdb"
 	"github.com/dexon-foundation/dexon/core/vm"
+	dexDB "github.com/dexon-foundation/dexon/dex/db"
 	"github.com/dexon-foundation/dexon/dex/downloader"
 	"github.com/dexon-foundation/dexon/eth/filters"
 	"github.com/dexon-foundation/dexon/eth/gasprice"
@@ -58,6 +63,7 @@ type Dexon struct {
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
+	coreDb  *dexDB.DB      // Consensus core database over the chain database
 
 	eventMux       *event.TypeMux
 	engine         consensus.Engine
@@ -69,6 +75,7 @@ type Dexon struct {
 	APIBackend *DexAPIBackend
 
 	// Dexon consensus.
+	signer     ConsensusSigner
 	app        *DexconApp
 	governance *DexconGovernance
 	network    *DexconNetwork
@@ -117,6 +124,35 @@ func New(ctx *node.ServiceContext, config *Config) (*Dexon, error) {
 		engine:         engine,
 	}
 
+	// Encrypt DKG private keys at rest if a passphrase is given, including
+	// the ones stored in plaintext by previous versions. Once encrypted, the
+	// node does not start without the passphrase.
+	if config.DKGKeyPassphrase != "" {
+		scryptN, scryptP := keystore.StandardScryptN, keystore.StandardScryptP
+		if ctx.Config != nil && ctx.Config.UseLightweightKDF {
+			scryptN, scryptP = keystore.LightScryptN, keystore.LightScryptP
+		}
+		coreDb, err := dexDB.NewEncryptedDatabase(chainDb, config.DKGKeyPassphrase, scryptN, scryptP)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open DKG private key encryption: %v", err)
+		}
+		dex.coreDb = coreDb
+		count, err := dex.coreDb.EncryptDKGPrivateKeys()
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt DKG private keys: %v", err)
+		}
+		if count > 0 {
+			log.Info("Encrypted plaintext DKG records", "count", count)
+		}
+	} else if len(rawdb.ReadCoreDKGKeyEncryption(chainDb)) > 0 {
+		return nil, errors.New("DKG private keys are encrypted, DKG key password required")
+	} else {
+		dex.coreDb = dexDB.NewDatabase(chainDb)
+		if config.BlockProposerEnabled {
+			log.Warn("DKG private keys are stored unencrypted, set a DKG key password to encrypt them")
+		}
+	}
+
 	var (
 		vmConfig = vm.Config{
 			EnablePreimageRecording: config.EnablePreimageRecording,
@@ -127,6 +163,10 @@ func New(ctx *node.ServiceContext, config *Config) (*Dexon, error) {
 		cacheConfig = &core.CacheConfig{Disabled: config.NoPruning, TrieCleanLimit: config.TrieCleanCache, TrieDirtyLimit: config.TrieDirtyCache, TrieTimeLimit: config.TrieTimeout}
 	)
 	dex.blockchain, err = core.NewBlockChain(chainDb, cacheConfig, dex.chainConfig, dex.engine, vmConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+	dex.blockchain.SetLenientVerification(config.LenientHeaderVerification)
 
 	// Rewind the chain in case of an incompatible config upgrade.
 	if compat, ok := genesisErr.(*params.ConfigCompatError); ok {
@@ -137,11 +177,16 @@ func New(ctx *node.ServiceContext, config *Config) (*Dexon, error) {
 	dex.bloomIndexer.Start(dex.blockchain)
 
 	if config.Indexer.Enable {
-		dex.indexer = indexer.NewIndexerFromConfig(
+		dex.indexer, err = indexer.NewIndexerFromConfig(
 			indexer.NewROBlockChain(dex.blockchain),
 			config.Indexer,
 		)
-		dex.indexer.Start()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create indexer: %v", err)
+		}
+		if err := dex.indexer.Start(); err != nil {
+			return nil, fmt.Errorf("failed to start indexer: %v", err)
+		}
 	}
 
 	if config.TxPool.Journal != "" {
@@ -157,11 +202,31 @@ func New(ctx *node.ServiceContext, config *Config) (*Dexon, error) {
 	dex.APIBackend.gpo = gasprice.NewOracle(dex.APIBackend, gpoParams)
 
 	// Dexcon related objects.
-	dex.governance = NewDexconGovernance(dex.APIBackend, dex.chainConfig, config.PrivateKey)
+	dex.signer, err = newConsensusSigner(ctx.AccountManager, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create consensus signer: %v", err)
+	}
+	operator, err := newOperatorSigner(ctx.AccountManager, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create operator signer: %v", err)
+	}
+	if ctx.ServerConfig != nil {
+		if err := checkConsensusKey(chainConfig, dex.blockchain.CurrentBlock().Number(),
+			ctx.ServerConfig.PrivateKey, dex.signer.Address()); err != nil {
+			return nil, err
+		}
+	}
+	log.Info("Consensus signer", "address", dex.signer.Address())
+	if operator != nil {
+		log.Info("Governance operator", "address", operator.Address())
+	}
+	dex.governance = NewDexconGovernance(dex.APIBackend, dex.chainConfig, dex.signer, operator)
 	dex.app = NewDexconApp(dex.txPool, dex.blockchain, dex.governance, chainDb, config)
 
 	// Set config fetcher so engine can fetch current system configuration from state.
 	engine.SetGovStateFetcher(dex.governance)
+	engine.SetTSigVerifierCache(dex.blockchain.TSigVerifierCache())
+	engine.SetLenientVerification(config.LenientHeaderVerification)
 
 	dMoment := time.Unix(int64(chainConfig.DMoment), 0)
 	log.Info("Consensus DMoment", "dMoment", dMoment)
@@ -181,8 +246,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Dexon, error) {
 	dex.protocolManager = pm
 	dex.network = NewDexconNetwork(pm)
 
-	recovery := NewRecovery(chainConfig.Recovery, config.RecoveryNetworkRPC,
-		dex.governance, config.PrivateKey)
+	recovery := NewRecovery(chainConfig.Recovery,
+		NewRPCRecoveryBackend(config.RecoveryNetworkRPC),
+		dex.governance, dex.signer)
 	watchCat := syncer.NewWatchCat(recovery, dex.governance, 10*time.Second,
 		time.Duration(chainConfig.Recovery.Timeout)*time.Second, log.Root())
 
@@ -221,6 +287,16 @@ func (s *Dexon) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   NewPublicDebugAPI(s),
 			Public:    true,
+		}, {
+			Namespace: "governance",
+			Version:   "1.0",
+			Service:   NewPublicGovernanceAPI(s),
+			Public:    true,
+		}, {
+			Namespace: "dex",
+			Version:   "1.0",
+			Service:   NewPublicDexAPI(s),
+			Public:    true,
 		}, {
 			Namespace: "debug",
 			Version:   "1.0",
@@ -296,6 +372,25 @@ func (s *Dexon) IsProposing() bool {
 	return s.bp.IsProposing()
 }
 
+func (s *Dexon) NotaryInfo() (*NotaryInfo, error) {
+	return s.protocolManager.NotaryInfo()
+}
+
+// NotaryStats reports whether the node is in the current and the next notary
+// set, and the size of the current notary set.
+func (s *Dexon) NotaryStats() (isNotary, isNextNotary bool, size int, err error) {
+	info, err := s.NotaryInfo()
+	if err != nil {
+		return false, false, 0, err
+	}
+	return info.IsNotary, info.IsNextNotary, len(info.Nodes), nil
+}
+
+// SuggestPrice returns the gas price currently suggested to transaction senders.
+func (s *Dexon) SuggestPrice(ctx context.Context) (*big.Int, error) {
+	return s.APIBackend.SuggestPrice(ctx)
+}
+
 // CreateDB creates the chain database.
 func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Database, error) {
 	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
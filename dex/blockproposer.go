@@ -8,12 +8,10 @@ import (
 	"time"
 
 	dexCore "github.com/dexon-foundation/dexon-consensus/core"
-	coreEcdsa "github.com/dexon-foundation/dexon-consensus/core/crypto/ecdsa"
 	"github.com/dexon-foundation/dexon-consensus/core/syncer"
 	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
 
 	"github.com/dexon-foundation/dexon/core"
-	"github.com/dexon-foundation/dexon/dex/db"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/rlp"
 )
@@ -111,10 +109,8 @@ func (b *blockProposer) IsProposing() bool {
 }
 
 func (b *blockProposer) initConsensus() *dexCore.Consensus {
-	db := db.NewDatabase(b.dex.chainDb)
-	privkey := coreEcdsa.NewPrivateKeyFromECDSA(b.dex.config.PrivateKey)
 	return dexCore.NewConsensus(b.dMoment,
-		b.dex.app, b.dex.governance, db, b.dex.network, privkey, log.Root())
+		b.dex.app, b.dex.governance, b.dex.coreDb, b.dex.network, b.dex.signer, log.Root())
 }
 
 func (b *blockProposer) syncConsensus() (*dexCore.Consensus, error) {
@@ -123,10 +119,8 @@ func (b *blockProposer) syncConsensus() (*dexCore.Consensus, error) {
 
 	cb := b.dex.blockchain.CurrentBlock()
 
-	db := db.NewDatabase(b.dex.chainDb)
-	privkey := coreEcdsa.NewPrivateKeyFromECDSA(b.dex.config.PrivateKey)
 	consensusSync := syncer.NewConsensus(cb.NumberU64(), b.dMoment, b.dex.app,
-		b.dex.governance, db, b.dex.network, privkey, log.Root())
+		b.dex.governance, b.dex.coreDb, b.dex.network, b.dex.signer, log.Root())
 
 	// Start the watchCat.
 	b.watchCat.Start()
@@ -157,7 +151,7 @@ func (b *blockProposer) syncConsensus() (*dexCore.Consensus, error) {
 	}
 
 	// Sync all blocks in compaction chain to core.
-	_, coreHeight := db.GetCompactionChainTipInfo()
+	_, coreHeight := b.dex.coreDb.GetCompactionChainTipInfo()
 
 Loop:
 	for {
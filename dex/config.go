@@ -74,9 +74,23 @@ type Config struct {
 	// If nil, the Ethereum main net block is used.
 	Genesis *core.Genesis `toml:",omitempty"`
 
-	// PrivateKey, also represents the node identity.
+	// PrivateKey signs consensus messages. It is the p2p node key unless a
+	// separate consensus key or account is configured.
 	PrivateKey *ecdsa.PrivateKey `toml:",omitempty"`
 
+	// ConsensusAccount is the keystore account signing consensus messages
+	// instead of PrivateKey, unlocked with ConsensusPassphrase.
+	ConsensusAccount    common.Address `toml:",omitempty"`
+	ConsensusPassphrase string         `toml:"-"`
+
+	// OperatorAccount sends the governance transactions of the node after the
+	// node key separation fork, signed by the external signer at
+	// OperatorSigner if set, or by the keystore unlocked with
+	// OperatorPassphrase. The consensus key sends them if it is not set.
+	OperatorAccount    common.Address `toml:",omitempty"`
+	OperatorPassphrase string         `toml:"-"`
+	OperatorSigner     string         `toml:",omitempty"`
+
 	// Protocol options
 	NetworkId uint64 // Network ID to use for selecting peers to connect to
 	SyncMode  downloader.SyncMode
@@ -133,4 +147,13 @@ type Config struct {
 
 	// Recovery network RPC
 	RecoveryNetworkRPC string
+
+	// DKGKeyPassphrase encrypts the DKG private keys stored in the chain
+	// database. Keys are stored in plaintext if it is empty.
+	DKGKeyPassphrase string `toml:"-"`
+
+	// LenientHeaderVerification only logs headers with invalid randomness,
+	// round or reward instead of rejecting them. It is a compatibility option
+	// for nodes which synced before these rules were enforced.
+	LenientHeaderVerification bool
 }
@@ -18,23 +18,121 @@
 package db
 
 import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+
 	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
 	coreDKG "github.com/dexon-foundation/dexon-consensus/core/crypto/dkg"
 	coreDb "github.com/dexon-foundation/dexon-consensus/core/db"
 	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
 
+	"github.com/dexon-foundation/dexon/accounts/keystore"
 	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
 	"github.com/dexon-foundation/dexon/core/rawdb"
+	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/ethdb"
+	"github.com/dexon-foundation/dexon/rlp"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	// ErrDKGPrivateKeyLocked is returned when reading an encrypted DKG
+	// private key or DKG protocol without the passphrase.
+	ErrDKGPrivateKeyLocked = errors.New("dkg private key encrypted, passphrase required")
+
+	errNoDKGKeyPassphrase = errors.New("no dkg private key passphrase")
+	errShortDKGRecord     = errors.New("encrypted dkg record too short")
+)
+
+// dkgProtocolAD is the additional data authenticated with the DKG protocol,
+// distinct from the ones of DKG private keys.
+var dkgProtocolAD = []byte("CoreDKGProtocol")
+
+const (
+	dkgKeyScryptR     = 8
+	dkgKeyScryptDKLen = 32
+	dkgKeySaltLen     = 32
 )
 
 // DB implement dexon-consensus BlockDatabase interface.
 type DB struct {
-	db ethdb.Database
+	db  ethdb.Database
+	dkg cipher.AEAD
+}
+
+// dkgKeyEncryption is the scrypt salt and parameters the key encrypting DKG
+// private keys is derived from the passphrase with. It is stored once in the
+// database, along with the MAC checking the passphrase.
+type dkgKeyEncryption struct {
+	Salt hexutil.Bytes `json:"salt"`
+	N    int           `json:"n"`
+	R    int           `json:"r"`
+	P    int           `json:"p"`
+	MAC  hexutil.Bytes `json:"mac"`
 }
 
 func NewDatabase(db ethdb.Database) *DB {
-	return &DB{db}
+	return &DB{db: db}
+}
+
+// NewEncryptedDatabase returns a database which encrypts DKG private keys, and
+// the DKG protocol holding the private shares they are recovered from, at rest
+// with the passphrase. The encryption key is derived once with the scrypt
+// parameters stored in the database, or with scryptN and scryptP for a new
+// one. Keys stored in plaintext are still readable until they are encrypted
+// by EncryptDKGPrivateKeys.
+func NewEncryptedDatabase(db ethdb.Database, passphrase string, scryptN, scryptP int) (*DB, error) {
+	var enc dkgKeyEncryption
+	data := rawdb.ReadCoreDKGKeyEncryption(db)
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &enc); err != nil {
+			return nil, err
+		}
+	} else {
+		enc = dkgKeyEncryption{
+			Salt: make([]byte, dkgKeySaltLen),
+			N:    scryptN,
+			R:    dkgKeyScryptR,
+			P:    scryptP,
+		}
+		if _, err := io.ReadFull(rand.Reader, enc.Salt); err != nil {
+			return nil, err
+		}
+	}
+	key, err := scrypt.Key([]byte(passphrase), enc.Salt, enc.N, enc.R, enc.P, dkgKeyScryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	mac := crypto.Keccak256(key)
+	if len(data) == 0 {
+		enc.MAC = mac
+		data, err := json.Marshal(&enc)
+		if err != nil {
+			return nil, err
+		}
+		if err := rawdb.WriteCoreDKGKeyEncryption(db, data); err != nil {
+			return nil, err
+		}
+	} else if !bytes.Equal(enc.MAC, mac) {
+		return nil, keystore.ErrDecrypt
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &DB{db: db, dkg: aead}, nil
 }
 
 func (d *DB) HasBlock(hash coreCommon.Hash) bool {
@@ -50,7 +148,11 @@ func (d *DB) GetBlock(hash coreCommon.Hash) (coreTypes.Block, error) {
 }
 
 func (d *DB) GetAllBlocks() (coreDb.BlockIterator, error) {
-	return nil, coreDb.ErrNotImplemented
+	db, ok := d.db.(ethdb.Iteratee)
+	if !ok {
+		return nil, coreDb.ErrNotImplemented
+	}
+	return &blockIterator{it: rawdb.NewCoreBlockIterator(db)}, nil
 }
 
 func (d *DB) UpdateBlock(block coreTypes.Block) error {
@@ -70,6 +172,9 @@ func (d *DB) PutBlock(block coreTypes.Block) error {
 }
 
 func (d *DB) GetDKGPrivateKey(round, reset uint64) (coreDKG.PrivateKey, error) {
+	if data := rawdb.ReadCoreEncryptedDKGPrivateKey(d.db, round); len(data) > 0 {
+		return d.decryptDKGPrivateKey(round, reset, data)
+	}
 	key := rawdb.ReadCoreDKGPrivateKey(d.db, round, reset)
 	if key == nil {
 		return coreDKG.PrivateKey{}, coreDb.ErrDKGPrivateKeyDoesNotExist
@@ -86,7 +191,116 @@ func (d *DB) PutDKGPrivateKey(round, reset uint64, key coreDKG.PrivateKey) error
 		return err
 	}
 
-	return rawdb.WriteCoreDKGPrivateKey(d.db, round, reset, &key)
+	if d.dkg == nil {
+		return rawdb.WriteCoreDKGPrivateKey(d.db, round, reset, &key)
+	}
+	data, err := rawdb.EncodeCoreDKGPrivateKey(&key, reset)
+	if err != nil {
+		return err
+	}
+	if err := d.writeEncryptedDKGPrivateKey(round, data); err != nil {
+		return err
+	}
+	// Drop the key of a previous reset left in plaintext.
+	rawdb.DeleteCoreDKGPrivateKey(d.db, round)
+	return nil
+}
+
+// EncryptDKGPrivateKeys encrypts the DKG private keys and the DKG protocol
+// stored in plaintext and returns the number of records encrypted.
+func (d *DB) EncryptDKGPrivateKeys() (int, error) {
+	if d.dkg == nil {
+		return 0, errNoDKGKeyPassphrase
+	}
+	db, ok := d.db.(ethdb.Iteratee)
+	if !ok {
+		return 0, coreDb.ErrNotImplemented
+	}
+	rounds, err := rawdb.ReadCoreDKGPrivateKeyRounds(db)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, round := range rounds {
+		// An encrypted key supersedes the plaintext one, which is a leftover
+		// of an interrupted migration.
+		if len(rawdb.ReadCoreEncryptedDKGPrivateKey(d.db, round)) == 0 {
+			data := rawdb.ReadCoreDKGPrivateKeyRLP(d.db, round)
+			if err := d.writeEncryptedDKGPrivateKey(round, data); err != nil {
+				return count, err
+			}
+			count++
+		}
+		rawdb.DeleteCoreDKGPrivateKey(d.db, round)
+	}
+
+	if data := rawdb.ReadCoreDKGProtocolRLP(d.db); len(data) > 0 {
+		if len(rawdb.ReadCoreEncryptedDKGProtocol(d.db)) == 0 {
+			encrypted, err := d.seal(data, dkgProtocolAD)
+			if err != nil {
+				return count, err
+			}
+			if err := rawdb.WriteCoreEncryptedDKGProtocol(d.db, encrypted); err != nil {
+				return count, err
+			}
+			count++
+		}
+		rawdb.DeleteCoreDKGProtocol(d.db)
+	}
+	return count, nil
+}
+
+func (d *DB) writeEncryptedDKGPrivateKey(round uint64, data []byte) error {
+	encrypted, err := d.seal(data, dkgPrivateKeyAD(round))
+	if err != nil {
+		return err
+	}
+	return rawdb.WriteCoreEncryptedDKGPrivateKey(d.db, round, encrypted)
+}
+
+func (d *DB) decryptDKGPrivateKey(round, reset uint64, encrypted []byte) (coreDKG.PrivateKey, error) {
+	data, err := d.open(encrypted, dkgPrivateKeyAD(round))
+	if err != nil {
+		return coreDKG.PrivateKey{}, err
+	}
+	key, keyReset, err := rawdb.DecodeCoreDKGPrivateKey(data)
+	if err != nil {
+		return coreDKG.PrivateKey{}, err
+	}
+	if keyReset != reset {
+		return coreDKG.PrivateKey{}, coreDb.ErrDKGPrivateKeyDoesNotExist
+	}
+	return *key, nil
+}
+
+// seal encrypts and authenticates the data along with the additional data,
+// prefixed by a random nonce.
+func (d *DB) seal(data, ad []byte) ([]byte, error) {
+	nonce := make([]byte, d.dkg.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return d.dkg.Seal(nonce, nonce, data, ad), nil
+}
+
+// open decrypts the data sealed along with the additional data.
+func (d *DB) open(encrypted, ad []byte) ([]byte, error) {
+	if d.dkg == nil {
+		return nil, ErrDKGPrivateKeyLocked
+	}
+	if len(encrypted) < d.dkg.NonceSize() {
+		return nil, errShortDKGRecord
+	}
+	nonce, sealed := encrypted[:d.dkg.NonceSize()], encrypted[d.dkg.NonceSize():]
+	return d.dkg.Open(nil, nonce, sealed, ad)
+}
+
+// dkgPrivateKeyAD is the additional data authenticated with the DKG private
+// key of the round, so that the key can not be moved to another round.
+func dkgPrivateKeyAD(round uint64) []byte {
+	ad := make([]byte, 8)
+	binary.BigEndian.PutUint64(ad, round)
+	return ad
 }
 
 func (d *DB) PutCompactionChainTipInfo(hash coreCommon.Hash, height uint64) error {
@@ -103,11 +317,36 @@ func (d *DB) GetCompactionChainTipInfo() (hash coreCommon.Hash, height uint64) {
 
 func (d *DB) PutOrUpdateDKGProtocol(
 	protocol coreDb.DKGProtocolInfo) error {
-	return rawdb.WriteCoreDKGProtocol(d.db, &protocol)
+	if d.dkg == nil {
+		return rawdb.WriteCoreDKGProtocol(d.db, &protocol)
+	}
+	data, err := rlp.EncodeToBytes(&protocol)
+	if err != nil {
+		return err
+	}
+	encrypted, err := d.seal(data, dkgProtocolAD)
+	if err != nil {
+		return err
+	}
+	if err := rawdb.WriteCoreEncryptedDKGProtocol(d.db, encrypted); err != nil {
+		return err
+	}
+	rawdb.DeleteCoreDKGProtocol(d.db)
+	return nil
 }
 
 func (d *DB) GetDKGProtocol() (
 	protocol coreDb.DKGProtocolInfo, err error) {
+	if encrypted := rawdb.ReadCoreEncryptedDKGProtocol(d.db); len(encrypted) > 0 {
+		data, err := d.open(encrypted, dkgProtocolAD)
+		if err != nil {
+			return coreDb.DKGProtocolInfo{}, err
+		}
+		if err := rlp.DecodeBytes(data, &protocol); err != nil {
+			return coreDb.DKGProtocolInfo{}, err
+		}
+		return protocol, nil
+	}
 	dkgProtocol := rawdb.ReadCoreDKGProtocol(d.db)
 	if dkgProtocol == nil {
 		return coreDb.DKGProtocolInfo{}, coreDb.ErrDKGProtocolDoesNotExist
@@ -116,3 +355,30 @@ func (d *DB) GetDKGProtocol() (
 }
 
 func (d *DB) Close() error { return nil }
+
+// blockIterator implements dexon-consensus BlockIterator interface. The
+// underlying database iterator is released once all blocks are iterated.
+type blockIterator struct {
+	it   *rawdb.CoreBlockIterator
+	done bool
+}
+
+func (it *blockIterator) NextBlock() (coreTypes.Block, error) {
+	if it.done {
+		return coreTypes.Block{}, coreDb.ErrIterationFinished
+	}
+	if !it.it.Next() {
+		it.done = true
+		err := it.it.Error()
+		it.it.Release()
+		if err != nil {
+			return coreTypes.Block{}, err
+		}
+		return coreTypes.Block{}, coreDb.ErrIterationFinished
+	}
+	block, err := it.it.Block()
+	if err != nil {
+		return coreTypes.Block{}, err
+	}
+	return *block, nil
+}
@@ -0,0 +1,235 @@
+// Copyright 2018 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package db
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
+	coreDKG "github.com/dexon-foundation/dexon-consensus/core/crypto/dkg"
+	coreDb "github.com/dexon-foundation/dexon-consensus/core/db"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+
+	"github.com/dexon-foundation/dexon/accounts/keystore"
+	"github.com/dexon-foundation/dexon/core/rawdb"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/ethdb"
+)
+
+func TestGetAllBlocksMemory(t *testing.T) {
+	testGetAllBlocks(t, ethdb.NewMemDatabase())
+}
+
+func TestGetAllBlocksLevelDB(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dex-db-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := ethdb.NewLDBDatabase(dir, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to create leveldb: %v", err)
+	}
+	defer db.Close()
+
+	testGetAllBlocks(t, db)
+}
+
+func testGetAllBlocks(t *testing.T, ethDB ethdb.Database) {
+	db := NewDatabase(ethDB)
+
+	// Entries sharing the core block key prefix must not be iterated.
+	rawdb.WriteDatabaseVersion(ethDB, 3)
+	if err := db.PutDKGPrivateKey(1, 0, *coreDKG.NewPrivateKey()); err != nil {
+		t.Fatalf("failed to put dkg private key: %v", err)
+	}
+
+	iter, err := db.GetAllBlocks()
+	if err != nil {
+		t.Fatalf("failed to get block iterator: %v", err)
+	}
+	if _, err := iter.NextBlock(); err != coreDb.ErrIterationFinished {
+		t.Fatalf("empty database iteration error mismatch: have %v, want %v",
+			err, coreDb.ErrIterationFinished)
+	}
+
+	blocks := make(map[coreCommon.Hash]coreTypes.Block)
+	for i := uint64(0); i < 10; i++ {
+		block := coreTypes.Block{
+			Hash:     coreCommon.Hash(crypto.Keccak256Hash([]byte{byte(i)})),
+			Position: coreTypes.Position{Round: 1, Height: i},
+		}
+		if err := db.PutBlock(block); err != nil {
+			t.Fatalf("failed to put block: %v", err)
+		}
+		blocks[block.Hash] = block
+	}
+
+	iter, err = db.GetAllBlocks()
+	if err != nil {
+		t.Fatalf("failed to get block iterator: %v", err)
+	}
+	var prev coreCommon.Hash
+	for i := 0; ; i++ {
+		block, err := iter.NextBlock()
+		if err == coreDb.ErrIterationFinished {
+			if i != len(blocks) {
+				t.Fatalf("iterated block count mismatch: have %d, want %d", i, len(blocks))
+			}
+			break
+		}
+		if err != nil {
+			t.Fatalf("failed to iterate block: %v", err)
+		}
+		want, ok := blocks[block.Hash]
+		if !ok {
+			t.Fatalf("unknown block iterated: %x", block.Hash)
+		}
+		if block.Position != want.Position {
+			t.Errorf("block position mismatch: have %v, want %v", block.Position, want.Position)
+		}
+		if i > 0 && !prev.Less(block.Hash) {
+			t.Errorf("blocks not in hash order: %x after %x", block.Hash, prev)
+		}
+		prev = block.Hash
+	}
+
+	// A finished iterator stays finished.
+	if _, err := iter.NextBlock(); err != coreDb.ErrIterationFinished {
+		t.Errorf("finished iteration error mismatch: have %v, want %v",
+			err, coreDb.ErrIterationFinished)
+	}
+}
+
+func TestEncryptedDKGPrivateKey(t *testing.T) {
+	ethDB := ethdb.NewMemDatabase()
+	plain := NewDatabase(ethDB)
+	db, err := NewEncryptedDatabase(ethDB, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to create encrypted database: %v", err)
+	}
+
+	// Keys stored in plaintext are readable until encrypted.
+	key1 := *coreDKG.NewPrivateKey()
+	if err := plain.PutDKGPrivateKey(1, 0, key1); err != nil {
+		t.Fatalf("failed to put dkg private key: %v", err)
+	}
+	if key, err := db.GetDKGPrivateKey(1, 0); err != nil || !bytes.Equal(key.Bytes(), key1.Bytes()) {
+		t.Fatalf("plaintext key mismatch: %v", err)
+	}
+	count, err := db.EncryptDKGPrivateKeys()
+	if err != nil || count != 1 {
+		t.Fatalf("failed to encrypt dkg private keys: have %d, %v", count, err)
+	}
+	if len(rawdb.ReadCoreDKGPrivateKeyRLP(ethDB, 1)) != 0 {
+		t.Error("plaintext key not deleted")
+	}
+	if count, err := db.EncryptDKGPrivateKeys(); err != nil || count != 0 {
+		t.Errorf("encrypted keys encrypted again: have %d, %v", count, err)
+	}
+
+	key2 := *coreDKG.NewPrivateKey()
+	if err := db.PutDKGPrivateKey(2, 1, key2); err != nil {
+		t.Fatalf("failed to put dkg private key: %v", err)
+	}
+	if err := db.PutDKGPrivateKey(2, 1, key2); err != coreDb.ErrDKGPrivateKeyExists {
+		t.Errorf("put existing key error mismatch: have %v, want %v", err, coreDb.ErrDKGPrivateKeyExists)
+	}
+	for round, key := range map[uint64]coreDKG.PrivateKey{1: key1, 2: key2} {
+		reset := round - 1
+		have, err := db.GetDKGPrivateKey(round, reset)
+		if err != nil || !bytes.Equal(have.Bytes(), key.Bytes()) {
+			t.Errorf("round %d key mismatch: %v", round, err)
+		}
+		if _, err := db.GetDKGPrivateKey(round, reset+1); err != coreDb.ErrDKGPrivateKeyDoesNotExist {
+			t.Errorf("round %d reset mismatch error: have %v, want %v",
+				round, err, coreDb.ErrDKGPrivateKeyDoesNotExist)
+		}
+		if _, err := plain.GetDKGPrivateKey(round, reset); err != ErrDKGPrivateKeyLocked {
+			t.Errorf("round %d read without passphrase: have %v, want %v",
+				round, err, ErrDKGPrivateKeyLocked)
+		}
+	}
+
+	// The key is derived with the stored parameters, not the given ones.
+	reopened, err := NewEncryptedDatabase(ethDB, "secret", keystore.StandardScryptN, keystore.StandardScryptP)
+	if err != nil {
+		t.Fatalf("failed to reopen encrypted database: %v", err)
+	}
+	if have, err := reopened.GetDKGPrivateKey(2, 1); err != nil || !bytes.Equal(have.Bytes(), key2.Bytes()) {
+		t.Errorf("reopened key mismatch: %v", err)
+	}
+	if _, err := NewEncryptedDatabase(ethDB, "wrong", keystore.LightScryptN, keystore.LightScryptP); err != keystore.ErrDecrypt {
+		t.Errorf("wrong passphrase error mismatch: have %v, want %v", err, keystore.ErrDecrypt)
+	}
+
+	// Keys are bound to their rounds.
+	rawdb.WriteCoreEncryptedDKGPrivateKey(ethDB, 3, rawdb.ReadCoreEncryptedDKGPrivateKey(ethDB, 2))
+	if _, err := db.GetDKGPrivateKey(3, 1); err == nil {
+		t.Error("read key moved to another round")
+	}
+}
+
+func TestEncryptedDKGProtocol(t *testing.T) {
+	ethDB := ethdb.NewMemDatabase()
+	plain := NewDatabase(ethDB)
+	db, err := NewEncryptedDatabase(ethDB, "secret", keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatalf("failed to create encrypted database: %v", err)
+	}
+
+	prvShares, _ := coreDKG.NewPrivateKeyShares(2)
+	protocol := coreDb.DKGProtocolInfo{
+		Round:                     1,
+		Threshold:                 2,
+		IsMasterPrivateShareEmpty: true,
+		PrvShares:                 *prvShares,
+		Step:                      3,
+	}
+
+	// The protocol stored in plaintext is encrypted along with the keys.
+	if err := plain.PutOrUpdateDKGProtocol(protocol); err != nil {
+		t.Fatalf("failed to put dkg protocol: %v", err)
+	}
+	count, err := db.EncryptDKGPrivateKeys()
+	if err != nil || count != 1 {
+		t.Fatalf("failed to encrypt dkg protocol: have %d, %v", count, err)
+	}
+	if len(rawdb.ReadCoreDKGProtocolRLP(ethDB)) != 0 {
+		t.Error("plaintext protocol not deleted")
+	}
+	have, err := db.GetDKGProtocol()
+	if err != nil || !have.Equal(&protocol) {
+		t.Errorf("protocol mismatch: %v", err)
+	}
+	if _, err := plain.GetDKGProtocol(); err != ErrDKGPrivateKeyLocked {
+		t.Errorf("read protocol without passphrase: have %v, want %v", err, ErrDKGPrivateKeyLocked)
+	}
+
+	protocol.Step = 4
+	if err := db.PutOrUpdateDKGProtocol(protocol); err != nil {
+		t.Fatalf("failed to update dkg protocol: %v", err)
+	}
+	if have, err := db.GetDKGProtocol(); err != nil || have.Step != 4 {
+		t.Errorf("updated protocol mismatch: step %d, %v", have.Step, err)
+	}
+}
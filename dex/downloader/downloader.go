@@ -1497,7 +1497,16 @@ func (d *Downloader) processHeaders(origin uint64, pivot uint64, number uint64)
 					}
 
 					for _, header := range chunk {
-						if header.GovState != nil {
+						if header.GovStateDiff != nil {
+							log.Debug("Got gov state, store it", "round", header.Round, "number", header.Number.Uint64())
+							govState, err := d.gov.StoreStateDiff(header.Header, header.GovStateDiff)
+							if err != nil {
+								log.Debug("Invalid gov state encountered", "number", header.Number, "hash", header.Hash(), "err", err)
+								return errInvalidChain
+							}
+							header.GovState = govState
+						} else if header.GovState != nil {
+							// dex64 peers send the whole gov state.
 							log.Debug("Got gov state, store it", "round", header.Round, "number", header.Number.Uint64())
 							d.gov.StoreState(header.GovState)
 						}
@@ -1,6 +1,7 @@
 package downloader
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -8,12 +9,19 @@ import (
 	"github.com/dexon-foundation/dexon/core"
 	"github.com/dexon-foundation/dexon/core/state"
 	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
 	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/ethdb"
 	"github.com/dexon-foundation/dexon/log"
+	"github.com/dexon-foundation/dexon/rlp"
 	"github.com/dexon-foundation/dexon/trie"
 )
 
+var (
+	errGovStateMismatch = errors.New("governance state mismatch")
+	errNoGovAccount     = errors.New("governance contract account not found")
+)
+
 // governanceDB is backed by memory db for fast sync.
 // it implements core.GovernanceStateDB
 type governanceStateDB struct {
@@ -41,9 +49,6 @@ func (g *governanceStateDB) StoreState(s *types.GovState) {
 	defer g.mu.Unlock()
 	log.Debug("Store state", "height", s.Number.Uint64())
 
-	// Store the height -> root mapping.
-	g.height2Root[s.Number.Uint64()] = s.Root
-
 	// Store the account.
 	for _, node := range s.Proof {
 		g.db.Put(crypto.Keccak256(node), node)
@@ -61,10 +66,78 @@ func (g *governanceStateDB) StoreState(s *types.GovState) {
 	t.Commit(nil)
 	triedb.Commit(t.Hash(), false)
 
-	if s.Number.Uint64() > g.headHeight {
-		log.Debug("Governance head root changed", "number", s.Number.Uint64())
-		g.headRoot = s.Root
-		g.headHeight = s.Number.Uint64()
+	// Store the height -> root mapping.
+	g.storeRoot(s.Number.Uint64(), s.Root)
+}
+
+// StoreStateDiff verifies the governance state diff against the header and
+// stores it. The account proof must be valid against the state root of the
+// header, and the storage trie of the account must be complete with the nodes
+// of the diff and of the states stored before. It returns the full governance
+// state at the header.
+func (g *governanceStateDB) StoreStateDiff(header *types.Header,
+	diff *types.GovStateDiff) (*types.GovState, error) {
+	if diff.BlockHash != header.Hash() || diff.Root != header.Root ||
+		diff.Number == nil || diff.Number.Cmp(header.Number) != 0 {
+		return nil, errGovStateMismatch
+	}
+
+	proofDB := ethdb.NewMemDatabase()
+	for _, node := range diff.Proof {
+		proofDB.Put(crypto.Keccak256(node), node)
+	}
+	key := crypto.Keccak256(vm.GovernanceContractAddress.Bytes())
+	value, _, err := trie.VerifyProof(header.Root, key, proofDB)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, errNoGovAccount
+	}
+	var account state.Account
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// Nodes are keyed by their hashes, so nodes not in the storage trie are
+	// harmless, and missing ones fail the iteration below.
+	for _, node := range diff.Nodes {
+		g.db.Put(crypto.Keccak256(node), node)
+	}
+	t, err := trie.New(account.Root, trie.NewDatabase(g.db))
+	if err != nil {
+		return nil, err
+	}
+	govState := &types.GovState{
+		BlockHash: diff.BlockHash,
+		Number:    diff.Number,
+		Root:      diff.Root,
+		Proof:     diff.Proof,
+	}
+	it := trie.NewIterator(t.NodeIterator(nil))
+	for it.Next() {
+		govState.Storage = append(govState.Storage, [2][]byte{it.Key, it.Value})
+	}
+	if it.Err != nil {
+		return nil, it.Err
+	}
+
+	for _, node := range diff.Proof {
+		g.db.Put(crypto.Keccak256(node), node)
+	}
+	g.storeRoot(diff.Number.Uint64(), diff.Root)
+	return govState, nil
+}
+
+func (g *governanceStateDB) storeRoot(number uint64, root common.Hash) {
+	g.height2Root[number] = root
+	if number > g.headHeight {
+		log.Debug("Governance head root changed", "number", number)
+		g.headRoot = root
+		g.headHeight = number
 	}
 }
 
@@ -92,3 +165,8 @@ func newGovernance(headState *types.GovState) *governance {
 func (g *governance) StoreState(s *types.GovState) {
 	g.db.StoreState(s)
 }
+
+func (g *governance) StoreStateDiff(header *types.Header,
+	diff *types.GovStateDiff) (*types.GovState, error) {
+	return g.db.StoreStateDiff(header, diff)
+}
@@ -0,0 +1,126 @@
+package downloader
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/ethdb"
+)
+
+// govStateChain builds governance states of consecutive blocks, each of which
+// updates a storage slot of the governance contract.
+type govStateChain struct {
+	t       *testing.T
+	db      state.Database
+	root    common.Hash
+	headers []*types.Header
+	states  []*types.GovState
+}
+
+func newGovStateChain(t *testing.T, slots int) *govStateChain {
+	c := &govStateChain{t: t, db: state.NewDatabase(ethdb.NewMemDatabase())}
+	c.next(func(statedb *state.StateDB) {
+		// Empty accounts are deleted on commit.
+		statedb.SetNonce(vm.GovernanceContractAddress, 1)
+		for i := 0; i < slots; i++ {
+			statedb.SetState(vm.GovernanceContractAddress,
+				common.BigToHash(big.NewInt(int64(i))), common.BigToHash(big.NewInt(int64(i+1))))
+		}
+	})
+	return c
+}
+
+func (c *govStateChain) next(update func(*state.StateDB)) {
+	statedb, err := state.New(c.root, c.db)
+	if err != nil {
+		c.t.Fatalf("failed to open state: %v", err)
+	}
+	update(statedb)
+	c.root, err = statedb.Commit(true)
+	if err != nil {
+		c.t.Fatalf("failed to commit state: %v", err)
+	}
+	header := &types.Header{Number: big.NewInt(int64(len(c.headers))), Root: c.root}
+	s, err := state.GetGovState(statedb, header, vm.GovernanceContractAddress)
+	if err != nil {
+		c.t.Fatalf("failed to get gov state: %v", err)
+	}
+	c.headers = append(c.headers, header)
+	c.states = append(c.states, s)
+}
+
+func (c *govStateChain) set(slot, value int64) {
+	c.next(func(statedb *state.StateDB) {
+		statedb.SetState(vm.GovernanceContractAddress,
+			common.BigToHash(big.NewInt(slot)), common.BigToHash(big.NewInt(value)))
+	})
+}
+
+func (c *govStateChain) diff(number, base int) *types.GovStateDiff {
+	var baseState *types.GovState
+	if base >= 0 {
+		baseState = c.states[base]
+	}
+	diff, err := state.DiffGovState(c.states[number], baseState)
+	if err != nil {
+		c.t.Fatalf("failed to diff gov state: %v", err)
+	}
+	return diff
+}
+
+func TestStoreGovStateDiff(t *testing.T) {
+	c := newGovStateChain(t, 64)
+	c.set(3, 100)
+	c.set(5, 200)
+
+	full, diff := c.diff(1, -1), c.diff(1, 0)
+	if len(diff.Nodes) == 0 || len(diff.Nodes) >= len(full.Nodes) {
+		t.Errorf("diff size mismatch: have %d nodes, full state %d nodes", len(diff.Nodes), len(full.Nodes))
+	}
+
+	g := newGovernance(c.states[0])
+	s, err := g.StoreStateDiff(c.headers[1], diff)
+	if err != nil {
+		t.Fatalf("failed to store gov state diff: %v", err)
+	}
+	if len(s.Storage) != len(c.states[1].Storage) {
+		t.Fatalf("storage size mismatch: have %d, want %d", len(s.Storage), len(c.states[1].Storage))
+	}
+	for i, kv := range s.Storage {
+		want := c.states[1].Storage[i]
+		if !bytes.Equal(kv[0], want[0]) || !bytes.Equal(kv[1], want[1]) {
+			t.Errorf("storage %d mismatch: have %x, want %x", i, kv, want)
+		}
+	}
+	statedb, err := g.db.StateAt(1)
+	if err != nil {
+		t.Fatalf("failed to get state: %v", err)
+	}
+	if v := statedb.GetState(vm.GovernanceContractAddress, common.BigToHash(big.NewInt(3))); v != common.BigToHash(big.NewInt(100)) {
+		t.Errorf("storage value mismatch: have %x", v)
+	}
+
+	// The diff does not belong to the header.
+	if _, err := g.StoreStateDiff(c.headers[2], c.diff(1, 0)); err != errGovStateMismatch {
+		t.Errorf("header mismatch error mismatch: have %v, want %v", err, errGovStateMismatch)
+	}
+	tampered := c.diff(2, 1)
+	tampered.Proof = c.states[1].Proof
+	if _, err := g.StoreStateDiff(c.headers[2], tampered); err == nil {
+		t.Errorf("stored gov state diff with invalid proof")
+	}
+
+	// The storage trie is incomplete without the base.
+	g = newGovernance(c.states[0])
+	if _, err := g.StoreStateDiff(c.headers[2], c.diff(2, 1)); err == nil {
+		t.Errorf("stored gov state diff of missing base")
+	}
+	if _, err := g.StoreStateDiff(c.headers[2], c.diff(2, -1)); err != nil {
+		t.Errorf("failed to store full gov state: %v", err)
+	}
+}
@@ -19,17 +19,14 @@ package dex
 
 import (
 	"context"
-	"crypto/ecdsa"
 	"math/big"
 
 	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
 	dkgTypes "github.com/dexon-foundation/dexon-consensus/core/types/dkg"
 
-	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/core"
 	"github.com/dexon-foundation/dexon/core/types"
 	"github.com/dexon-foundation/dexon/core/vm"
-	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/params"
 )
@@ -39,25 +36,37 @@ type DexconGovernance struct {
 
 	b           *DexAPIBackend
 	chainConfig *params.ChainConfig
-	privateKey  *ecdsa.PrivateKey
-	address     common.Address
+	signer      ConsensusSigner
+	operator    TxSigner
 }
 
 // NewDexconGovernance returns a governance implementation of the DEXON
-// consensus governance interface.
+// consensus governance interface. Governance transactions are sent by the
+// operator after the node key separation fork if it is not nil, and by the
+// consensus signer otherwise.
 func NewDexconGovernance(backend *DexAPIBackend, chainConfig *params.ChainConfig,
-	privKey *ecdsa.PrivateKey) *DexconGovernance {
+	signer ConsensusSigner, operator TxSigner) *DexconGovernance {
 	g := &DexconGovernance{
 		Governance: core.NewGovernance(
 			core.NewGovernanceStateDB(backend.dex.BlockChain())),
 		b:           backend,
 		chainConfig: chainConfig,
-		privateKey:  privKey,
-		address:     crypto.PubkeyToAddress(privKey.PublicKey),
+		signer:      signer,
+		operator:    operator,
 	}
 	return g
 }
 
+// txSigner returns the signer of governance transactions. The governance
+// contract only accepts DKG messages relayed by other accounts after the node
+// key separation fork.
+func (d *DexconGovernance) txSigner() TxSigner {
+	if d.operator != nil && d.chainConfig.IsNodeKeySeparation(d.b.CurrentBlock().Number()) {
+		return d.operator
+	}
+	return d.signer
+}
+
 // DexconConfiguration return raw config in state.
 func (d *DexconGovernance) DexconConfiguration(round uint64) *params.DexconConfig {
 	return d.GetStateForConfigAtRound(round).Configuration()
@@ -69,7 +78,8 @@ func (d *DexconGovernance) sendGovTx(ctx context.Context, data []byte) error {
 		return err
 	}
 
-	nonce, err := d.b.GetPoolNonce(ctx, d.address)
+	txSigner := d.txSigner()
+	nonce, err := d.b.GetPoolNonce(ctx, txSigner.Address())
 	if err != nil {
 		return err
 	}
@@ -91,14 +101,13 @@ func (d *DexconGovernance) sendGovTx(ctx context.Context, data []byte) error {
 		gasPrice,
 		data)
 
-	signer := types.NewEIP155Signer(d.chainConfig.ChainID)
-
-	tx, err = types.SignTx(tx, signer, d.privateKey)
+	tx, err = txSigner.SignTx(tx, d.chainConfig.ChainID)
 	if err != nil {
 		return err
 	}
 
-	log.Info("Send governance transaction", "fullhash", tx.Hash().Hex(), "nonce", nonce)
+	log.Info("Send governance transaction", "fullhash", tx.Hash().Hex(),
+		"from", txSigner.Address(), "nonce", nonce)
 
 	return d.b.SendTx(ctx, tx)
 }
@@ -54,6 +54,7 @@ import (
 	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/consensus"
 	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/state"
 	"github.com/dexon-foundation/dexon/core/types"
 	"github.com/dexon-foundation/dexon/crypto"
 	dexDB "github.com/dexon-foundation/dexon/dex/db"
@@ -573,7 +574,10 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				return p.SendBlockHeaders(query.Flag, []*types.HeaderWithGovState{})
 			}
 
-			snapshotHeight := map[uint64]struct{}{}
+			// The gov state of a round is sent as a diff against the gov
+			// state of the previous round, which the requester processes
+			// before this one. dex64 peers take the whole gov state.
+			snapshotHeight := map[uint64]uint64{}
 			for r, height := range round {
 				log.Trace("#Include round", "round", r)
 				if r == 0 {
@@ -584,22 +588,27 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 				if h == 0 {
 					h = height
 				}
-				snapshotHeight[h] = struct{}{}
+				snapshotHeight[h] = r - 1
 			}
 
 			for _, header := range headers {
-				if _, exist := snapshotHeight[header.Number.Uint64()]; exist {
+				if baseRound, exist := snapshotHeight[header.Number.Uint64()]; exist {
 					tt := time.Now()
 					log.Debug("Handler get gov state by hash", "t", tt)
-					s, err := pm.blockchain.GetGovStateByHash(header.Hash())
+					var err error
+					if p.version >= dex65 {
+						header.GovStateDiff, err = pm.govStateDiff(header.Header, baseRound)
+					} else {
+						header.GovState, err = pm.blockchain.GetGovStateByHash(header.Hash())
+					}
 					log.Debug("Handler get gov state by hash", "elapsed", time.Since(tt))
 					if err != nil {
 						log.Warn("Get gov state by hash fail", "number", header.Number.Uint64(), "err", err)
 						return p.SendBlockHeaders(query.Flag, []*types.HeaderWithGovState{})
 					}
-					header.GovState = s
 				}
-				log.Trace("Send header", "round", header.Round, "number", header.Number.Uint64(), "gov state == nil", header.GovState == nil)
+				log.Trace("Send header", "round", header.Round, "number", header.Number.Uint64(),
+					"gov state == nil", header.GovState == nil && header.GovStateDiff == nil)
 			}
 		}
 		return p.SendBlockHeaders(query.Flag, headers)
@@ -607,7 +616,13 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	case msg.Code == BlockHeadersMsg:
 		// A batch of headers arrived to one of our previous requests
 		var data headersData
-		if err := msg.Decode(&data); err != nil {
+		if p.version < dex65 {
+			var data64 headersData64
+			if err := msg.Decode(&data64); err != nil {
+				return errResp(ErrDecode, "msg %v: %v", msg, err)
+			}
+			data = data64.headersData()
+		} else if err := msg.Decode(&data); err != nil {
 			return errResp(ErrDecode, "msg %v: %v", msg, err)
 		}
 
@@ -998,6 +1013,25 @@ func (pm *ProtocolManager) handleMsg(p *peer) error {
 	return nil
 }
 
+// govStateDiff returns the gov state at the header as a diff against the gov
+// state at the snapshot height of the base round, or as the whole state if the
+// base is not available.
+func (pm *ProtocolManager) govStateDiff(header *types.Header, baseRound uint64) (*types.GovStateDiff, error) {
+	s, err := pm.blockchain.GetGovStateByHash(header.Hash())
+	if err != nil {
+		return nil, err
+	}
+	var base *types.GovState
+	if height := pm.gov.GetRoundHeight(baseRound); baseRound == 0 || height != 0 {
+		base, err = pm.blockchain.GetGovStateByNumber(height)
+		if err != nil {
+			log.Debug("Base gov state not available", "round", baseRound, "err", err)
+			base = nil
+		}
+	}
+	return state.DiffGovState(s, base)
+}
+
 // BroadcastBlock will either propagate a block to a subset of it's peers, or
 // will only announce it's availability (depending what's requested).
 func (pm *ProtocolManager) BroadcastBlock(block *types.Block, propagate bool) {
@@ -1177,7 +1211,7 @@ func (pm *ProtocolManager) BroadcastAgreementResult(
 func (pm *ProtocolManager) SendDKGPrivateShare(
 	pub coreCrypto.PublicKey, privateShare *dkgTypes.PrivateShare) {
 
-	pk, err := crypto.UnmarshalPubkey(pub.Bytes())
+	pk, err := crypto.UnmarshalPubkey(pm.gov.P2PPublicKey(pub.Bytes()))
 	if err != nil {
 		panic(err)
 	}
@@ -1406,7 +1440,7 @@ type NotaryNodeInfo struct {
 
 func (pm *ProtocolManager) NotaryInfo() (*NotaryInfo, error) {
 	current := pm.blockchain.CurrentBlock()
-	pubkeys, err := pm.gov.NotarySet(current.Round())
+	pubkeys, err := pm.gov.NotaryNodeSet(current.Round())
 	if err != nil {
 		return nil, err
 	}
@@ -1424,7 +1458,7 @@ func (pm *ProtocolManager) NotaryInfo() (*NotaryInfo, error) {
 	info.IsNotary = in
 
 	if crsRound := pm.gov.CRSRound(); crsRound != current.Round() {
-		pubkeys, err := pm.gov.NotarySet(crsRound)
+		pubkeys, err := pm.gov.NotaryNodeSet(crsRound)
 		if err != nil {
 			return nil, err
 		}
@@ -232,6 +232,15 @@ func (g *testGovernance) NotarySet(
 	return g.notarySetFunc(round)
 }
 
+func (g *testGovernance) NotaryNodeSet(
+	round uint64) (map[string]struct{}, error) {
+	return g.notarySetFunc(round)
+}
+
+func (g *testGovernance) P2PPublicKey(pk []byte) []byte {
+	return pk
+}
+
 func (g *testGovernance) DKGSet(round uint64) (map[string]struct{}, error) {
 	return g.dkgSetFunc(round)
 }
@@ -505,6 +505,9 @@ func (p *peer) AsyncSendPullVotes(pos coreTypes.Position) {
 
 // SendBlockHeaders sends a batch of block headers to the remote peer.
 func (p *peer) SendBlockHeaders(flag uint8, headers []*types.HeaderWithGovState) error {
+	if p.version < dex65 {
+		return p.logSend(p2p.Send(p.rw, BlockHeadersMsg, newHeadersData64(flag, headers)), BlockHeadersMsg)
+	}
 	return p.logSend(p2p.Send(p.rw, BlockHeadersMsg, headersData{Flag: flag, Headers: headers}), BlockHeadersMsg)
 }
 
@@ -857,7 +860,7 @@ func (ps *peerSet) BuildConnection(round uint64) {
 
 	notaryLabel := peerLabel{set: notaryset, round: round}
 	if _, ok := ps.label2Nodes[notaryLabel]; !ok {
-		notaryPKs, err := ps.gov.NotarySet(round)
+		notaryPKs, err := ps.gov.NotaryNodeSet(round)
 		if err != nil {
 			log.Error("get notary set fail", "round", round, "err", err)
 			return
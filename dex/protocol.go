@@ -50,16 +50,17 @@ import (
 // Constants to match up protocol versions and messages
 const (
 	dex64 = 64
+	dex65 = 65
 )
 
 // ProtocolName is the official short name of the protocol used during capability negotiation.
 var ProtocolName = "dex"
 
 // ProtocolVersions are the supported versions of the eth protocol (first is primary).
-var ProtocolVersions = []uint{dex64}
+var ProtocolVersions = []uint{dex65, dex64}
 
 // ProtocolLengths are the number of implemented message corresponding to different protocol versions.
-var ProtocolLengths = []uint64{43}
+var ProtocolLengths = []uint64{43, 43}
 
 const ProtocolMaxMsgSize = 10 * 1024 * 1024 // Maximum cap on the size of a protocol message
 
@@ -154,6 +155,10 @@ type governance interface {
 
 	NotarySet(uint64) (map[string]struct{}, error)
 
+	NotaryNodeSet(uint64) (map[string]struct{}, error)
+
+	P2PPublicKey([]byte) []byte
+
 	PurgeNotarySet(uint64)
 
 	DKGResetCount(uint64) uint64
@@ -242,6 +247,35 @@ type headersData struct {
 	Headers []*types.HeaderWithGovState
 }
 
+// headerWithFullGovState is a header with the whole governance state at the
+// block, as sent to dex64 peers.
+type headerWithFullGovState struct {
+	*types.Header
+	GovState *types.GovState `rlp:"nil"`
+}
+
+// headersData64 is the dex64 network packet for header content distribution.
+type headersData64 struct {
+	Flag    uint8
+	Headers []*headerWithFullGovState
+}
+
+func newHeadersData64(flag uint8, headers []*types.HeaderWithGovState) headersData64 {
+	data := headersData64{Flag: flag, Headers: make([]*headerWithFullGovState, len(headers))}
+	for i, header := range headers {
+		data.Headers[i] = &headerWithFullGovState{Header: header.Header, GovState: header.GovState}
+	}
+	return data
+}
+
+func (data headersData64) headersData() headersData {
+	headers := make([]*types.HeaderWithGovState, len(data.Headers))
+	for i, header := range data.Headers {
+		headers[i] = &types.HeaderWithGovState{Header: header.Header, GovState: header.GovState}
+	}
+	return headersData{Flag: data.Flag, Headers: headers}
+}
+
 // newBlockData is the network packet for the block propagation message.
 type newBlockData struct {
 	Block *types.Block
@@ -19,6 +19,7 @@ package dex
 import (
 	"crypto/ecdsa"
 	"fmt"
+	"math/big"
 	"reflect"
 	"sync"
 	"testing"
@@ -231,11 +232,67 @@ func TestGetBlockHeadersDataEncodeDecode(t *testing.T) {
 	}
 }
 
+// Tests that headers with gov states are sent to dex64 peers with the whole
+// gov states, without the diffs they do not understand.
+func TestHeadersData64EncodeDecode(t *testing.T) {
+	govState := &types.GovState{
+		BlockHash: common.Hash{0x01},
+		Number:    big.NewInt(10),
+		Root:      common.Hash{0x02},
+		Proof:     [][]byte{{0x03}},
+		Storage:   [][2][]byte{{{0x04}, {0x05}}},
+	}
+	headers := []*types.HeaderWithGovState{
+		{Header: &types.Header{Number: big.NewInt(9)}},
+		{
+			Header:       &types.Header{Number: big.NewInt(10)},
+			GovState:     govState,
+			GovStateDiff: &types.GovStateDiff{Number: big.NewInt(10), Nodes: [][]byte{{0x06}}},
+		},
+	}
+	bytes, err := rlp.EncodeToBytes(newHeadersData64(downloaderReq, headers))
+	if err != nil {
+		t.Fatalf("failed to encode packet: %v", err)
+	}
+	var packet headersData64
+	if err := rlp.DecodeBytes(bytes, &packet); err != nil {
+		t.Fatalf("failed to decode packet: %v", err)
+	}
+	data := packet.headersData()
+	if data.Flag != downloaderReq || len(data.Headers) != len(headers) {
+		t.Fatalf("packet mismatch: have flag %d, %d headers", data.Flag, len(data.Headers))
+	}
+	for i, header := range data.Headers {
+		if header.Hash() != headers[i].Hash() {
+			t.Errorf("header %d mismatch: have %x, want %x", i, header.Hash(), headers[i].Hash())
+		}
+		if header.GovStateDiff != nil {
+			t.Errorf("header %d has gov state diff", i)
+		}
+		if !reflect.DeepEqual(header.GovState, headers[i].GovState) {
+			t.Errorf("header %d gov state mismatch: have %+v, want %+v", i, header.GovState, headers[i].GovState)
+		}
+	}
+
+	// dex65 packets carry the diff instead.
+	bytes, err = rlp.EncodeToBytes(headersData{Flag: downloaderReq, Headers: headers})
+	if err != nil {
+		t.Fatalf("failed to encode packet: %v", err)
+	}
+	var data65 headersData
+	if err := rlp.DecodeBytes(bytes, &data65); err != nil {
+		t.Fatalf("failed to decode packet: %v", err)
+	}
+	if data65.Headers[1].GovState != nil || data65.Headers[1].GovStateDiff == nil {
+		t.Errorf("dex65 packet gov state mismatch")
+	}
+}
+
 func TestRecvCoreBlocks(t *testing.T) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
 	pm.SetReceiveCoreMessage(true)
 
-	p, _ := newTestPeer("peer", dex64, pm, true)
+	p, _ := newTestPeer("peer", dex65, pm, true)
 	defer pm.Stop()
 	defer p.close()
 
@@ -365,7 +422,7 @@ func TestSendCoreBlocks(t *testing.T) {
 
 	pm.peers.label2Nodes = make(map[peerLabel]map[string]*enode.Node)
 	for i, tt := range testPeers {
-		p, _ := newTestPeer(fmt.Sprintf("peer #%d", i), dex64, pm, true)
+		p, _ := newTestPeer(fmt.Sprintf("peer #%d", i), dex65, pm, true)
 		if tt.label != nil {
 			if pm.peers.label2Nodes[*tt.label] == nil {
 				pm.peers.label2Nodes[*tt.label] = make(map[string]*enode.Node)
@@ -385,7 +442,7 @@ func TestRecvVotes(t *testing.T) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
 	pm.SetReceiveCoreMessage(true)
 
-	p, _ := newTestPeer("peer", dex64, pm, true)
+	p, _ := newTestPeer("peer", dex65, pm, true)
 	defer pm.Stop()
 	defer p.close()
 
@@ -503,7 +560,7 @@ func TestSendVotes(t *testing.T) {
 
 	pm.peers.label2Nodes = make(map[peerLabel]map[string]*enode.Node)
 	for i, tt := range testPeers {
-		p, _ := newTestPeer(fmt.Sprintf("peer #%d", i), dex64, pm, true)
+		p, _ := newTestPeer(fmt.Sprintf("peer #%d", i), dex65, pm, true)
 		if tt.label != nil {
 			if pm.peers.label2Nodes[*tt.label] == nil {
 				pm.peers.label2Nodes[*tt.label] = make(map[string]*enode.Node)
@@ -533,7 +590,7 @@ func TestRecvDKGPrivateShare(t *testing.T) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
 	pm.SetReceiveCoreMessage(true)
 
-	p, _ := newTestPeer("peer1", dex64, pm, true)
+	p, _ := newTestPeer("peer1", dex65, pm, true)
 	defer pm.Stop()
 	defer p.close()
 
@@ -570,8 +627,8 @@ func TestSendDKGPrivateShare(t *testing.T) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
 	pm.SetReceiveCoreMessage(true)
 
-	p1, _ := newTestPeer("peer1", dex64, pm, true)
-	p2, _ := newTestPeer("peer2", dex64, pm, true)
+	p1, _ := newTestPeer("peer1", dex65, pm, true)
+	p2, _ := newTestPeer("peer2", dex65, pm, true)
 	defer pm.Stop()
 	defer p1.close()
 
@@ -620,7 +677,7 @@ func TestRecvAgreement(t *testing.T) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
 	pm.SetReceiveCoreMessage(true)
 
-	p, _ := newTestPeer("peer", dex64, pm, true)
+	p, _ := newTestPeer("peer", dex65, pm, true)
 	defer pm.Stop()
 	defer p.close()
 
@@ -670,7 +727,7 @@ func TestSendAgreement(t *testing.T) {
 	pm, _ := newTestProtocolManagerMust(t, downloader.FullSync, 0, nil, nil)
 	pm.SetReceiveCoreMessage(true)
 
-	p, _ := newTestPeer("peer", dex64, pm, true)
+	p, _ := newTestPeer("peer", dex65, pm, true)
 	defer pm.Stop()
 	defer p.close()
 
@@ -18,22 +18,17 @@
 package dex
 
 import (
-	"crypto/ecdsa"
 	"encoding/hex"
 	"errors"
-	"fmt"
 	"math/big"
-	"strconv"
 	"strings"
 
+	"github.com/dexon-foundation/dexon"
 	"github.com/dexon-foundation/dexon/accounts/abi"
 	"github.com/dexon-foundation/dexon/common"
 	"github.com/dexon-foundation/dexon/core/types"
-	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/params"
-	"github.com/dexon-foundation/dexon/rlp"
-	"github.com/onrik/ethrpc"
 )
 
 const numConfirmation = 1
@@ -256,54 +251,46 @@ func init() {
 	}
 }
 
+// recoveryGovernance is the governance information needed by Recovery.
+type recoveryGovernance interface {
+	Round() uint64
+	NotarySet(round uint64) (map[string]struct{}, error)
+	DKGSetNodeKeyAddresses(round uint64) (map[common.Address]struct{}, error)
+}
+
 type Recovery struct {
-	gov          *DexconGovernance
+	gov          recoveryGovernance
 	contract     common.Address
 	confirmation int
 	publicKey    string
-	privateKey   *ecdsa.PrivateKey
+	signer       ConsensusSigner
 	nodeAddress  common.Address
-	client       *ethrpc.EthRPC
+	backend      RecoveryBackend
 }
 
-func NewRecovery(config *params.RecoveryConfig, networkRPC string,
-	gov *DexconGovernance, privKey *ecdsa.PrivateKey) *Recovery {
-	client := ethrpc.New(networkRPC)
+func NewRecovery(config *params.RecoveryConfig, backend RecoveryBackend,
+	gov recoveryGovernance, signer ConsensusSigner) *Recovery {
 	return &Recovery{
 		gov:          gov,
 		contract:     config.Contract,
 		confirmation: config.Confirmation,
-		publicKey:    hex.EncodeToString(crypto.FromECDSAPub(&privKey.PublicKey)),
-		privateKey:   privKey,
-		nodeAddress:  crypto.PubkeyToAddress(privKey.PublicKey),
-		client:       client,
+		publicKey:    hex.EncodeToString(signer.PublicKey().Bytes()),
+		signer:       signer,
+		nodeAddress:  signer.Address(),
+		backend:      backend,
 	}
 }
 
-func (r *Recovery) callRPC(data []byte, tag string) ([]byte, error) {
-	res, err := r.client.EthCall(ethrpc.T{
-		From: r.nodeAddress.String(),
-		To:   r.contract.String(),
-		Data: "0x" + hex.EncodeToString(data),
-	}, tag)
-	if err != nil {
-		return nil, err
-	}
-
-	resBytes, err := hex.DecodeString(res[2:])
-	if err != nil {
-		return nil, err
-	}
-	return resBytes, nil
+func (r *Recovery) call(data []byte, blockNumber *big.Int) ([]byte, error) {
+	return r.backend.CallContract(dexon.CallMsg{
+		From: r.nodeAddress,
+		To:   &r.contract,
+		Data: data,
+	}, blockNumber)
 }
 
 func (r *Recovery) genVoteForSkipBlockTx(height uint64) (*types.Transaction, error) {
-	netVersion, err := r.client.NetVersion()
-	if err != nil {
-		return nil, err
-	}
-
-	networkID, err := strconv.Atoi(netVersion)
+	networkID, err := r.backend.NetworkID()
 	if err != nil {
 		return nil, err
 	}
@@ -313,7 +300,7 @@ func (r *Recovery) genVoteForSkipBlockTx(height uint64) (*types.Transaction, err
 		return nil, err
 	}
 
-	resBytes, err := r.callRPC(data, "latest")
+	resBytes, err := r.call(data, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -334,7 +321,7 @@ func (r *Recovery) genVoteForSkipBlockTx(height uint64) (*types.Transaction, err
 		return nil, err
 	}
 
-	resBytes, err = r.callRPC(data, "latest")
+	resBytes, err = r.call(data, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -350,30 +337,29 @@ func (r *Recovery) genVoteForSkipBlockTx(height uint64) (*types.Transaction, err
 		return nil, err
 	}
 
-	gasPrice, err := r.client.EthGasPrice()
+	gasPrice, err := r.backend.SuggestGasPrice()
 	if err != nil {
 		return nil, err
 	}
 
-	nonce, err := r.client.EthGetTransactionCount(r.nodeAddress.String(), "pending")
+	nonce, err := r.backend.PendingNonceAt(r.nodeAddress)
 	if err != nil {
 		return nil, err
 	}
 
 	// Increase gasPrice to 3 times of suggested gas price to make sure it will
 	// be included in time.
-	useGasPrice := new(big.Int).Mul(&gasPrice, big.NewInt(3))
+	useGasPrice := new(big.Int).Mul(gasPrice, big.NewInt(3))
 
 	tx := types.NewTransaction(
-		uint64(nonce),
+		nonce,
 		r.contract,
 		depositValue,
 		uint64(100000),
 		useGasPrice,
 		data)
 
-	signer := types.NewEIP155Signer(big.NewInt(int64(networkID)))
-	return types.SignTx(tx, signer, r.privateKey)
+	return r.signer.SignTx(tx, new(big.Int).SetUint64(networkID))
 }
 
 func (r *Recovery) ProposeSkipBlock(height uint64) error {
@@ -392,13 +378,7 @@ func (r *Recovery) ProposeSkipBlock(height uint64) error {
 	if err != nil {
 		return err
 	}
-
-	txData, err := rlp.EncodeToBytes(tx)
-	if err != nil {
-		return err
-	}
-	_, err = r.client.EthSendRawTransaction("0x" + hex.EncodeToString(txData))
-	return err
+	return r.backend.SendTransaction(tx)
 }
 
 func (r *Recovery) Votes(height uint64) (uint64, error) {
@@ -407,14 +387,14 @@ func (r *Recovery) Votes(height uint64) (uint64, error) {
 		return 0, err
 	}
 
-	bn, err := r.client.EthBlockNumber()
+	bn, err := r.backend.BlockNumber()
 	if err != nil {
 		return 0, err
 	}
 
-	snapshotHeight := bn - numConfirmation
+	snapshotHeight := new(big.Int).SetUint64(bn - numConfirmation)
 
-	resBytes, err := r.callRPC(data, fmt.Sprintf("0x%x", snapshotHeight))
+	resBytes, err := r.call(data, snapshotHeight)
 	if err != nil {
 		return 0, err
 	}
@@ -439,7 +419,7 @@ func (r *Recovery) Votes(height uint64) (uint64, error) {
 			return 0, err
 		}
 
-		resBytes, err := r.callRPC(data, fmt.Sprintf("0x%x", snapshotHeight))
+		resBytes, err := r.call(data, snapshotHeight)
 		if err != nil {
 			return 0, err
 		}
@@ -0,0 +1,169 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/dexon-foundation/dexon"
+	"github.com/dexon-foundation/dexon/accounts/abi/bind/backends"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/onrik/ethrpc"
+)
+
+// RecoveryBackend is the chain hosting the recovery contract.
+type RecoveryBackend interface {
+	// NetworkID returns the network ID transactions are signed for.
+	NetworkID() (uint64, error)
+
+	// BlockNumber returns the number of the latest block.
+	BlockNumber() (uint64, error)
+
+	// CallContract executes a message call on the state of the given block,
+	// or the latest block if blockNumber is nil.
+	CallContract(msg dexon.CallMsg, blockNumber *big.Int) ([]byte, error)
+
+	// PendingNonceAt returns the pending nonce of the account.
+	PendingNonceAt(account common.Address) (uint64, error)
+
+	// SuggestGasPrice returns the currently suggested gas price.
+	SuggestGasPrice() (*big.Int, error)
+
+	// SendTransaction submits a signed transaction.
+	SendTransaction(tx *types.Transaction) error
+}
+
+// RPCRecoveryBackend is a RecoveryBackend reaching the recovery network over
+// JSON-RPC.
+type RPCRecoveryBackend struct {
+	client *ethrpc.EthRPC
+}
+
+// NewRPCRecoveryBackend creates a RecoveryBackend talking to the JSON-RPC
+// endpoint at url.
+func NewRPCRecoveryBackend(url string) *RPCRecoveryBackend {
+	return &RPCRecoveryBackend{client: ethrpc.New(url)}
+}
+
+func (b *RPCRecoveryBackend) NetworkID() (uint64, error) {
+	netVersion, err := b.client.NetVersion()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(netVersion, 10, 64)
+}
+
+func (b *RPCRecoveryBackend) BlockNumber() (uint64, error) {
+	bn, err := b.client.EthBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	return uint64(bn), nil
+}
+
+func (b *RPCRecoveryBackend) CallContract(msg dexon.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	tag := "latest"
+	if blockNumber != nil {
+		tag = fmt.Sprintf("0x%x", blockNumber)
+	}
+
+	to := ""
+	if msg.To != nil {
+		to = msg.To.String()
+	}
+	res, err := b.client.EthCall(ethrpc.T{
+		From: msg.From.String(),
+		To:   to,
+		Data: "0x" + hex.EncodeToString(msg.Data),
+	}, tag)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) < 2 {
+		return nil, fmt.Errorf("invalid call result: %q", res)
+	}
+	return hex.DecodeString(res[2:])
+}
+
+func (b *RPCRecoveryBackend) PendingNonceAt(account common.Address) (uint64, error) {
+	nonce, err := b.client.EthGetTransactionCount(account.String(), "pending")
+	if err != nil {
+		return 0, err
+	}
+	return uint64(nonce), nil
+}
+
+func (b *RPCRecoveryBackend) SuggestGasPrice() (*big.Int, error) {
+	gasPrice, err := b.client.EthGasPrice()
+	if err != nil {
+		return nil, err
+	}
+	return &gasPrice, nil
+}
+
+func (b *RPCRecoveryBackend) SendTransaction(tx *types.Transaction) error {
+	txData, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	_, err = b.client.EthSendRawTransaction("0x" + hex.EncodeToString(txData))
+	return err
+}
+
+// SimulatedRecoveryBackend is a RecoveryBackend running the recovery contract
+// on an in-process simulated chain. Transactions sent are only included after
+// the simulated backend is committed.
+type SimulatedRecoveryBackend struct {
+	backend *backends.SimulatedBackend
+}
+
+// NewSimulatedRecoveryBackend creates a RecoveryBackend on top of a simulated
+// chain.
+func NewSimulatedRecoveryBackend(backend *backends.SimulatedBackend) *SimulatedRecoveryBackend {
+	return &SimulatedRecoveryBackend{backend: backend}
+}
+
+func (b *SimulatedRecoveryBackend) NetworkID() (uint64, error) {
+	return b.backend.Blockchain().Config().ChainID.Uint64(), nil
+}
+
+func (b *SimulatedRecoveryBackend) BlockNumber() (uint64, error) {
+	return b.backend.Blockchain().CurrentBlock().NumberU64(), nil
+}
+
+func (b *SimulatedRecoveryBackend) CallContract(msg dexon.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.backend.CallContract(context.Background(), msg, blockNumber)
+}
+
+func (b *SimulatedRecoveryBackend) PendingNonceAt(account common.Address) (uint64, error) {
+	return b.backend.PendingNonceAt(context.Background(), account)
+}
+
+func (b *SimulatedRecoveryBackend) SuggestGasPrice() (*big.Int, error) {
+	return b.backend.SuggestGasPrice(context.Background())
+}
+
+func (b *SimulatedRecoveryBackend) SendTransaction(tx *types.Transaction) error {
+	return b.backend.SendTransaction(context.Background(), tx)
+}
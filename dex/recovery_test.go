@@ -18,26 +18,307 @@
 package dex
 
 import (
+	"crypto/ecdsa"
+	"encoding/hex"
+	"fmt"
+	"math/big"
 	"testing"
 
+	"github.com/dexon-foundation/dexon/accounts/abi/bind/backends"
 	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/asm"
 	"github.com/dexon-foundation/dexon/crypto"
 	"github.com/dexon-foundation/dexon/params"
 )
 
+// recoveryContractAsm is a minimal recovery contract implementing the calls
+// used by Recovery. The deposit value is kept in slot 0.
+const recoveryContractAsm = `
+	push 0
+	calldataload
+	push 0xe0
+	shr
+	dup1
+	push 0x%x
+	eq
+	jumpi @deposit
+	dup1
+	push 0x%x
+	eq
+	jumpi @voted
+	dup1
+	push 0x%x
+	eq
+	jumpi @vote
+	dup1
+	push 0x%x
+	eq
+	jumpi @numvotes
+	dup1
+	push 0x%x
+	eq
+	jumpi @votes
+	jump @fail
+
+deposit:
+	push 0
+	sload
+	jump @return
+
+voted:
+	push 4
+	calldataload
+	push 0
+	mstore
+	push 36
+	calldataload
+	push 32
+	mstore
+	push 64
+	push 0
+	sha3
+	sload
+	jump @return
+
+numvotes:
+	push 4
+	calldataload
+	push 0
+	mstore
+	push 32
+	push 0
+	sha3
+	sload
+	jump @return
+
+votes:
+	push 4
+	calldataload
+	push 0
+	mstore
+	push 36
+	calldataload
+	push 32
+	mstore
+	push 1
+	push 64
+	mstore
+	push 96
+	push 0
+	sha3
+	sload
+	jump @return
+
+vote:
+	push 0
+	sload
+	callvalue
+	lt
+	jumpi @fail
+	push 4
+	calldataload
+	push 0
+	mstore
+	caller
+	push 32
+	mstore
+	push 64
+	push 0
+	sha3
+	dup1
+	sload
+	jumpi @fail
+	push 1
+	swap1
+	sstore
+	push 32
+	push 0
+	sha3
+	dup1
+	sload
+	dup1
+	push 32
+	mstore
+	push 1
+	push 64
+	mstore
+	caller
+	push 96
+	push 0
+	sha3
+	sstore
+	push 1
+	add
+	swap1
+	sstore
+	stop
+
+return:
+	push 0
+	mstore
+	push 32
+	push 0
+	return
+
+fail:
+	push 0
+	dup1
+	revert
+`
+
+func recoveryContractCode(t *testing.T) []byte {
+	src := fmt.Sprintf(recoveryContractAsm,
+		abiObject.Methods["depositValue"].Id(),
+		abiObject.Methods["voted"].Id(),
+		abiObject.Methods["voteForSkipBlock"].Id(),
+		abiObject.Methods["numVotes"].Id(),
+		abiObject.Methods["votes"].Id())
+
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex("recovery.asm", []byte(src), false))
+	bin, errs := compiler.Compile()
+	if len(errs) != 0 {
+		t.Fatalf("failed to compile recovery contract: %v", errs)
+	}
+	code, err := hex.DecodeString(bin)
+	if err != nil {
+		t.Fatalf("failed to decode recovery contract: %v", err)
+	}
+	return code
+}
+
+type testRecoveryGovernance struct {
+	notarySet map[string]struct{}
+	dkgSet    map[common.Address]struct{}
+}
+
+func (g *testRecoveryGovernance) Round() uint64 {
+	return 1
+}
+
+func (g *testRecoveryGovernance) NotarySet(round uint64) (map[string]struct{}, error) {
+	return g.notarySet, nil
+}
+
+func (g *testRecoveryGovernance) DKGSetNodeKeyAddresses(round uint64) (map[common.Address]struct{}, error) {
+	return g.dkgSet, nil
+}
+
+func newTestRecoveryBackend(t *testing.T, keys []*ecdsa.PrivateKey) (
+	*backends.SimulatedBackend, *params.RecoveryConfig) {
+	contract := common.HexToAddress("f675c0e9bf4b949f50dcec5b224a70f0361d4680")
+	alloc := core.GenesisAlloc{
+		contract: {
+			Code:    recoveryContractCode(t),
+			Balance: big.NewInt(0),
+			Storage: map[common.Hash]common.Hash{
+				{}: common.BigToHash(big.NewInt(1e18)),
+			},
+		},
+	}
+	for _, key := range keys {
+		alloc[crypto.PubkeyToAddress(key.PublicKey)] = core.GenesisAccount{
+			Balance: new(big.Int).Mul(big.NewInt(1e18), big.NewInt(10)),
+		}
+	}
+	return backends.NewSimulatedBackend(alloc, 10000000), &params.RecoveryConfig{
+		Contract:     contract,
+		Timeout:      30,
+		Confirmation: 1,
+	}
+}
+
 func TestRecoveryVoteTxGeneration(t *testing.T) {
 	key, err := crypto.GenerateKey()
 	if err != nil {
 		t.Fatalf("failed to generate keypair: %v", err)
 	}
 
-	r := NewRecovery(&params.RecoveryConfig{
-		Contract:     common.HexToAddress("f675c0e9bf4b949f50dcec5b224a70f0361d4680"),
-		Timeout:      30,
-		Confirmation: 1,
-	}, "https://rinkeby.infura.io", nil, key)
-	_, err = r.genVoteForSkipBlockTx(0)
+	sim, config := newTestRecoveryBackend(t, []*ecdsa.PrivateKey{key})
+	r := NewRecovery(config, NewSimulatedRecoveryBackend(sim), nil, NewKeySigner(key))
+	tx, err := r.genVoteForSkipBlockTx(0)
 	if err != nil {
 		t.Fatalf("failed to generate voteForSkipBlock tx: %v", err)
 	}
+	if tx.Value().Cmp(big.NewInt(1e18)) != 0 {
+		t.Errorf("deposit mismatch: have %v, want %v", tx.Value(), big.NewInt(1e18))
+	}
+	if *tx.To() != config.Contract {
+		t.Errorf("recipient mismatch: have %x, want %x", tx.To(), config.Contract)
+	}
+}
+
+func TestRecoveryVotes(t *testing.T) {
+	var keys []*ecdsa.PrivateKey
+	for i := 0; i < 4; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatalf("failed to generate keypair: %v", err)
+		}
+		keys = append(keys, key)
+	}
+
+	// The first three nodes are in notary set, while the last node is not
+	// in DKG set.
+	gov := &testRecoveryGovernance{
+		notarySet: make(map[string]struct{}),
+		dkgSet:    make(map[common.Address]struct{}),
+	}
+	for _, key := range keys[:3] {
+		gov.notarySet[hex.EncodeToString(crypto.FromECDSAPub(&key.PublicKey))] = struct{}{}
+	}
+	for _, key := range keys[:2] {
+		gov.dkgSet[crypto.PubkeyToAddress(key.PublicKey)] = struct{}{}
+	}
+
+	sim, config := newTestRecoveryBackend(t, keys)
+	backend := NewSimulatedRecoveryBackend(sim)
+	var recoveries []*Recovery
+	for _, key := range keys {
+		recoveries = append(recoveries, NewRecovery(config, backend, gov, NewKeySigner(key)))
+	}
+
+	checkVotes := func(height, want uint64) {
+		t.Helper()
+		votes, err := recoveries[0].Votes(height)
+		if err != nil {
+			t.Fatalf("failed to get votes: %v", err)
+		}
+		if votes != want {
+			t.Errorf("votes mismatch at height %d: have %d, want %d", height, votes, want)
+		}
+	}
+
+	// Node not in notary set can not propose.
+	if err := recoveries[3].ProposeSkipBlock(10); err == nil {
+		t.Fatalf("proposing skip block without being in notary set succeeded")
+	}
+
+	for _, r := range recoveries[:3] {
+		if err := r.ProposeSkipBlock(10); err != nil {
+			t.Fatalf("failed to propose skip block: %v", err)
+		}
+	}
+	sim.Commit()
+
+	// Votes are not counted before confirmed.
+	checkVotes(10, 0)
+
+	// Voting again is a no-op.
+	if err := recoveries[0].ProposeSkipBlock(10); err != nil {
+		t.Fatalf("failed to propose skip block again: %v", err)
+	}
+	nonce, err := backend.PendingNonceAt(crypto.PubkeyToAddress(keys[0].PublicKey))
+	if err != nil {
+		t.Fatalf("failed to get pending nonce: %v", err)
+	}
+	if nonce != 1 {
+		t.Errorf("pending nonce mismatch: have %d, want %d", nonce, 1)
+	}
+	sim.Commit()
+
+	// Only votes of DKG set are counted.
+	checkVotes(10, 2)
+	checkVotes(11, 0)
 }
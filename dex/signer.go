@@ -0,0 +1,229 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	coreCrypto "github.com/dexon-foundation/dexon-consensus/core/crypto"
+	coreEcdsa "github.com/dexon-foundation/dexon-consensus/core/crypto/ecdsa"
+
+	"github.com/dexon-foundation/dexon/accounts"
+	"github.com/dexon-foundation/dexon/accounts/keystore"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/internal/ethapi"
+	"github.com/dexon-foundation/dexon/log"
+	"github.com/dexon-foundation/dexon/params"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+// ecdsaSignatureType is the signature type of the consensus core ecdsa keys.
+const ecdsaSignatureType = "ecdsa"
+
+// TxSigner signs transactions sent from an account.
+type TxSigner interface {
+	// Address returns the account the transactions are sent from.
+	Address() common.Address
+
+	// SignTx signs the transaction for the chain.
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// ConsensusSigner signs consensus messages with the node key, and the
+// transactions sent from the node key address.
+type ConsensusSigner interface {
+	coreCrypto.PrivateKey
+	TxSigner
+}
+
+// keySigner signs with an in-memory private key.
+type keySigner struct {
+	*coreEcdsa.PrivateKey
+
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner creates a consensus signer of an in-memory private key.
+func NewKeySigner(key *ecdsa.PrivateKey) ConsensusSigner {
+	return &keySigner{
+		PrivateKey: coreEcdsa.NewPrivateKeyFromECDSA(key),
+		key:        key,
+	}
+}
+
+func (s *keySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), s.key)
+}
+
+// NewKeystoreSigner decrypts the key of the keystore account and creates a
+// consensus signer of it. The key is kept in the signer only, the account
+// stays locked in the keystore.
+func NewKeystoreSigner(ks *keystore.KeyStore, address common.Address,
+	passphrase string) (ConsensusSigner, error) {
+	account, err := ks.Find(accounts.Account{Address: address})
+	if err != nil {
+		return nil, fmt.Errorf("account %x: %v", address, err)
+	}
+	keyJSON, err := ioutil.ReadFile(account.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt account %x: %v", address, err)
+	}
+	if key.Address != address {
+		return nil, fmt.Errorf("key content mismatch: have account %x, want %x", key.Address, address)
+	}
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// signTxArgs are the arguments of account_signTransaction.
+type signTxArgs struct {
+	From     common.MixedcaseAddress  `json:"from"`
+	To       *common.MixedcaseAddress `json:"to"`
+	Gas      hexutil.Uint64           `json:"gas"`
+	GasPrice hexutil.Big              `json:"gasPrice"`
+	Value    hexutil.Big              `json:"value"`
+	Nonce    hexutil.Uint64           `json:"nonce"`
+	Data     *hexutil.Bytes           `json:"data"`
+}
+
+// externalTxSigner signs transactions with an external signer like clef.
+// External signers only sign EIP-191 prefixed data, so they can not sign
+// consensus messages.
+type externalTxSigner struct {
+	client  *rpc.Client
+	address common.Address
+}
+
+// NewExternalTxSigner creates a transaction signer of the account managed by
+// the external signer connected by the client.
+func NewExternalTxSigner(client *rpc.Client, address common.Address) TxSigner {
+	return &externalTxSigner{client: client, address: address}
+}
+
+func (s *externalTxSigner) Address() common.Address {
+	return s.address
+}
+
+func (s *externalTxSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if tx.To() == nil {
+		return nil, errors.New("contract creation not supported")
+	}
+	to := common.NewMixedcaseAddress(*tx.To())
+	data := hexutil.Bytes(tx.Data())
+	args := signTxArgs{
+		From:     common.NewMixedcaseAddress(s.address),
+		To:       &to,
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: hexutil.Big(*tx.GasPrice()),
+		Value:    hexutil.Big(*tx.Value()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		Data:     &data,
+	}
+	var result ethapi.SignTransactionResult
+	if err := s.client.CallContext(context.Background(), &result,
+		"account_signTransaction", &args, nil); err != nil {
+		return nil, err
+	}
+	if result.Tx.ChainId().Cmp(chainID) != 0 {
+		return nil, fmt.Errorf("signer signed for chain %v, want %v", result.Tx.ChainId(), chainID)
+	}
+	return result.Tx, nil
+}
+
+// checkConsensusKey checks that a consensus signer other than the p2p node key
+// can be used on the chain. Before the node key separation fork the node
+// registers a single key, which peers also dial the notary node by.
+func checkConsensusKey(config *params.ChainConfig, head *big.Int,
+	nodeKey *ecdsa.PrivateKey, signer common.Address) error {
+	if nodeKey == nil || crypto.PubkeyToAddress(nodeKey.PublicKey) == signer {
+		return nil
+	}
+	if config.NodeKeySeparationBlock == nil {
+		return errors.New("separate consensus key requires the node key separation fork")
+	}
+	if !config.IsNodeKeySeparation(head) {
+		log.Warn("#############################################################")
+		log.Warn("Consensus key differs from the node key before the node key separation fork")
+		log.Warn("Peers dial the notary node by its consensus key until the fork",
+			"fork", config.NodeKeySeparationBlock, "head", head)
+		log.Warn("#############################################################")
+	}
+	return nil
+}
+
+// newConsensusSigner creates the consensus signer of the configured keystore
+// account, or of the private key if no account is configured.
+func newConsensusSigner(am *accounts.Manager, config *Config) (ConsensusSigner, error) {
+	if config.ConsensusAccount == (common.Address{}) {
+		if config.PrivateKey == nil {
+			return nil, errors.New("no consensus key")
+		}
+		return NewKeySigner(config.PrivateKey), nil
+	}
+	ks, err := findKeyStore(am)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeystoreSigner(ks, config.ConsensusAccount, config.ConsensusPassphrase)
+}
+
+// newOperatorSigner creates the transaction signer of the configured operator
+// account, nil if no operator account is configured.
+func newOperatorSigner(am *accounts.Manager, config *Config) (TxSigner, error) {
+	if config.OperatorAccount == (common.Address{}) {
+		return nil, nil
+	}
+	if config.OperatorSigner != "" {
+		client, err := rpc.Dial(config.OperatorSigner)
+		if err != nil {
+			return nil, err
+		}
+		return NewExternalTxSigner(client, config.OperatorAccount), nil
+	}
+	ks, err := findKeyStore(am)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeystoreSigner(ks, config.OperatorAccount, config.OperatorPassphrase)
+}
+
+func findKeyStore(am *accounts.Manager) (*keystore.KeyStore, error) {
+	if am == nil {
+		return nil, errors.New("no account manager")
+	}
+	backends := am.Backends(keystore.KeyStoreType)
+	if len(backends) == 0 {
+		return nil, errors.New("keystore not available")
+	}
+	return backends[0].(*keystore.KeyStore), nil
+}
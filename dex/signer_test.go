@@ -0,0 +1,195 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package dex
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
+	coreEcdsa "github.com/dexon-foundation/dexon-consensus/core/crypto/ecdsa"
+
+	"github.com/dexon-foundation/dexon/accounts/keystore"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/internal/ethapi"
+	"github.com/dexon-foundation/dexon/params"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+// checkSigner checks the signer signs consensus messages and transactions as
+// the key.
+func checkSigner(t *testing.T, signer ConsensusSigner, key *ecdsa.PrivateKey) {
+	if !bytes.Equal(signer.PublicKey().Bytes(), crypto.FromECDSAPub(&key.PublicKey)) {
+		t.Errorf("public key mismatch")
+	}
+	hash := coreCommon.NewRandomHash()
+	sig, err := signer.Sign(hash)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	want, err := coreEcdsa.NewPrivateKeyFromECDSA(key).Sign(hash)
+	if err != nil {
+		t.Fatalf("failed to sign hash: %v", err)
+	}
+	if sig.Type != want.Type || !bytes.Equal(sig.Signature, want.Signature) {
+		t.Errorf("signature mismatch: have %v, want %v", sig, want)
+	}
+	checkTxSigner(t, signer, crypto.PubkeyToAddress(key.PublicKey))
+}
+
+func checkTxSigner(t *testing.T, signer TxSigner, address common.Address) {
+	if signer.Address() != address {
+		t.Errorf("address mismatch: have %x, want %x", signer.Address(), address)
+	}
+	chainID := big.NewInt(237)
+	tx, err := signer.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(0),
+		21000, big.NewInt(1), nil), chainID)
+	if err != nil {
+		t.Fatalf("failed to sign tx: %v", err)
+	}
+	if from, err := types.Sender(types.NewEIP155Signer(chainID), tx); err != nil || from != address {
+		t.Errorf("sender mismatch: have %x, err %v", from, err)
+	}
+}
+
+func TestKeySigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	checkSigner(t, NewKeySigner(key), key)
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dex-signer-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	account, err := ks.ImportECDSA(key, "foo")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+
+	if _, err := NewKeystoreSigner(ks, account.Address, "bar"); err == nil {
+		t.Errorf("unlocked account with wrong passphrase")
+	}
+	signer, err := NewKeystoreSigner(ks, account.Address, "foo")
+	if err != nil {
+		t.Fatalf("failed to create keystore signer: %v", err)
+	}
+	checkSigner(t, signer, key)
+
+	// The account is not unlocked in the keystore.
+	if _, err := ks.SignHash(account, crypto.Keccak256(nil)); err != keystore.ErrLocked {
+		t.Errorf("keystore sign error mismatch: have %v, want %v", err, keystore.ErrLocked)
+	}
+}
+
+// StubSignTxArgs are the account_signTransaction arguments received by
+// StubExternalSigner, exported to be an RPC argument.
+type StubSignTxArgs signTxArgs
+
+// StubExternalSigner serves account_signTransaction like clef. It is exported
+// to be registered as an RPC service.
+type StubExternalSigner struct {
+	key     *ecdsa.PrivateKey
+	chainID *big.Int
+}
+
+func (s *StubExternalSigner) SignTransaction(ctx context.Context, args StubSignTxArgs,
+	methodSelector *string) (*ethapi.SignTransactionResult, error) {
+	tx := types.NewTransaction(uint64(args.Nonce), args.To.Address(), (*big.Int)(&args.Value),
+		uint64(args.Gas), (*big.Int)(&args.GasPrice), *args.Data)
+	tx, err := types.SignTx(tx, types.NewEIP155Signer(s.chainID), s.key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &ethapi.SignTransactionResult{Raw: raw, Tx: tx}, nil
+}
+
+func TestExternalTxSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("account", &StubExternalSigner{key, big.NewInt(237)}); err != nil {
+		t.Fatalf("failed to register signer: %v", err)
+	}
+	signer := NewExternalTxSigner(rpc.DialInProc(server), address)
+	checkTxSigner(t, signer, address)
+
+	// Transactions signed for another chain are rejected.
+	if _, err := signer.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(0),
+		21000, big.NewInt(1), nil), big.NewInt(238)); err == nil {
+		t.Errorf("accepted transaction signed for another chain")
+	}
+}
+
+func TestCheckConsensusKey(t *testing.T) {
+	nodeKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	consensusKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	nodeAddr := crypto.PubkeyToAddress(nodeKey.PublicKey)
+	consensusAddr := crypto.PubkeyToAddress(consensusKey.PublicKey)
+
+	config := *params.TestChainConfig
+	config.NodeKeySeparationBlock = nil
+	if err := checkConsensusKey(&config, big.NewInt(0), nodeKey, nodeAddr); err != nil {
+		t.Errorf("node key refused without fork: %v", err)
+	}
+	if err := checkConsensusKey(&config, big.NewInt(0), nodeKey, consensusAddr); err == nil {
+		t.Errorf("separate consensus key accepted without fork")
+	}
+
+	// The fork is scheduled, the key is accepted ahead of it.
+	config.NodeKeySeparationBlock = big.NewInt(10)
+	if err := checkConsensusKey(&config, big.NewInt(0), nodeKey, consensusAddr); err != nil {
+		t.Errorf("separate consensus key refused before scheduled fork: %v", err)
+	}
+	if err := checkConsensusKey(&config, big.NewInt(10), nodeKey, consensusAddr); err != nil {
+		t.Errorf("separate consensus key refused after fork: %v", err)
+	}
+}
@@ -135,6 +135,25 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	}
 	head := header.Number.Uint64()
 
+	// Resolve the aliases of the first blocks of rounds. A range beginning
+	// with a round not begun yet is empty, one ending with it is unbounded.
+	if _, ok := rpc.BlockNumber(f.begin).RoundStart(); ok {
+		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(f.begin))
+		if header == nil || err != nil {
+			return nil, err
+		}
+		f.begin = header.Number.Int64()
+	}
+	if _, ok := rpc.BlockNumber(f.end).RoundStart(); ok {
+		header, err := f.backend.HeaderByNumber(ctx, rpc.BlockNumber(f.end))
+		if err != nil {
+			return nil, err
+		}
+		f.end = -1
+		if header != nil {
+			f.end = header.Number.Int64()
+		}
+	}
 	if f.begin == -1 {
 		f.begin = int64(head)
 	}
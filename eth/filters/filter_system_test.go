@@ -38,6 +38,10 @@ import (
 	"github.com/dexon-foundation/dexon/rpc"
 )
 
+// testRoundLength is the length of rounds resolving the round start block
+// number alias in testBackend.
+const testRoundLength = 10
+
 type testBackend struct {
 	mux        *event.TypeMux
 	db         ethdb.Database
@@ -61,6 +65,9 @@ func (b *testBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumbe
 		hash common.Hash
 		num  uint64
 	)
+	if round, ok := blockNr.RoundStart(); ok {
+		blockNr = rpc.BlockNumber(round * testRoundLength)
+	}
 	if blockNr == rpc.LatestBlockNumber {
 		hash = rawdb.ReadHeadBlockHash(b.db)
 		number := rawdb.ReadHeaderNumber(b.db, hash)
@@ -378,6 +385,60 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 	}
 }
 
+// TestGetLogsRoundStart tests resolving the round start block number alias in
+// the block range of logs.
+func TestGetLogsRoundStart(t *testing.T) {
+	var (
+		mux        = new(event.TypeMux)
+		db         = ethdb.NewMemDatabase()
+		txFeed     = new(event.Feed)
+		rmLogsFeed = new(event.Feed)
+		logsFeed   = new(event.Feed)
+		chainFeed  = new(event.Feed)
+		backend    = &testBackend{mux, db, 0, txFeed, rmLogsFeed, logsFeed, chainFeed}
+		api        = NewPublicFilterAPI(backend, false)
+		addr       = common.HexToAddress("0x1111111111111111111111111111111111111111")
+	)
+
+	// Each block has a log.
+	genesis := new(core.Genesis).MustCommit(db)
+	chain, receipts := core.GenerateChain(params.TestChainConfig, genesis, ethash.NewFaker(), db, 3*testRoundLength, func(i int, gen *core.BlockGen) {
+		receipt := types.NewReceipt(nil, false, 0)
+		receipt.Logs = []*types.Log{{Address: addr}}
+		gen.AddUncheckedReceipt(receipt)
+	})
+	for i, block := range chain {
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteHeadBlockHash(db, block.Hash())
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts[i])
+	}
+
+	testCases := []struct {
+		input string
+		logs  int
+	}{
+		0: {`{"fromBlock":"round:0x1","toBlock":"round:0x2"}`, testRoundLength + 1},
+		1: {`{"fromBlock":"round:0x2"}`, testRoundLength + 1},
+		2: {`{"fromBlock":"round:0x2","toBlock":"round:0x5"}`, testRoundLength + 1},
+		3: {`{"fromBlock":"round:0x5"}`, 0},
+		4: {`{"fromBlock":"0x0","toBlock":"round:0x1"}`, testRoundLength},
+	}
+	for i, test := range testCases {
+		var crit FilterCriteria
+		if err := crit.UnmarshalJSON([]byte(test.input)); err != nil {
+			t.Fatalf("test %d: failed to unmarshal criteria: %v", i, err)
+		}
+		logs, err := api.GetLogs(context.Background(), crit)
+		if err != nil {
+			t.Fatalf("test %d: failed to get logs: %v", i, err)
+		}
+		if len(logs) != test.logs {
+			t.Errorf("test %d: log count mismatch: have %d, want %d", i, len(logs), test.logs)
+		}
+	}
+}
+
 // TestLogFilter tests whether log filters match the correct logs that are posted to the event feed.
 func TestLogFilter(t *testing.T) {
 	t.Parallel()
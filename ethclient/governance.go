@@ -0,0 +1,457 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	coreCommon "github.com/dexon-foundation/dexon-consensus/common"
+	dexCore "github.com/dexon-foundation/dexon-consensus/core"
+	coreEcdsa "github.com/dexon-foundation/dexon-consensus/core/crypto/ecdsa"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+
+	"github.com/dexon-foundation/dexon"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/event"
+	"github.com/dexon-foundation/dexon/params"
+)
+
+var (
+	errGovernanceReverted = errors.New("governance call reverted")
+	errRoundNotReady      = errors.New("round not ready")
+)
+
+// decimalMultiplier scales the fractional configurations stored in the
+// governance contract.
+const decimalMultiplier = 100000000.0
+
+// GovernanceNode is a node registered in the governance contract.
+type GovernanceNode struct {
+	Owner      common.Address
+	PublicKey  []byte
+	Staked     *big.Int
+	Fined      *big.Int
+	Name       string
+	Email      string
+	Location   string
+	Url        string
+	Unstaked   *big.Int
+	UnstakedAt *big.Int
+}
+
+// DKGStatus is the progress of the DKG of the round being prepared.
+type DKGStatus struct {
+	Round          uint64
+	ResetCount     uint64
+	MPKReadyCount  uint64
+	FinalizedCount uint64
+	SuccessCount   uint64
+}
+
+// GovernanceEventQuery selects governance events. All fields are optional.
+type GovernanceEventQuery struct {
+	// NodeAddress restricts the result to events whose NodeAddress matches.
+	NodeAddress *common.Address
+
+	// Events restricts the result to the named events, e.g. "Staked".
+	Events []string
+}
+
+// GovernanceEvent is a decoded governance contract event.
+type GovernanceEvent struct {
+	Name string
+	Args map[string]interface{}
+	Log  types.Log
+}
+
+// callGovernance calls a view method of the governance contract on the state
+// of the given block and unpacks the result into out.
+func (ec *Client) callGovernance(ctx context.Context, blockNumber *big.Int, out interface{}, method string, args ...interface{}) error {
+	input, err := vm.GovernanceABI.ABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+	to := vm.GovernanceContractAddress
+	output, err := ec.CallContract(ctx, dexon.CallMsg{To: &to, Data: input}, blockNumber)
+	if err != nil {
+		return err
+	}
+	// Every view method returns something, an empty output means the call
+	// was reverted.
+	if len(output) == 0 {
+		return errGovernanceReverted
+	}
+	return vm.GovernanceABI.ABI.Unpack(out, method, output)
+}
+
+func (ec *Client) governanceUint(ctx context.Context, blockNumber *big.Int, method string, args ...interface{}) (*big.Int, error) {
+	var value *big.Int
+	if err := ec.callGovernance(ctx, blockNumber, &value, method, args...); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// latestBlockNumber returns the given block number, or the number of the latest
+// block if it is nil, so that several calls are done on the same state.
+func (ec *Client) latestBlockNumber(ctx context.Context, blockNumber *big.Int) (*big.Int, error) {
+	if blockNumber != nil {
+		return blockNumber, nil
+	}
+	head, err := ec.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return head.Number, nil
+}
+
+// RoundHeight returns the height of the first block of the round, recorded in
+// the state of the given block. The height of a round not yet begun is zero.
+func (ec *Client) RoundHeight(ctx context.Context, round uint64, blockNumber *big.Int) (uint64, error) {
+	height, err := ec.governanceUint(ctx, blockNumber, "roundHeight", new(big.Int).SetUint64(round))
+	if err != nil {
+		return 0, err
+	}
+	return height.Uint64(), nil
+}
+
+// CRSRound returns the latest round whose CRS is proposed in the state of the
+// given block.
+func (ec *Client) CRSRound(ctx context.Context, blockNumber *big.Int) (uint64, error) {
+	round, err := ec.governanceUint(ctx, blockNumber, "crsRound")
+	if err != nil {
+		return 0, err
+	}
+	return round.Uint64(), nil
+}
+
+// CRS returns the common reference string of the given round.
+func (ec *Client) CRS(ctx context.Context, round uint64) (common.Hash, error) {
+	latest, err := ec.latestBlockNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return ec.crs(ctx, round, latest)
+}
+
+func (ec *Client) crs(ctx context.Context, round uint64, latest *big.Int) (common.Hash, error) {
+	var crs common.Hash
+	// CRS of the rounds before DKG begins are derived from the genesis one.
+	if round <= dexCore.DKGDelayRound {
+		if err := ec.callGovernance(ctx, big.NewInt(0), &crs, "crs"); err != nil {
+			return common.Hash{}, err
+		}
+		for i := uint64(0); i < round; i++ {
+			crs = crypto.Keccak256Hash(crs[:])
+		}
+		return crs, nil
+	}
+
+	crsRound, err := ec.CRSRound(ctx, latest)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	switch {
+	case round > crsRound:
+		return common.Hash{}, errRoundNotReady
+	case round < crsRound:
+		height, err := ec.RoundHeight(ctx, round, latest)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		latest = new(big.Int).SetUint64(height)
+	}
+	if err := ec.callGovernance(ctx, latest, &crs, "crs"); err != nil {
+		return common.Hash{}, err
+	}
+	return crs, nil
+}
+
+// Nodes returns the nodes registered in the governance contract in the state
+// of the given block.
+func (ec *Client) Nodes(ctx context.Context, blockNumber *big.Int) ([]*GovernanceNode, error) {
+	blockNumber, err := ec.latestBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	length, err := ec.governanceUint(ctx, blockNumber, "nodesLength")
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*GovernanceNode, 0, length.Uint64())
+	for i := uint64(0); i < length.Uint64(); i++ {
+		node, err := ec.node(ctx, blockNumber, new(big.Int).SetUint64(i))
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// NodeByAddress returns the node owned by the given address in the state of
+// the given block. It returns NotFound if the address owns no node.
+func (ec *Client) NodeByAddress(ctx context.Context, owner common.Address, blockNumber *big.Int) (*GovernanceNode, error) {
+	blockNumber, err := ec.latestBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := ec.governanceUint(ctx, blockNumber, "nodesOffsetByAddress", owner)
+	if err != nil {
+		return nil, err
+	}
+	if offset.Sign() < 0 {
+		return nil, dexon.NotFound
+	}
+	return ec.node(ctx, blockNumber, offset)
+}
+
+func (ec *Client) node(ctx context.Context, blockNumber *big.Int, index *big.Int) (*GovernanceNode, error) {
+	node := new(GovernanceNode)
+	if err := ec.callGovernance(ctx, blockNumber, node, "nodes", index); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// NotarySet returns the nodes in the notary set of the given round, which is
+// drawn by the CRS of the round from the qualified nodes ConfigRoundShift
+// rounds before.
+func (ec *Client) NotarySet(ctx context.Context, round uint64) ([]*GovernanceNode, error) {
+	latest, err := ec.latestBlockNumber(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	crs, err := ec.crs(ctx, round, latest)
+	if err != nil {
+		return nil, err
+	}
+
+	configRound := uint64(0)
+	if round > dexCore.ConfigRoundShift {
+		configRound = round - dexCore.ConfigRoundShift
+	}
+	height, err := ec.RoundHeight(ctx, configRound, latest)
+	if err != nil {
+		return nil, err
+	}
+	if configRound != 0 && height == 0 {
+		return nil, errRoundNotReady
+	}
+	blockNumber := new(big.Int).SetUint64(height)
+
+	size, err := ec.governanceUint(ctx, blockNumber, "notarySetSize")
+	if err != nil {
+		return nil, err
+	}
+	minStake, err := ec.governanceUint(ctx, blockNumber, "minStake")
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := ec.Nodes(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeSet := coreTypes.NewNodeSet()
+	ids := make([]coreTypes.NodeID, len(nodes))
+	for i, node := range nodes {
+		// Nodes with unpaid fine are unqualified.
+		if node.Fined.Sign() > 0 {
+			continue
+		}
+		delegated, err := ec.governanceUint(ctx, blockNumber, "delegated", node.Owner)
+		if err == errGovernanceReverted {
+			// Delegation is not activated yet.
+			delegated = new(big.Int)
+		} else if err != nil {
+			return nil, err
+		}
+		if new(big.Int).Add(node.Staked, delegated).Cmp(minStake) < 0 {
+			continue
+		}
+		var jailed bool
+		err = ec.callGovernance(ctx, blockNumber, &jailed, "jailed", node.Owner)
+		if err != nil && err != errGovernanceReverted {
+			return nil, err
+		}
+		// Jailed nodes are unqualified, jailing is not activated yet if reverted.
+		if jailed {
+			continue
+		}
+		key, err := coreEcdsa.NewPublicKeyFromByteSlice(node.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid public key of node %x: %v", node.Owner, err)
+		}
+		ids[i] = coreTypes.NewNodeID(key)
+		nodeSet.Add(ids[i])
+	}
+
+	notarySet := nodeSet.GetSubSet(int(size.Uint64()), coreTypes.NewNotarySetTarget(coreCommon.Hash(crs)))
+	var notaries []*GovernanceNode
+	for i, node := range nodes {
+		if _, exists := notarySet[ids[i]]; exists {
+			notaries = append(notaries, node)
+		}
+	}
+	return notaries, nil
+}
+
+// DexconConfiguration returns the consensus configuration in the state of the
+// given block. FineValues holds the fines of the known fine types.
+func (ec *Client) DexconConfiguration(ctx context.Context, blockNumber *big.Int) (*params.DexconConfig, error) {
+	blockNumber, err := ec.latestBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var owner common.Address
+	if err := ec.callGovernance(ctx, blockNumber, &owner, "owner"); err != nil {
+		return nil, err
+	}
+
+	methods := []string{
+		"minStake", "lockupPeriod", "miningVelocity", "nextHalvingSupply",
+		"lastHalvedAmount", "minGasPrice", "blockGasLimit", "lambdaBA",
+		"lambdaDKG", "notarySetSize", "notaryParamAlpha", "notaryParamBeta",
+		"roundLength", "minBlockInterval",
+	}
+	values := make(map[string]*big.Int, len(methods))
+	for _, method := range methods {
+		value, err := ec.governanceUint(ctx, blockNumber, method)
+		if err != nil {
+			return nil, err
+		}
+		values[method] = value
+	}
+	decimal := func(method string) float32 {
+		return float32(values[method].Uint64()) / decimalMultiplier
+	}
+
+	fineValues := make([]*big.Int, 0, vm.FineTypeForkBlock+1)
+	for i := int64(vm.FineTypeFailStop); i <= vm.FineTypeForkBlock; i++ {
+		value, err := ec.governanceUint(ctx, blockNumber, "fineValues", big.NewInt(i))
+		if err != nil {
+			return nil, err
+		}
+		fineValues = append(fineValues, value)
+	}
+
+	return &params.DexconConfig{
+		Owner:             owner,
+		MinStake:          values["minStake"],
+		LockupPeriod:      values["lockupPeriod"].Uint64(),
+		MiningVelocity:    decimal("miningVelocity"),
+		NextHalvingSupply: values["nextHalvingSupply"],
+		LastHalvedAmount:  values["lastHalvedAmount"],
+		MinGasPrice:       values["minGasPrice"],
+		BlockGasLimit:     values["blockGasLimit"].Uint64(),
+		LambdaBA:          values["lambdaBA"].Uint64(),
+		LambdaDKG:         values["lambdaDKG"].Uint64(),
+		NotarySetSize:     uint32(values["notarySetSize"].Uint64()),
+		NotaryParamAlpha:  decimal("notaryParamAlpha"),
+		NotaryParamBeta:   decimal("notaryParamBeta"),
+		RoundLength:       values["roundLength"].Uint64(),
+		MinBlockInterval:  values["minBlockInterval"].Uint64(),
+		FineValues:        fineValues,
+	}, nil
+}
+
+// DKGStatus returns the progress of the DKG in the state of the given block.
+func (ec *Client) DKGStatus(ctx context.Context, blockNumber *big.Int) (*DKGStatus, error) {
+	blockNumber, err := ec.latestBlockNumber(ctx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	round, err := ec.governanceUint(ctx, blockNumber, "dkgRound")
+	if err != nil {
+		return nil, err
+	}
+	resetCount, err := ec.governanceUint(ctx, blockNumber, "dkgResetCount", round)
+	if err != nil {
+		return nil, err
+	}
+	mpkReadyCount, err := ec.governanceUint(ctx, blockNumber, "dkgMPKReadysCount")
+	if err != nil {
+		return nil, err
+	}
+	finalizedCount, err := ec.governanceUint(ctx, blockNumber, "dkgFinalizedsCount")
+	if err != nil {
+		return nil, err
+	}
+	successCount, err := ec.governanceUint(ctx, blockNumber, "dkgSuccessesCount")
+	if err != nil {
+		return nil, err
+	}
+	return &DKGStatus{
+		Round:          round.Uint64(),
+		ResetCount:     resetCount.Uint64(),
+		MPKReadyCount:  mpkReadyCount.Uint64(),
+		FinalizedCount: finalizedCount.Uint64(),
+		SuccessCount:   successCount.Uint64(),
+	}, nil
+}
+
+// SubscribeGovernanceEvents subscribes to the governance contract events
+// matching the given query, decoding them as they arrive.
+func (ec *Client) SubscribeGovernanceEvents(ctx context.Context, q GovernanceEventQuery, ch chan<- *GovernanceEvent) (dexon.Subscription, error) {
+	filter := dexon.FilterQuery{
+		Addresses: []common.Address{vm.GovernanceContractAddress},
+	}
+	var indexed []common.Hash
+	if q.NodeAddress != nil {
+		indexed = append(indexed, q.NodeAddress.Hash())
+	}
+	topics, err := vm.GovernanceABI.EventTopics(q.Events, indexed...)
+	if err != nil {
+		return nil, err
+	}
+	filter.Topics = topics
+
+	logs := make(chan types.Log)
+	sub, err := ec.SubscribeFilterLogs(ctx, filter, logs)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				e, args, err := vm.GovernanceABI.DecodeEvent(&log)
+				if err != nil {
+					return err
+				}
+				select {
+				case ch <- &GovernanceEvent{Name: e.Name, Args: args, Log: log}:
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
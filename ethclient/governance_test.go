@@ -0,0 +1,263 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/dexon-foundation/dexon"
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
+	"github.com/dexon-foundation/dexon/consensus"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/ethdb"
+	"github.com/dexon-foundation/dexon/params"
+	"github.com/dexon-foundation/dexon/rpc"
+)
+
+// GovernanceTestService serves the calls needed by the governance methods on
+// the genesis state.
+type GovernanceTestService struct {
+	db      ethdb.Database
+	config  *params.ChainConfig
+	genesis *types.Block
+	logs    []*types.Log
+}
+
+type GovernanceTestCallArgs struct {
+	To   *common.Address `json:"to"`
+	Data hexutil.Bytes   `json:"data"`
+}
+
+// testChainContext serves the genesis block as the only block of the chain.
+type testChainContext struct {
+	db      ethdb.Database
+	genesis *types.Block
+}
+
+func (c *testChainContext) Engine() consensus.Engine { return nil }
+
+func (c *testChainContext) GetHeader(hash common.Hash, number uint64) *types.Header {
+	if hash != c.genesis.Hash() || number != 0 {
+		return nil
+	}
+	return c.genesis.Header()
+}
+
+func (c *testChainContext) StateAt(root common.Hash) (*state.StateDB, error) {
+	return state.New(root, state.NewDatabase(c.db))
+}
+
+func (c *testChainContext) GetHeaderByNumber(number uint64) *types.Header {
+	if number != 0 {
+		return nil
+	}
+	return c.genesis.Header()
+}
+
+func (c *testChainContext) GetRoundHeight(round uint64) (uint64, bool) {
+	return 0, round == 0
+}
+
+func (s *GovernanceTestService) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (*types.Header, error) {
+	if number > 0 {
+		return nil, nil
+	}
+	return s.genesis.Header(), nil
+}
+
+func (s *GovernanceTestService) Call(ctx context.Context, args GovernanceTestCallArgs, number rpc.BlockNumber) (hexutil.Bytes, error) {
+	if number > 0 {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	statedb, err := state.New(s.genesis.Root(), state.NewDatabase(s.db))
+	if err != nil {
+		return nil, err
+	}
+	chain := &testChainContext{db: s.db, genesis: s.genesis}
+	msg := types.NewMessage(common.Address{}, args.To, 0, new(big.Int), math.MaxUint64/2, new(big.Int), args.Data, false)
+	evm := vm.NewEVM(core.NewEVMContext(msg, s.genesis.Header(), chain, &common.Address{}), statedb, s.config, vm.Config{})
+	ret, _, failed, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(math.MaxUint64))
+	if err != nil || failed {
+		return nil, err
+	}
+	return ret, nil
+}
+
+func (s *GovernanceTestService) Logs(ctx context.Context, crit interface{}) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub := notifier.CreateSubscription()
+	go func() {
+		for _, log := range s.logs {
+			notifier.Notify(sub.ID, log)
+		}
+	}()
+	return sub, nil
+}
+
+func newTestGovernanceClient(t *testing.T, logs []*types.Log) (*Client, *core.Genesis, *vm.GovernanceState) {
+	db := ethdb.NewMemDatabase()
+	gspec := core.DefaultTestnetGenesisBlock()
+	genesis := gspec.MustCommit(db)
+
+	statedb, err := state.New(genesis.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to get genesis state: %v", err)
+	}
+
+	server := rpc.NewServer()
+	service := &GovernanceTestService{db: db, config: gspec.Config, genesis: genesis, logs: logs}
+	if err := server.RegisterName("eth", service); err != nil {
+		t.Fatalf("failed to register service: %v", err)
+	}
+	return NewClient(rpc.DialInProc(server)), gspec, &vm.GovernanceState{StateDB: statedb}
+}
+
+func TestGovernanceMethods(t *testing.T) {
+	client, gspec, gs := newTestGovernanceClient(t, nil)
+	defer client.Close()
+	ctx := context.Background()
+
+	height, err := client.RoundHeight(ctx, 0, nil)
+	if err != nil {
+		t.Fatalf("failed to get round height: %v", err)
+	}
+	if height != 0 {
+		t.Errorf("round height mismatch: have %d, want 0", height)
+	}
+
+	genesisCRS := crypto.Keccak256Hash([]byte(gspec.Config.Dexcon.GenesisCRSText))
+	crs, err := client.CRS(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to get crs: %v", err)
+	}
+	if want := crypto.Keccak256Hash(genesisCRS[:]); crs != want {
+		t.Errorf("crs mismatch: have %x, want %x", crs, want)
+	}
+	if _, err := client.CRS(ctx, 3); err != errRoundNotReady {
+		t.Errorf("unready crs error mismatch: have %v, want %v", err, errRoundNotReady)
+	}
+
+	config, err := client.DexconConfiguration(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to get configuration: %v", err)
+	}
+	want := gspec.Config.Dexcon
+	if config.RoundLength != want.RoundLength || config.MinStake.Cmp(want.MinStake) != 0 ||
+		config.NotarySetSize != uint32(gs.NotarySetSize().Uint64()) || config.Owner != want.Owner {
+		t.Errorf("configuration mismatch: have %+v, want %+v", config, want)
+	}
+	if len(config.FineValues) != len(want.FineValues) {
+		t.Errorf("fine values mismatch: have %v, want %v", config.FineValues, want.FineValues)
+	}
+
+	nodes, err := client.Nodes(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to get nodes: %v", err)
+	}
+	if len(nodes) == 0 {
+		t.Fatalf("no node in genesis")
+	}
+	node, err := client.NodeByAddress(ctx, nodes[0].Owner, nil)
+	if err != nil {
+		t.Fatalf("failed to get node by address: %v", err)
+	}
+	if node.Owner != nodes[0].Owner || node.Staked.Cmp(nodes[0].Staked) != 0 {
+		t.Errorf("node mismatch: have %+v, want %+v", node, nodes[0])
+	}
+	if _, err := client.NodeByAddress(ctx, common.Address{}, nil); err != dexon.NotFound {
+		t.Errorf("unknown node error mismatch: have %v, want %v", err, dexon.NotFound)
+	}
+
+	notarySet, err := client.NotarySet(ctx, 0)
+	if err != nil {
+		t.Fatalf("failed to get notary set: %v", err)
+	}
+	size := int(config.NotarySetSize)
+	if len(nodes) < size {
+		size = len(nodes)
+	}
+	if len(notarySet) != size {
+		t.Errorf("notary set size mismatch: have %d, want %d", len(notarySet), size)
+	}
+
+	status, err := client.DKGStatus(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to get dkg status: %v", err)
+	}
+	if status.Round != gs.DKGRound().Uint64() || status.ResetCount != 0 {
+		t.Errorf("dkg status mismatch: have %+v", status)
+	}
+}
+
+func TestSubscribeGovernanceEvents(t *testing.T) {
+	nodeAddr := common.HexToAddress("0x1")
+	amount, err := vm.GovernanceABI.Events["Staked"].Inputs.NonIndexed().Pack(big.NewInt(100))
+	if err != nil {
+		t.Fatalf("failed to pack event: %v", err)
+	}
+	logs := []*types.Log{{
+		Address: vm.GovernanceContractAddress,
+		Topics:  []common.Hash{vm.GovernanceABI.Events["Staked"].Id(), nodeAddr.Hash()},
+		Data:    amount,
+	}}
+	client, _, _ := newTestGovernanceClient(t, logs)
+	defer client.Close()
+
+	if _, err := client.SubscribeGovernanceEvents(context.Background(),
+		GovernanceEventQuery{Events: []string{"Unknown"}}, make(chan *GovernanceEvent)); err == nil {
+		t.Errorf("subscribed to unknown event")
+	}
+
+	events := make(chan *GovernanceEvent)
+	sub, err := client.SubscribeGovernanceEvents(context.Background(),
+		GovernanceEventQuery{NodeAddress: &nodeAddr, Events: []string{"Staked"}}, events)
+	if err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case event := <-events:
+		if event.Name != "Staked" {
+			t.Errorf("event name mismatch: have %s, want Staked", event.Name)
+		}
+		if event.Args["NodeAddress"] != nodeAddr {
+			t.Errorf("node address mismatch: have %v, want %v", event.Args["NodeAddress"], nodeAddr)
+		}
+		if event.Args["Amount"].(*big.Int).Cmp(big.NewInt(100)) != 0 {
+			t.Errorf("amount mismatch: have %v, want 100", event.Args["Amount"])
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(time.Second):
+		t.Fatalf("event not received")
+	}
+}
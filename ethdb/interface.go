@@ -16,6 +16,8 @@
 
 package ethdb
 
+import "github.com/syndtr/goleveldb/leveldb/iterator"
+
 // Code using batches should try to add this much data to the batch.
 // The value was determined empirically.
 const IdealBatchSize = 100 * 1024
@@ -30,6 +32,14 @@ type Deleter interface {
 	Delete(key []byte) error
 }
 
+// Iteratee wraps the prefix iteration supported by persistent and memory
+// databases.
+type Iteratee interface {
+	// NewIteratorWithPrefix returns an iterator over the subset of database
+	// content with a particular key prefix, in ascending key order.
+	NewIteratorWithPrefix(prefix []byte) iterator.Iterator
+}
+
 // Database wraps all database operations. All methods are safe for concurrent use.
 type Database interface {
 	Putter
@@ -31,12 +31,15 @@ import (
 	"time"
 
 	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/common/hexutil"
 	"github.com/dexon-foundation/dexon/common/mclock"
 	"github.com/dexon-foundation/dexon/consensus"
 	"github.com/dexon-foundation/dexon/core"
 	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
 	"github.com/dexon-foundation/dexon/eth"
 	"github.com/dexon-foundation/dexon/event"
+	"github.com/dexon-foundation/dexon/internal/ethapi"
 	"github.com/dexon-foundation/dexon/les"
 	"github.com/dexon-foundation/dexon/log"
 	"github.com/dexon-foundation/dexon/p2p"
@@ -66,11 +69,30 @@ type blockChain interface {
 	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
 }
 
+// DexonBackend is the DEXON full node service to monitor. It is an interface
+// so that light clients reporting stats don't have to link the full node.
+type DexonBackend interface {
+	BlockChain() *core.BlockChain
+	TxPool() *core.TxPool
+	Engine() consensus.Engine
+	Downloader() ethapi.Downloader
+	NetVersion() uint64
+	DexVersion() int
+	SuggestPrice(ctx context.Context) (*big.Int, error)
+	IsProposing() bool
+	IsCoreSyncing() bool
+
+	// NotaryStats reports whether the node is in the current and the next
+	// notary set, and the size of the current notary set.
+	NotaryStats() (isNotary, isNextNotary bool, size int, err error)
+}
+
 // Service implements an Ethereum netstats reporting daemon that pushes local
 // chain statistics up to a monitoring server.
 type Service struct {
 	server *p2p.Server        // Peer-to-peer server to retrieve networking infos
 	eth    *eth.Ethereum      // Full Ethereum service if monitoring a full node
+	dex    DexonBackend       // Full DEXON service if monitoring a DEXON full node
 	les    *les.LightEthereum // Light Ethereum service if monitoring a light node
 	engine consensus.Engine   // Consensus engine to retrieve variadic block fields
 
@@ -83,7 +105,7 @@ type Service struct {
 }
 
 // New returns a monitoring service ready for stats reporting.
-func New(url string, ethServ *eth.Ethereum, lesServ *les.LightEthereum) (*Service, error) {
+func New(url string, ethServ *eth.Ethereum, dexServ DexonBackend, lesServ *les.LightEthereum) (*Service, error) {
 	// Parse the netstats connection url
 	re := regexp.MustCompile("([^:@]*)(:([^@]*))?@(.+)")
 	parts := re.FindStringSubmatch(url)
@@ -92,13 +114,17 @@ func New(url string, ethServ *eth.Ethereum, lesServ *les.LightEthereum) (*Servic
 	}
 	// Assemble and return the stats service
 	var engine consensus.Engine
-	if ethServ != nil {
+	switch {
+	case ethServ != nil:
 		engine = ethServ.Engine()
-	} else {
+	case dexServ != nil:
+		engine = dexServ.Engine()
+	default:
 		engine = lesServ.Engine()
 	}
 	return &Service{
 		eth:    ethServ,
+		dex:    dexServ,
 		les:    lesServ,
 		engine: engine,
 		node:   parts[1],
@@ -138,10 +164,14 @@ func (s *Service) loop() {
 	// Subscribe to chain events to execute updates on
 	var blockchain blockChain
 	var txpool txPool
-	if s.eth != nil {
+	switch {
+	case s.eth != nil:
 		blockchain = s.eth.BlockChain()
 		txpool = s.eth.TxPool()
-	} else {
+	case s.dex != nil:
+		blockchain = s.dex.BlockChain()
+		txpool = s.dex.TxPool()
+	default:
 		blockchain = s.les.BlockChain()
 		txpool = s.les.TxPool()
 	}
@@ -262,6 +292,9 @@ func (s *Service) loop() {
 				if err = s.reportPending(conn); err != nil {
 					log.Warn("Post-block transaction stats report failed", "err", err)
 				}
+				if err = s.reportDexon(conn, head); err != nil {
+					log.Warn("Post-block DEXON stats report failed", "err", err)
+				}
 			case <-txCh:
 				if err = s.reportPending(conn); err != nil {
 					log.Warn("Transaction stats report failed", "err", err)
@@ -376,6 +409,9 @@ func (s *Service) login(conn *websocket.Conn) error {
 	if info := infos.Protocols["eth"]; info != nil {
 		network = fmt.Sprintf("%d", info.(*eth.NodeInfo).Network)
 		protocol = fmt.Sprintf("eth/%d", eth.ProtocolVersions[0])
+	} else if s.dex != nil {
+		network = fmt.Sprintf("%d", s.dex.NetVersion())
+		protocol = fmt.Sprintf("dex/%d", s.dex.DexVersion())
 	} else {
 		network = fmt.Sprintf("%d", infos.Protocols["les"].(*les.NodeInfo).Network)
 		protocol = fmt.Sprintf("les/%d", les.ClientProtocolVersions[0])
@@ -426,6 +462,9 @@ func (s *Service) report(conn *websocket.Conn) error {
 	if err := s.reportStats(conn); err != nil {
 		return err
 	}
+	if err := s.reportDexon(conn, nil); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -466,6 +505,18 @@ func (s *Service) reportLatency(conn *websocket.Conn) error {
 	return websocket.JSON.Send(conn, stats)
 }
 
+// fullChain returns the chain of the monitored full node, or nil if monitoring
+// a light node.
+func (s *Service) fullChain() *core.BlockChain {
+	switch {
+	case s.eth != nil:
+		return s.eth.BlockChain()
+	case s.dex != nil:
+		return s.dex.BlockChain()
+	}
+	return nil
+}
+
 // blockStats is the information to report about individual blocks.
 type blockStats struct {
 	Number     *big.Int       `json:"number"`
@@ -527,13 +578,13 @@ func (s *Service) assembleBlockStats(block *types.Block) *blockStats {
 		txs    []txStats
 		uncles []*types.Header
 	)
-	if s.eth != nil {
+	if chain := s.fullChain(); chain != nil {
 		// Full nodes have all needed information available
 		if block == nil {
-			block = s.eth.BlockChain().CurrentBlock()
+			block = chain.CurrentBlock()
 		}
 		header = block.Header()
-		td = s.eth.BlockChain().GetTd(header.Hash(), header.Number.Uint64())
+		td = chain.GetTd(header.Hash(), header.Number.Uint64())
 
 		txs = make([]txStats, len(block.Transactions()))
 		for i, tx := range block.Transactions() {
@@ -581,8 +632,8 @@ func (s *Service) reportHistory(conn *websocket.Conn, list []uint64) error {
 	} else {
 		// No indexes requested, send back the top ones
 		var head int64
-		if s.eth != nil {
-			head = s.eth.BlockChain().CurrentHeader().Number.Int64()
+		if chain := s.fullChain(); chain != nil {
+			head = chain.CurrentHeader().Number.Int64()
 		} else {
 			head = s.les.BlockChain().CurrentHeader().Number.Int64()
 		}
@@ -599,8 +650,8 @@ func (s *Service) reportHistory(conn *websocket.Conn, list []uint64) error {
 	for i, number := range indexes {
 		// Retrieve the next block if it's known to us
 		var block *types.Block
-		if s.eth != nil {
-			block = s.eth.BlockChain().GetBlockByNumber(number)
+		if chain := s.fullChain(); chain != nil {
+			block = chain.GetBlockByNumber(number)
 		} else {
 			if header := s.les.BlockChain().GetHeaderByNumber(number); header != nil {
 				block = types.NewBlockWithHeader(header)
@@ -641,9 +692,12 @@ type pendStats struct {
 func (s *Service) reportPending(conn *websocket.Conn) error {
 	// Retrieve the pending count from the local blockchain
 	var pending int
-	if s.eth != nil {
+	switch {
+	case s.eth != nil:
 		pending, _ = s.eth.TxPool().Stats()
-	} else {
+	case s.dex != nil:
+		pending, _ = s.dex.TxPool().Stats()
+	default:
 		pending = s.les.TxPool().Stats()
 	}
 	// Assemble the transaction stats and send it to the server
@@ -682,7 +736,8 @@ func (s *Service) reportStats(conn *websocket.Conn) error {
 		syncing  bool
 		gasprice int
 	)
-	if s.eth != nil {
+	switch {
+	case s.eth != nil:
 		mining = s.eth.Miner().Mining()
 		hashrate = int(s.eth.Miner().HashRate())
 
@@ -691,7 +746,15 @@ func (s *Service) reportStats(conn *websocket.Conn) error {
 
 		price, _ := s.eth.APIBackend.SuggestPrice(context.Background())
 		gasprice = int(price.Uint64())
-	} else {
+	case s.dex != nil:
+		mining = s.dex.IsProposing()
+
+		sync := s.dex.Downloader().Progress()
+		syncing = s.dex.BlockChain().CurrentHeader().Number.Uint64() >= sync.HighestBlock
+
+		price, _ := s.dex.SuggestPrice(context.Background())
+		gasprice = int(price.Uint64())
+	default:
 		sync := s.les.Downloader().Progress()
 		syncing = s.les.BlockChain().CurrentHeader().Number.Uint64() >= sync.HighestBlock
 	}
@@ -715,3 +778,77 @@ func (s *Service) reportStats(conn *websocket.Conn) error {
 	}
 	return websocket.JSON.Send(conn, report)
 }
+
+// dkgStats is the progress of the DKG of the round being prepared.
+type dkgStats struct {
+	Round      uint64 `json:"round"`
+	ResetCount uint64 `json:"resetCount"`
+	MPKReady   uint64 `json:"mpkReady"`
+	Finalized  uint64 `json:"finalized"`
+	Success    uint64 `json:"success"`
+}
+
+// dexonStats is the information to report about the DEXON consensus of the
+// local node.
+type dexonStats struct {
+	Round        uint64        `json:"round"`
+	Number       *big.Int      `json:"number"`
+	Randomness   hexutil.Bytes `json:"randomness"`
+	IsNotary     bool          `json:"isNotary"`
+	IsNextNotary bool          `json:"isNextNotary"`
+	NotarySize   int           `json:"notarySetSize"`
+	Proposing    bool          `json:"proposing"`
+	CoreSyncing  bool          `json:"coreSyncing"`
+	DKG          *dkgStats     `json:"dkg"`
+}
+
+// reportDexon retrieves the consensus stats of a DEXON full node at the given
+// block and reports them to the stats server. If block is nil, the current head
+// is processed. Nothing is reported when not monitoring a DEXON full node.
+func (s *Service) reportDexon(conn *websocket.Conn, block *types.Block) error {
+	if s.dex == nil {
+		return nil
+	}
+	if block == nil {
+		block = s.dex.BlockChain().CurrentBlock()
+	}
+	stats := &dexonStats{
+		Round:       block.Round(),
+		Number:      block.Number(),
+		Randomness:  block.Randomness(),
+		Proposing:   s.dex.IsProposing(),
+		CoreSyncing: s.dex.IsCoreSyncing(),
+	}
+	// The notary set is unknown before the governance state of the round is
+	// available, report the rest anyway.
+	if isNotary, isNextNotary, size, err := s.dex.NotaryStats(); err != nil {
+		log.Debug("Failed to retrieve notary info", "err", err)
+	} else {
+		stats.IsNotary = isNotary
+		stats.IsNextNotary = isNextNotary
+		stats.NotarySize = size
+	}
+	if statedb, err := s.dex.BlockChain().StateAt(block.Root()); err != nil {
+		log.Debug("Failed to retrieve governance state", "number", block.Number(), "err", err)
+	} else {
+		gs := &vm.GovernanceState{StateDB: statedb}
+		round := gs.DKGRound()
+		stats.DKG = &dkgStats{
+			Round:      round.Uint64(),
+			ResetCount: gs.DKGResetCount(round).Uint64(),
+			MPKReady:   gs.DKGMPKReadysCount().Uint64(),
+			Finalized:  gs.DKGFinalizedsCount().Uint64(),
+			Success:    gs.DKGSuccessesCount().Uint64(),
+		}
+	}
+	// Assemble the DEXON stats and send it to the server
+	log.Trace("Sending DEXON stats to ethstats", "round", stats.Round, "number", stats.Number)
+
+	report := map[string][]interface{}{
+		"emit": {"dexon", map[string]interface{}{
+			"id":    s.node,
+			"stats": stats,
+		}},
+	}
+	return websocket.JSON.Send(conn, report)
+}
@@ -1,17 +1,32 @@
 package indexer
 
 import (
+	"errors"
+	"fmt"
 	"plugin"
 
 	"github.com/dexon-foundation/dexon/core"
 	"github.com/dexon-foundation/dexon/dex/downloader"
 )
 
+var (
+	// ErrNoIndexer is returned if the indexer is enabled without selecting
+	// either a built-in indexer or a plugin.
+	ErrNoIndexer = errors.New("no indexer name or plugin specified")
+
+	// ErrAmbiguousIndexer is returned if both a built-in indexer and a plugin
+	// are selected.
+	ErrAmbiguousIndexer = errors.New("both indexer name and plugin specified")
+)
+
 // Config is data sources related configs struct.
 type Config struct {
 	// Used by dex/backend init flow.
 	Enable bool
 
+	// Name of a built-in indexer registered by Register.
+	Name string
+
 	// Plugin path for building components.
 	Plugin string
 
@@ -27,22 +42,47 @@ type Config struct {
 }
 
 // NewIndexerFromConfig initialize exporter according to given config.
-func NewIndexerFromConfig(bc ReadOnlyBlockChain, c Config) (idx Indexer) {
-	if c.Plugin == "" {
-		// default
-		return
+func NewIndexerFromConfig(bc ReadOnlyBlockChain, c Config) (Indexer, error) {
+	switch {
+	case c.Name != "" && c.Plugin != "":
+		return nil, ErrAmbiguousIndexer
+	case c.Name != "":
+		return newBuiltinIndexer(bc, c)
+	case c.Plugin != "":
+		return newPluginIndexer(bc, c)
 	}
+	return nil, ErrNoIndexer
+}
 
+func newPluginIndexer(bc ReadOnlyBlockChain, c Config) (Indexer, error) {
 	plug, err := plugin.Open(c.Plugin)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to open indexer plugin %s: %v", c.Plugin, err)
 	}
 
 	symbol, err := plug.Lookup(NewIndexerFuncName)
 	if err != nil {
-		panic(err)
+		return nil, fmt.Errorf("failed to look up %s in indexer plugin %s: %v",
+			NewIndexerFuncName, c.Plugin, err)
 	}
 
-	idx = symbol.(NewIndexerFunc)(bc, c)
-	return
+	var fn NewIndexerFunc
+	switch s := symbol.(type) {
+	case NewIndexerFunc:
+		fn = s
+	case *NewIndexerFunc:
+		fn = *s
+	default:
+		return nil, fmt.Errorf("indexer plugin %s: %s has unexpected type %T",
+			c.Plugin, NewIndexerFuncName, symbol)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("indexer plugin %s: %s is nil", c.Plugin, NewIndexerFuncName)
+	}
+
+	idx := fn(bc, c)
+	if idx == nil {
+		return nil, fmt.Errorf("indexer plugin %s returned nil indexer", c.Plugin)
+	}
+	return idx, nil
 }
@@ -0,0 +1,81 @@
+package indexer
+
+import (
+	"testing"
+)
+
+type testIndexer struct {
+	config Config
+}
+
+func (i *testIndexer) Start() error { return nil }
+func (i *testIndexer) Stop() error  { return nil }
+
+func TestNewIndexerFromConfig(t *testing.T) {
+	Register("test", func(bc ReadOnlyBlockChain, c Config) Indexer {
+		return &testIndexer{config: c}
+	})
+	Register("test-nil", func(bc ReadOnlyBlockChain, c Config) Indexer {
+		return nil
+	})
+
+	idx, err := NewIndexerFromConfig(nil, Config{Name: "test", PluginFlags: "flags"})
+	if err != nil {
+		t.Fatalf("failed to create built-in indexer: %v", err)
+	}
+	if ti, ok := idx.(*testIndexer); !ok || ti.config.PluginFlags != "flags" {
+		t.Errorf("built-in indexer mismatch: have %#v", idx)
+	}
+
+	tests := []struct {
+		config Config
+		err    error
+	}{
+		{Config{}, ErrNoIndexer},
+		{Config{Name: "test", Plugin: "test.so"}, ErrAmbiguousIndexer},
+		{Config{Name: "missing"}, nil},
+		{Config{Name: "test-nil"}, nil},
+		{Config{Plugin: "/nonexistent/indexer.so"}, nil},
+	}
+	for i, tt := range tests {
+		idx, err := NewIndexerFromConfig(nil, tt.config)
+		if err == nil {
+			t.Errorf("test %d: expected error, got indexer %v", i, idx)
+			continue
+		}
+		if tt.err != nil && err != tt.err {
+			t.Errorf("test %d: error mismatch: have %v, want %v", i, err, tt.err)
+		}
+	}
+}
+
+func TestRegisterDuplicate(t *testing.T) {
+	fn := func(bc ReadOnlyBlockChain, c Config) Indexer { return nil }
+	Register("test-dup", fn)
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate registration did not panic")
+		}
+	}()
+	Register("test-dup", fn)
+}
+
+func TestNames(t *testing.T) {
+	fn := func(bc ReadOnlyBlockChain, c Config) Indexer { return nil }
+	Register("test-names-b", fn)
+	Register("test-names-a", fn)
+
+	names := Names()
+	a, b := -1, -1
+	for i, name := range names {
+		switch name {
+		case "test-names-a":
+			a = i
+		case "test-names-b":
+			b = i
+		}
+	}
+	if a < 0 || b < 0 || a > b {
+		t.Errorf("registered names mismatch: %v", names)
+	}
+}
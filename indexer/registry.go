@@ -0,0 +1,66 @@
+package indexer
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	registryLock sync.RWMutex
+	registry     = make(map[string]NewIndexerFunc)
+)
+
+// Register makes an indexer linked into the binary available by name, so
+// it can be selected through Config.Name without building a Go plugin.
+// It is meant to be called from init functions and panics if the name is
+// empty, the constructor is nil or the name is already registered.
+func Register(name string, fn NewIndexerFunc) {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if name == "" {
+		panic("indexer: register with empty name")
+	}
+	if fn == nil {
+		panic("indexer: register nil constructor for " + name)
+	}
+	if _, dup := registry[name]; dup {
+		panic("indexer: register called twice for " + name)
+	}
+	registry[name] = fn
+}
+
+// Lookup returns the constructor of a registered built-in indexer.
+func Lookup(name string) (NewIndexerFunc, bool) {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	fn, ok := registry[name]
+	return fn, ok
+}
+
+// Names returns the sorted names of all registered built-in indexers.
+func Names() []string {
+	registryLock.RLock()
+	defer registryLock.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newBuiltinIndexer(bc ReadOnlyBlockChain, c Config) (Indexer, error) {
+	fn, ok := Lookup(c.Name)
+	if !ok {
+		return nil, fmt.Errorf("unknown indexer %q, available: %v", c.Name, Names())
+	}
+	idx := fn(bc, c)
+	if idx == nil {
+		return nil, fmt.Errorf("indexer %s returned nil indexer", c.Name)
+	}
+	return idx, nil
+}
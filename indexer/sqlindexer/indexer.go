@@ -0,0 +1,211 @@
+// Package sqlindexer implements a reference indexer which exports blocks,
+// transactions, receipts and logs, including the DEXON specific round,
+// randomness and reward fields, into an SQL database.
+//
+// The indexer is registered as the built-in indexer "sql". Its plugin flags
+// have the form "<driver>:<dsn>", for example "sqlite3:/data/index.db". The
+// database/sql driver has to be linked into the binary. The embedded SQLite
+// driver "sqlite3" is bundled when building with the "sqlite" tag, which
+// requires cgo.
+package sqlindexer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/indexer"
+	"github.com/dexon-foundation/dexon/log"
+)
+
+// Name is the name the indexer is registered with.
+const Name = "sql"
+
+const (
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 64
+
+	// logsEventChanSize is the size of channel listening to logs events.
+	logsEventChanSize = 64
+)
+
+var errInvalidFlags = errors.New(`invalid sql indexer flags, expected "<driver>:<dsn>"`)
+
+func init() {
+	indexer.Register(Name, func(bc indexer.ReadOnlyBlockChain, c indexer.Config) indexer.Indexer {
+		return New(bc, c)
+	})
+}
+
+// Indexer writes the canonical chain into an SQL database. On start it
+// resumes from the highest indexed block, backfills missing blocks and then
+// follows the chain events.
+type Indexer struct {
+	bc     indexer.ReadOnlyBlockChain
+	config indexer.Config
+
+	store store
+	next  uint64 // Number of the next block to index
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// New creates an SQL indexer. The database is opened when the indexer starts.
+func New(bc indexer.ReadOnlyBlockChain, c indexer.Config) *Indexer {
+	return &Indexer{
+		bc:     bc,
+		config: c,
+		quit:   make(chan struct{}),
+	}
+}
+
+// parseFlags splits the plugin flags into driver name and data source name.
+func parseFlags(flags string) (driver, dsn string, err error) {
+	parts := strings.SplitN(flags, ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errInvalidFlags
+	}
+	return parts[0], parts[1], nil
+}
+
+// Start implements indexer.Indexer.
+func (i *Indexer) Start() error {
+	if i.store == nil {
+		driver, dsn, err := parseFlags(i.config.PluginFlags)
+		if err != nil {
+			return err
+		}
+		s, err := newSQLStore(driver, dsn)
+		if err != nil {
+			return fmt.Errorf("failed to open %s database: %v", driver, err)
+		}
+		i.store = s
+	}
+
+	head, ok, err := i.store.Head()
+	if err != nil {
+		return err
+	}
+	if ok {
+		i.next = head + 1
+	}
+	gaps, err := i.store.Gaps()
+	if err != nil {
+		return err
+	}
+	log.Info("Starting SQL indexer", "next", i.next, "gaps", len(gaps))
+
+	// Subscribe before catching up, so no block is missed in between.
+	chainCh := make(chan core.ChainEvent, chainEventChanSize)
+	chainSub := i.bc.SubscribeChainEvent(chainCh)
+	logsCh := make(chan []*types.Log, logsEventChanSize)
+	logsSub := i.bc.SubscribeLogsEvent(logsCh)
+
+	i.wg.Add(1)
+	go func() {
+		defer i.wg.Done()
+		defer chainSub.Unsubscribe()
+		defer logsSub.Unsubscribe()
+		i.loop(gaps, chainCh, logsCh, chainSub.Err())
+	}()
+	return nil
+}
+
+// Stop implements indexer.Indexer.
+func (i *Indexer) Stop() error {
+	close(i.quit)
+	i.wg.Wait()
+	if i.store == nil {
+		return nil
+	}
+	return i.store.Close()
+}
+
+func (i *Indexer) loop(gaps []gap, chainCh <-chan core.ChainEvent,
+	logsCh <-chan []*types.Log, errCh <-chan error) {
+	for _, g := range gaps {
+		log.Info("Backfilling SQL indexer gap", "from", g.from, "to", g.to)
+		for number := g.from; number <= g.to; number++ {
+			if i.stopped() || !i.indexNumber(number) {
+				break
+			}
+		}
+	}
+	i.catchUp(i.bc.CurrentBlock().NumberU64())
+
+	for {
+		select {
+		case ev := <-chainCh:
+			if number := ev.Block.NumberU64(); number < i.next {
+				if err := i.index(ev.Block); err != nil {
+					log.Error("Failed to reindex block", "number", number, "err", err)
+				}
+			} else {
+				i.catchUp(number)
+			}
+		case logs := <-logsCh:
+			if err := i.store.WriteLogs(logs); err != nil {
+				log.Error("Failed to index logs", "err", err)
+			}
+		case <-errCh:
+			return
+		case <-i.quit:
+			return
+		}
+	}
+}
+
+func (i *Indexer) stopped() bool {
+	select {
+	case <-i.quit:
+		return true
+	default:
+		return false
+	}
+}
+
+// catchUp indexes the canonical blocks from the next expected number up to
+// the given one. On failure it stops and retries on the next chain event.
+func (i *Indexer) catchUp(to uint64) {
+	for ; i.next <= to; i.next++ {
+		if i.stopped() || !i.indexNumber(i.next) {
+			return
+		}
+	}
+}
+
+func (i *Indexer) indexNumber(number uint64) bool {
+	block := i.bc.GetBlockByNumber(number)
+	if block == nil {
+		log.Warn("Block to index not found", "number", number)
+		return false
+	}
+	if err := i.index(block); err != nil {
+		log.Error("Failed to index block", "number", number, "err", err)
+		return false
+	}
+	return true
+}
+
+func (i *Indexer) index(block *types.Block) error {
+	signer := types.MakeSigner(i.bc.Config(), block.Number())
+	senders := make([]common.Address, len(block.Transactions()))
+	for idx, tx := range block.Transactions() {
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("failed to recover sender of %x: %v", tx.Hash(), err)
+		}
+		senders[idx] = sender
+	}
+	receipts := i.bc.GetReceiptsByHash(block.Hash())
+	if len(receipts) != len(block.Transactions()) {
+		return fmt.Errorf("receipt count mismatch: have %d, want %d",
+			len(receipts), len(block.Transactions()))
+	}
+	return i.store.WriteBlock(block, senders, receipts)
+}
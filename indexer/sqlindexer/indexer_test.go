@@ -0,0 +1,219 @@
+package sqlindexer
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/consensus/ethash"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/ethdb"
+	"github.com/dexon-foundation/dexon/indexer"
+	"github.com/dexon-foundation/dexon/params"
+)
+
+// memStore is an in-memory store used to test the indexing flow.
+type memStore struct {
+	lock     sync.Mutex
+	blocks   map[uint64]*types.Block
+	senders  map[common.Hash]common.Address
+	receipts map[common.Hash]*types.Receipt
+	closed   bool
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		blocks:   make(map[uint64]*types.Block),
+		senders:  make(map[common.Hash]common.Address),
+		receipts: make(map[common.Hash]*types.Receipt),
+	}
+}
+
+func (s *memStore) numbers() []uint64 {
+	numbers := make([]uint64, 0, len(s.blocks))
+	for number := range s.blocks {
+		numbers = append(numbers, number)
+	}
+	sort.Slice(numbers, func(i, j int) bool { return numbers[i] < numbers[j] })
+	return numbers
+}
+
+func (s *memStore) Head() (uint64, bool, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	numbers := s.numbers()
+	if len(numbers) == 0 {
+		return 0, false, nil
+	}
+	return numbers[len(numbers)-1], true, nil
+}
+
+func (s *memStore) Gaps() ([]gap, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var (
+		gaps []gap
+		next uint64
+	)
+	for _, number := range s.numbers() {
+		if number > next {
+			gaps = append(gaps, gap{next, number - 1})
+		}
+		next = number + 1
+	}
+	return gaps, nil
+}
+
+func (s *memStore) WriteBlock(block *types.Block, senders []common.Address, receipts types.Receipts) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.blocks[block.NumberU64()] = block
+	for i, tx := range block.Transactions() {
+		s.senders[tx.Hash()] = senders[i]
+		s.receipts[tx.Hash()] = receipts[i]
+	}
+	return nil
+}
+
+func (s *memStore) WriteLogs(logs []*types.Log) error {
+	return nil
+}
+
+func (s *memStore) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.closed = true
+	return nil
+}
+
+func (s *memStore) waitHead(t *testing.T, head uint64) {
+	for i := 0; i < 100; i++ {
+		s.lock.Lock()
+		numbers := s.numbers()
+		s.lock.Unlock()
+		if uint64(len(numbers)) == head+1 && numbers[len(numbers)-1] == head {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("indexer did not reach block %d", head)
+}
+
+func TestIndexerResumeAndBackfill(t *testing.T) {
+	var (
+		db      = ethdb.NewMemDatabase()
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blocks, _ := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, 16, func(i int, gen *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(address), common.Address{0x01},
+			big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign tx: %v", err)
+		}
+		gen.AddTx(tx)
+	})
+	chain, err := core.NewBlockChain(db, nil, gspec.Config, ethash.NewFaker(), vm.Config{}, nil)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks[:10]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+
+	// Pretend a previous run indexed some blocks, but missed 3 and 4.
+	store := newMemStore()
+	for _, number := range []uint64{0, 1, 2, 5} {
+		store.blocks[number] = chain.GetBlockByNumber(number)
+	}
+
+	idx := New(indexer.NewROBlockChain(chain), indexer.Config{})
+	idx.store = store
+	if err := idx.Start(); err != nil {
+		t.Fatalf("failed to start indexer: %v", err)
+	}
+	store.waitHead(t, 10)
+
+	// New blocks are followed through chain events.
+	if _, err := chain.InsertChain(blocks[10:]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	store.waitHead(t, 16)
+
+	if err := idx.Stop(); err != nil {
+		t.Fatalf("failed to stop indexer: %v", err)
+	}
+	if !store.closed {
+		t.Error("store not closed")
+	}
+	for number := uint64(3); number <= 16; number++ {
+		if number == 5 {
+			continue
+		}
+		block := store.blocks[number]
+		if block.Hash() != chain.GetBlockByNumber(number).Hash() {
+			t.Errorf("block %d hash mismatch", number)
+		}
+		for _, tx := range block.Transactions() {
+			if sender := store.senders[tx.Hash()]; sender != address {
+				t.Errorf("block %d tx %x sender mismatch: have %x, want %x",
+					number, tx.Hash(), sender, address)
+			}
+			if receipt := store.receipts[tx.Hash()]; receipt == nil || receipt.TxHash != tx.Hash() {
+				t.Errorf("block %d tx %x receipt mismatch: have %v", number, tx.Hash(), receipt)
+			}
+		}
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		flags       string
+		driver, dsn string
+		err         error
+	}{
+		{"sqlite3:/data/index.db", "sqlite3", "/data/index.db", nil},
+		{"sqlite3:file:index.db?cache=shared", "sqlite3", "file:index.db?cache=shared", nil},
+		{"", "", "", errInvalidFlags},
+		{"sqlite3", "", "", errInvalidFlags},
+		{":index.db", "", "", errInvalidFlags},
+		{"sqlite3:", "", "", errInvalidFlags},
+	}
+	for i, tt := range tests {
+		driver, dsn, err := parseFlags(tt.flags)
+		if driver != tt.driver || dsn != tt.dsn || err != tt.err {
+			t.Errorf("test %d: have (%q, %q, %v), want (%q, %q, %v)",
+				i, driver, dsn, err, tt.driver, tt.dsn, tt.err)
+		}
+	}
+}
+
+func TestStartUnknownDriver(t *testing.T) {
+	idx, err := indexer.NewIndexerFromConfig(nil, indexer.Config{
+		Name:        Name,
+		PluginFlags: "no-such-driver:index.db",
+	})
+	if err != nil {
+		t.Fatalf("failed to create indexer: %v", err)
+	}
+	if err := idx.Start(); err == nil {
+		t.Error("expected error for unknown driver")
+	}
+}
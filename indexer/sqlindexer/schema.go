@@ -0,0 +1,61 @@
+package sqlindexer
+
+// schema creates the tables used by the indexer. It only relies on types and
+// statements understood by SQLite, so any SQLite driver can be used, and
+// large integers (balances, prices, rewards) are stored as decimal text.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS blocks (
+		number      INTEGER PRIMARY KEY,
+		hash        BLOB NOT NULL UNIQUE,
+		parent_hash BLOB NOT NULL,
+		coinbase    BLOB NOT NULL,
+		timestamp   INTEGER NOT NULL,
+		gas_limit   INTEGER NOT NULL,
+		gas_used    INTEGER NOT NULL,
+		tx_count    INTEGER NOT NULL,
+		round       INTEGER NOT NULL,
+		randomness  BLOB,
+		reward      TEXT NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS transactions (
+		hash         BLOB PRIMARY KEY,
+		block_number INTEGER NOT NULL,
+		block_hash   BLOB NOT NULL,
+		tx_index     INTEGER NOT NULL,
+		sender       BLOB NOT NULL,
+		recipient    BLOB,
+		nonce        INTEGER NOT NULL,
+		value        TEXT NOT NULL,
+		gas          INTEGER NOT NULL,
+		gas_price    TEXT NOT NULL,
+		input        BLOB
+	)`,
+	`CREATE INDEX IF NOT EXISTS transactions_block_number ON transactions (block_number)`,
+	`CREATE INDEX IF NOT EXISTS transactions_sender ON transactions (sender)`,
+	`CREATE INDEX IF NOT EXISTS transactions_recipient ON transactions (recipient)`,
+	`CREATE TABLE IF NOT EXISTS receipts (
+		tx_hash             BLOB PRIMARY KEY,
+		block_number        INTEGER NOT NULL,
+		status              INTEGER NOT NULL,
+		cumulative_gas_used INTEGER NOT NULL,
+		gas_used            INTEGER NOT NULL,
+		contract_address    BLOB
+	)`,
+	`CREATE INDEX IF NOT EXISTS receipts_block_number ON receipts (block_number)`,
+	`CREATE TABLE IF NOT EXISTS logs (
+		block_number INTEGER NOT NULL,
+		log_index    INTEGER NOT NULL,
+		block_hash   BLOB NOT NULL,
+		tx_hash      BLOB NOT NULL,
+		tx_index     INTEGER NOT NULL,
+		address      BLOB NOT NULL,
+		topic0       BLOB,
+		topic1       BLOB,
+		topic2       BLOB,
+		topic3       BLOB,
+		data         BLOB,
+		PRIMARY KEY (block_number, log_index)
+	)`,
+	`CREATE INDEX IF NOT EXISTS logs_address ON logs (address)`,
+	`CREATE INDEX IF NOT EXISTS logs_topic0 ON logs (topic0)`,
+}
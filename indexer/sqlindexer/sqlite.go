@@ -0,0 +1,5 @@
+// +build sqlite
+
+package sqlindexer
+
+import _ "github.com/mattn/go-sqlite3" // Embedded SQLite driver "sqlite3"
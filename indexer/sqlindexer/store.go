@@ -0,0 +1,215 @@
+package sqlindexer
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core/types"
+)
+
+// gap is an inclusive range of block numbers missing from the store.
+type gap struct {
+	from, to uint64
+}
+
+// store persists indexed chain data.
+type store interface {
+	// Head returns the highest indexed block number, ok is false if no block
+	// has been indexed yet.
+	Head() (number uint64, ok bool, err error)
+
+	// Gaps returns the ranges of block numbers below the head which are
+	// missing from the store.
+	Gaps() ([]gap, error)
+
+	// WriteBlock atomically replaces everything stored for the block's
+	// number with the given block, its transaction senders and receipts.
+	WriteBlock(block *types.Block, senders []common.Address, receipts types.Receipts) error
+
+	// WriteLogs replaces the given logs.
+	WriteLogs(logs []*types.Log) error
+
+	// Close releases the underlying resources.
+	Close() error
+}
+
+// sqlStore is a store backed by database/sql.
+type sqlStore struct {
+	db *sql.DB
+}
+
+// newSQLStore opens the database and creates the schema if needed. The driver
+// must have been linked into the binary.
+func newSQLStore(driver, dsn string) (*sqlStore, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to create schema: %v", err)
+		}
+	}
+	return &sqlStore{db: db}, nil
+}
+
+func (s *sqlStore) Head() (uint64, bool, error) {
+	var head sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MAX(number) FROM blocks`).Scan(&head); err != nil {
+		return 0, false, err
+	}
+	return uint64(head.Int64), head.Valid, nil
+}
+
+func (s *sqlStore) Gaps() ([]gap, error) {
+	var gaps []gap
+
+	var first sql.NullInt64
+	if err := s.db.QueryRow(`SELECT MIN(number) FROM blocks`).Scan(&first); err != nil {
+		return nil, err
+	}
+	if !first.Valid {
+		return nil, nil
+	}
+	if first.Int64 > 0 {
+		gaps = append(gaps, gap{0, uint64(first.Int64) - 1})
+	}
+
+	rows, err := s.db.Query(`
+		SELECT b.number + 1,
+			(SELECT MIN(n.number) FROM blocks n WHERE n.number > b.number) - 1
+		FROM blocks b
+		WHERE b.number < (SELECT MAX(number) FROM blocks)
+			AND NOT EXISTS (SELECT 1 FROM blocks n WHERE n.number = b.number + 1)
+		ORDER BY b.number`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var g gap
+		if err := rows.Scan(&g.from, &g.to); err != nil {
+			return nil, err
+		}
+		gaps = append(gaps, g)
+	}
+	return gaps, rows.Err()
+}
+
+func (s *sqlStore) WriteBlock(block *types.Block, senders []common.Address, receipts types.Receipts) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := writeBlock(tx, block, senders, receipts); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func writeBlock(tx *sql.Tx, block *types.Block, senders []common.Address, receipts types.Receipts) error {
+	number := block.NumberU64()
+	for _, stmt := range []string{
+		`DELETE FROM blocks WHERE number = ?`,
+		`DELETE FROM transactions WHERE block_number = ?`,
+		`DELETE FROM receipts WHERE block_number = ?`,
+		`DELETE FROM logs WHERE block_number = ?`,
+	} {
+		if _, err := tx.Exec(stmt, number); err != nil {
+			return err
+		}
+	}
+
+	_, err := tx.Exec(`INSERT INTO blocks (number, hash, parent_hash, coinbase,
+		timestamp, gas_limit, gas_used, tx_count, round, randomness, reward)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		number, block.Hash().Bytes(), block.ParentHash().Bytes(),
+		block.Coinbase().Bytes(), block.Time(), block.GasLimit(), block.GasUsed(),
+		len(block.Transactions()), block.Round(), block.Randomness(),
+		block.Reward().String())
+	if err != nil {
+		return fmt.Errorf("failed to insert block %d: %v", number, err)
+	}
+
+	for i, t := range block.Transactions() {
+		var recipient []byte
+		if to := t.To(); to != nil {
+			recipient = to.Bytes()
+		}
+		_, err := tx.Exec(`INSERT INTO transactions (hash, block_number,
+			block_hash, tx_index, sender, recipient, nonce, value, gas,
+			gas_price, input) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			t.Hash().Bytes(), number, block.Hash().Bytes(), i,
+			senders[i].Bytes(), recipient, t.Nonce(), t.Value().String(),
+			t.Gas(), t.GasPrice().String(), t.Data())
+		if err != nil {
+			return fmt.Errorf("failed to insert transaction %x: %v", t.Hash(), err)
+		}
+	}
+
+	for _, receipt := range receipts {
+		var contract []byte
+		if receipt.ContractAddress != (common.Address{}) {
+			contract = receipt.ContractAddress.Bytes()
+		}
+		_, err := tx.Exec(`INSERT INTO receipts (tx_hash, block_number, status,
+			cumulative_gas_used, gas_used, contract_address)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			receipt.TxHash.Bytes(), number, receipt.Status,
+			receipt.CumulativeGasUsed, receipt.GasUsed, contract)
+		if err != nil {
+			return fmt.Errorf("failed to insert receipt %x: %v", receipt.TxHash, err)
+		}
+		if err := insertLogs(tx, receipt.Logs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) WriteLogs(logs []*types.Log) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, l := range logs {
+		_, err := tx.Exec(`DELETE FROM logs WHERE block_number = ? AND log_index = ?`,
+			l.BlockNumber, l.Index)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := insertLogs(tx, logs); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertLogs(tx *sql.Tx, logs []*types.Log) error {
+	for _, l := range logs {
+		var topics [4][]byte
+		for i := 0; i < len(topics) && i < len(l.Topics); i++ {
+			topics[i] = l.Topics[i].Bytes()
+		}
+		_, err := tx.Exec(`INSERT INTO logs (block_number, log_index,
+			block_hash, tx_hash, tx_index, address, topic0, topic1, topic2,
+			topic3, data) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			l.BlockNumber, l.Index, l.BlockHash.Bytes(), l.TxHash.Bytes(),
+			l.TxIndex, l.Address.Bytes(), topics[0], topics[1], topics[2],
+			topics[3], l.Data)
+		if err != nil {
+			return fmt.Errorf("failed to insert log %d of block %d: %v",
+				l.Index, l.BlockNumber, err)
+		}
+	}
+	return nil
+}
+
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,187 @@
+package sqlindexer
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/consensus/ethash"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/ethdb"
+	"github.com/dexon-foundation/dexon/params"
+	_ "github.com/mattn/go-sqlite3" // The tests use the embedded SQLite driver
+)
+
+// newTestSQLStore opens an SQLite store in a temporary directory.
+func newTestSQLStore(t *testing.T) (*sqlStore, string, func()) {
+	dir, err := ioutil.TempDir("", "sqlindexer-test")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	dsn := filepath.Join(dir, "index.db")
+	s, err := newSQLStore("sqlite3", dsn)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("failed to open store: %v", err)
+	}
+	return s, dsn, func() {
+		s.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// generateBlocks generates blocks each with a transfer from the returned
+// address.
+func generateBlocks(t *testing.T, n int) ([]*types.Block, []types.Receipts, common.Address) {
+	var (
+		db      = ethdb.NewMemDatabase()
+		key, _  = crypto.GenerateKey()
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		gspec   = &core.Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  core.GenesisAlloc{address: {Balance: big.NewInt(1000000000)}},
+		}
+		genesis = gspec.MustCommit(db)
+		signer  = types.NewEIP155Signer(gspec.Config.ChainID)
+	)
+	blocks, receipts := core.GenerateChain(gspec.Config, genesis, ethash.NewFaker(), db, n, func(i int, gen *core.BlockGen) {
+		tx, err := types.SignTx(types.NewTransaction(gen.TxNonce(address), common.Address{0x01},
+			big.NewInt(1000), params.TxGas, nil, nil), signer, key)
+		if err != nil {
+			t.Fatalf("failed to sign tx: %v", err)
+		}
+		gen.AddTx(tx)
+	})
+	return blocks, receipts, address
+}
+
+func countRows(t *testing.T, s *sqlStore, query string, args ...interface{}) int {
+	var count int
+	if err := s.db.QueryRow(query, args...).Scan(&count); err != nil {
+		t.Fatalf("failed to query %q: %v", query, err)
+	}
+	return count
+}
+
+func TestSQLStoreHeadAndGaps(t *testing.T) {
+	s, _, cleanup := newTestSQLStore(t)
+	defer cleanup()
+
+	if _, ok, err := s.Head(); err != nil || ok {
+		t.Fatalf("empty store head mismatch: ok %v, err %v", ok, err)
+	}
+	if gaps, err := s.Gaps(); err != nil || len(gaps) != 0 {
+		t.Fatalf("empty store gaps mismatch: %v, err %v", gaps, err)
+	}
+
+	blocks, receipts, address := generateBlocks(t, 10)
+	for _, i := range []int{1, 2, 5, 6, 9} {
+		if err := s.WriteBlock(blocks[i], []common.Address{address}, receipts[i]); err != nil {
+			t.Fatalf("failed to write block %d: %v", blocks[i].NumberU64(), err)
+		}
+	}
+	head, ok, err := s.Head()
+	if err != nil || !ok || head != 10 {
+		t.Errorf("head mismatch: have %d (ok %v, err %v), want 10", head, ok, err)
+	}
+	gaps, err := s.Gaps()
+	if err != nil {
+		t.Fatalf("failed to get gaps: %v", err)
+	}
+	if want := []gap{{0, 1}, {4, 5}, {8, 9}}; !reflect.DeepEqual(gaps, want) {
+		t.Errorf("gaps mismatch: have %v, want %v", gaps, want)
+	}
+}
+
+func TestSQLStoreWrite(t *testing.T) {
+	s, _, cleanup := newTestSQLStore(t)
+	defer cleanup()
+
+	blocks, receipts, address := generateBlocks(t, 2)
+	block, tx := blocks[0], blocks[0].Transactions()[0]
+
+	// Writing a block again replaces it.
+	for i := 0; i < 2; i++ {
+		if err := s.WriteBlock(block, []common.Address{address}, receipts[0]); err != nil {
+			t.Fatalf("failed to write block: %v", err)
+		}
+	}
+	if n := countRows(t, s, `SELECT COUNT(*) FROM blocks`); n != 1 {
+		t.Errorf("block count mismatch: have %d, want 1", n)
+	}
+
+	var (
+		hash, sender, recipient []byte
+		value                   string
+	)
+	err := s.db.QueryRow(`SELECT hash, sender, recipient, value FROM transactions
+		WHERE block_number = ?`, block.NumberU64()).Scan(&hash, &sender, &recipient, &value)
+	if err != nil {
+		t.Fatalf("failed to query transaction: %v", err)
+	}
+	if common.BytesToHash(hash) != tx.Hash() || common.BytesToAddress(sender) != address ||
+		common.BytesToAddress(recipient) != *tx.To() || value != tx.Value().String() {
+		t.Errorf("transaction mismatch: hash %x, sender %x, recipient %x, value %s",
+			hash, sender, recipient, value)
+	}
+	var status uint64
+	err = s.db.QueryRow(`SELECT status FROM receipts WHERE tx_hash = ?`, tx.Hash().Bytes()).Scan(&status)
+	if err != nil || status != receipts[0][0].Status {
+		t.Errorf("receipt status mismatch: have %d, err %v", status, err)
+	}
+
+	// Logs are replaced by block number and index.
+	logs := []*types.Log{
+		{Address: common.Address{0x02}, Topics: []common.Hash{{0x03}}, BlockNumber: 1, Index: 0},
+		{Address: common.Address{0x02}, BlockNumber: 1, Index: 1},
+	}
+	for i := 0; i < 2; i++ {
+		if err := s.WriteLogs(logs); err != nil {
+			t.Fatalf("failed to write logs: %v", err)
+		}
+	}
+	if n := countRows(t, s, `SELECT COUNT(*) FROM logs WHERE block_number = 1`); n != 2 {
+		t.Errorf("log count mismatch: have %d, want 2", n)
+	}
+	if n := countRows(t, s, `SELECT COUNT(*) FROM logs WHERE topic0 = ?`, common.Hash{0x03}.Bytes()); n != 1 {
+		t.Errorf("log topic count mismatch: have %d, want 1", n)
+	}
+}
+
+func TestSQLStoreResume(t *testing.T) {
+	s, dsn, cleanup := newTestSQLStore(t)
+	defer cleanup()
+
+	blocks, receipts, address := generateBlocks(t, 3)
+	for _, i := range []int{0, 2} {
+		if err := s.WriteBlock(blocks[i], []common.Address{address}, receipts[i]); err != nil {
+			t.Fatalf("failed to write block: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("failed to close store: %v", err)
+	}
+
+	// The reopened store continues from what was written.
+	s, err := newSQLStore("sqlite3", dsn)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	defer s.Close()
+	if head, ok, err := s.Head(); err != nil || !ok || head != 3 {
+		t.Errorf("head mismatch: have %d (ok %v, err %v), want 3", head, ok, err)
+	}
+	gaps, err := s.Gaps()
+	if err != nil {
+		t.Fatalf("failed to get gaps: %v", err)
+	}
+	if want := []gap{{0, 0}, {2, 2}}; !reflect.DeepEqual(gaps, want) {
+		t.Errorf("gaps mismatch: have %v, want %v", gaps, want)
+	}
+}
@@ -22,9 +22,12 @@ var Modules = map[string]string{
 	"admin":      Admin_JS,
 	"chequebook": Chequebook_JS,
 	"clique":     Clique_JS,
+	"dex":        Dex_JS,
+	"dexcon":     Dexcon_JS,
 	"ethash":     Ethash_JS,
 	"debug":      Debug_JS,
 	"eth":        Eth_JS,
+	"governance": Governance_JS,
 	"miner":      Miner_JS,
 	"net":        Net_JS,
 	"personal":   Personal_JS,
@@ -111,6 +114,74 @@ web3._extend({
 });
 `
 
+const Dex_JS = `
+web3._extend({
+	property: 'dex',
+	methods: [
+		new web3._extend.Method({
+			name: 'getTransactionStatus',
+			call: 'dex_getTransactionStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRound',
+			call: 'dex_getRound',
+			params: 1,
+			inputFormatter: [web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockByRound',
+			call: 'dex_getBlockByRound',
+			params: 2,
+			inputFormatter: [web3._extend.utils.toHex, function(val) { return !!val; }]
+		}),
+	]
+});
+`
+
+const Dexcon_JS = `
+web3._extend({
+	property: 'dexcon',
+	methods: [
+		new web3._extend.Method({
+			name: 'getBlockReward',
+			call: 'dexcon_getBlockReward',
+			params: 1,
+			inputFormatter: [web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getRewardSchedule',
+			call: 'dexcon_getRewardSchedule',
+			params: 2,
+			inputFormatter: [web3._extend.utils.toHex, web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'inExtendedRound',
+			call: 'dexcon_inExtendedRound',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getDexconMeta',
+			call: 'dexcon_getDexconMeta',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'getDexconMetaByHash',
+			call: 'dexcon_getDexconMetaByHash',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRandomness',
+			call: 'dexcon_getRandomness',
+			params: 1,
+			inputFormatter: [null]
+		}),
+	]
+});
+`
+
 const Ethash_JS = `
 web3._extend({
 	property: 'ethash',
@@ -538,6 +609,37 @@ web3._extend({
 });
 `
 
+const Governance_JS = `
+web3._extend({
+	property: 'governance',
+	methods: [
+		new web3._extend.Method({
+			name: 'getEvents',
+			call: 'governance_getEvents',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getNotarySet',
+			call: 'governance_getNotarySet',
+			params: 1,
+			inputFormatter: [web3._extend.utils.toHex]
+		}),
+		new web3._extend.Method({
+			name: 'getDKGResult',
+			call: 'governance_getDKGResult',
+			params: 1,
+			inputFormatter: [web3._extend.utils.toHex]
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'eventNames',
+			getter: 'governance_eventNames'
+		}),
+	]
+});
+`
+
 const Miner_JS = `
 web3._extend({
 	property: 'miner',
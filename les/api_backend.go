@@ -18,6 +18,7 @@ package les
 
 import (
 	"context"
+	"errors"
 	"math/big"
 
 	"github.com/dexon-foundation/dexon/accounts"
@@ -38,6 +39,10 @@ import (
 	"github.com/dexon-foundation/dexon/rpc"
 )
 
+// errRoundStartUnsupported is returned for the round start block number alias,
+// which can not be resolved without the heights of rounds.
+var errRoundStartUnsupported = errors.New("round start block number not supported by light client")
+
 type LesApiBackend struct {
 	eth *LightEthereum
 	gpo *gasprice.Oracle
@@ -60,6 +65,10 @@ func (b *LesApiBackend) HeaderByNumber(ctx context.Context, blockNr rpc.BlockNum
 	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
 		return b.eth.blockchain.CurrentHeader(), nil
 	}
+	// Light clients do not track the heights of rounds.
+	if _, ok := blockNr.RoundStart(); ok {
+		return nil, errRoundStartUnsupported
+	}
 	return b.eth.blockchain.GetHeaderByNumberOdr(ctx, uint64(blockNr))
 }
 
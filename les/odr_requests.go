@@ -68,6 +68,8 @@ func LesRequest(req light.OdrRequest) LesOdrRequest {
 		return (*ChtRequest)(r)
 	case *light.BloomRequest:
 		return (*BloomRequest)(r)
+	case *light.GovStateRequest:
+		return (*GovStateRequest)(r)
 	default:
 		return nil
 	}
@@ -557,6 +559,51 @@ func (r *BloomRequest) Validate(db ethdb.Database, msg *Msg) error {
 	return nil
 }
 
+// GovStateRequest is the ODR request type for governance states by block hash
+type GovStateRequest light.GovStateRequest
+
+// GetCost returns the cost of the given ODR request according to the serving
+// peer's cost table (implementation of LesOdrRequest)
+func (r *GovStateRequest) GetCost(peer *peer) uint64 {
+	return peer.GetRequestCost(GetGovStateMsg, 1)
+}
+
+// CanSend tells if a certain peer is suitable for serving the given request
+func (r *GovStateRequest) CanSend(peer *peer) bool {
+	if peer.version < lpv3 {
+		return false
+	}
+	return peer.HasBlock(r.Header.Hash(), r.Header.Number.Uint64(), false)
+}
+
+// Request sends an ODR request to the LES network (implementation of LesOdrRequest)
+func (r *GovStateRequest) Request(reqID uint64, peer *peer) error {
+	peer.Log().Debug("Requesting governance state", "number", r.Header.Number, "hash", r.Header.Hash())
+	return peer.RequestGovStates(reqID, r.GetCost(peer), []common.Hash{r.Header.Hash()})
+}
+
+// Valid processes an ODR request reply message from the LES network
+// returns true and stores results in memory if the message was a valid reply
+// to the request (implementation of LesOdrRequest)
+func (r *GovStateRequest) Validate(db ethdb.Database, msg *Msg) error {
+	log.Debug("Validating governance state", "number", r.Header.Number, "hash", r.Header.Hash())
+
+	// Ensure we have a correct message with a single governance state
+	if msg.MsgType != MsgGovStates {
+		return errInvalidMessageType
+	}
+	states := msg.Obj.([]*types.GovState)
+	if len(states) != 1 {
+		return errInvalidEntryCount
+	}
+	// Verify the proof against the state root of the requested header
+	if err := light.VerifyGovState(r.Header, states[0]); err != nil {
+		return err
+	}
+	r.GovState = states[0]
+	return nil
+}
+
 // readTraceDB stores the keys of database reads. We use this to check that received node
 // sets contain only the trie nodes necessary to make proofs pass.
 type readTraceDB struct {
@@ -159,6 +159,25 @@ func odrContractCall(ctx context.Context, db ethdb.Database, config *params.Chai
 	return res
 }
 
+func TestOdrGetGovStateLes3(t *testing.T) { testOdr(t, 3, 0, odrGetGovState) }
+
+func odrGetGovState(ctx context.Context, db ethdb.Database, config *params.ChainConfig, bc *core.BlockChain, lc *light.LightChain, bhash common.Hash) []byte {
+	var (
+		govState *types.GovState
+		err      error
+	)
+	if bc != nil {
+		govState, err = bc.GetGovStateByHash(bhash)
+	} else if header := lc.GetHeaderByHash(bhash); header != nil {
+		govState, err = light.GetGovState(ctx, lc.Odr(), header)
+	}
+	if govState == nil || err != nil {
+		return nil
+	}
+	rlp, _ := rlp.EncodeToBytes(govState)
+	return rlp
+}
+
 // testOdr tests odr requests whose validation guaranteed by block headers.
 func testOdr(t *testing.T, protocol int, expFail uint64, fn odrTestFn) {
 	// Assemble the test environment
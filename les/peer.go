@@ -232,6 +232,11 @@ func (p *peer) SendTxStatus(reqID, bv uint64, stats []txStatus) error {
 	return sendResponse(p.rw, TxStatusMsg, reqID, bv, stats)
 }
 
+// SendGovStates sends a batch of governance states, corresponding to the ones requested.
+func (p *peer) SendGovStates(reqID, bv uint64, states []*types.GovState) error {
+	return sendResponse(p.rw, GovStateMsg, reqID, bv, states)
+}
+
 // RequestHeadersByHash fetches a batch of blocks' headers corresponding to the
 // specified header query, based on the hash of an origin block.
 func (p *peer) RequestHeadersByHash(reqID, cost uint64, origin common.Hash, amount int, skip int, reverse bool) error {
@@ -307,6 +312,13 @@ func (p *peer) RequestTxStatus(reqID, cost uint64, txHashes []common.Hash) error
 	return sendRequest(p.rw, GetTxStatusMsg, reqID, cost, txHashes)
 }
 
+// RequestGovStates fetches a batch of governance states at the specified blocks
+// from a remote node.
+func (p *peer) RequestGovStates(reqID, cost uint64, hashes []common.Hash) error {
+	p.Log().Debug("Requesting governance states", "count", len(hashes))
+	return sendRequest(p.rw, GetGovStateMsg, reqID, cost, hashes)
+}
+
 // SendTxStatus sends a batch of transactions to be added to the remote transaction pool.
 func (p *peer) SendTxs(reqID, cost uint64, txs types.Transactions) error {
 	p.Log().Debug("Fetching batch of transactions", "count", len(txs))
@@ -0,0 +1,294 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	dexCore "github.com/dexon-foundation/dexon-consensus/core"
+	coreTypes "github.com/dexon-foundation/dexon-consensus/core/types"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/consensus"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/crypto"
+	"github.com/dexon-foundation/dexon/ethdb"
+	"github.com/dexon-foundation/dexon/log"
+	"github.com/dexon-foundation/dexon/rlp"
+	"github.com/dexon-foundation/dexon/trie"
+)
+
+var (
+	errNoBodies           = errors.New("light client holds no block bodies")
+	errNoStates           = errors.New("light client holds no states")
+	errGovStateMismatch   = errors.New("governance state mismatch")
+	errGovStorageMismatch = errors.New("governance storage mismatch")
+	errGovStateNotReady   = errors.New("governance state not ready")
+	errNotInNotarySet     = errors.New("proposer not in notary set")
+)
+
+// VerifyGovState checks the governance state belongs to the given header: the
+// proof of the governance contract account must be valid against the state
+// root, and the storage must hash to the storage root of the account.
+func VerifyGovState(header *types.Header, s *types.GovState) error {
+	if s.BlockHash != header.Hash() || s.Root != header.Root ||
+		s.Number == nil || s.Number.Cmp(header.Number) != 0 {
+		return errGovStateMismatch
+	}
+
+	proofDB := ethdb.NewMemDatabase()
+	for _, node := range s.Proof {
+		proofDB.Put(crypto.Keccak256(node), node)
+	}
+	key := crypto.Keccak256(vm.GovernanceContractAddress.Bytes())
+	value, _, err := trie.VerifyProof(header.Root, key, proofDB)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return errGovStateMismatch
+	}
+	var account state.Account
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return err
+	}
+
+	t, err := trie.New(common.Hash{}, trie.NewDatabase(ethdb.NewMemDatabase()))
+	if err != nil {
+		return err
+	}
+	for _, entry := range s.Storage {
+		if err := t.TryUpdate(entry[0], entry[1]); err != nil {
+			return err
+		}
+	}
+	if t.Hash() != account.Root {
+		return errGovStorageMismatch
+	}
+	return nil
+}
+
+// governanceStateDB implements core.GovernanceStateDB on top of the
+// governance states retrieved by the light client. Only the governance
+// contract is available in these states.
+type governanceStateDB struct {
+	db          ethdb.Database
+	headRoot    common.Hash
+	headHeight  uint64
+	hasHead     bool
+	height2Root map[uint64]common.Hash
+	mu          sync.RWMutex
+}
+
+func (g *governanceStateDB) State() (*state.StateDB, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	if !g.hasHead {
+		return nil, errGovStateNotReady
+	}
+	return state.New(g.headRoot, state.NewDatabase(g.db))
+}
+
+func (g *governanceStateDB) StateAt(height uint64) (*state.StateDB, error) {
+	g.mu.RLock()
+	root, exists := g.height2Root[height]
+	g.mu.RUnlock()
+
+	if !exists {
+		return nil, fmt.Errorf("governance state not ready, height: %d", height)
+	}
+	return state.New(root, state.NewDatabase(g.db))
+}
+
+func (g *governanceStateDB) has(height uint64) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	_, exists := g.height2Root[height]
+	return exists
+}
+
+// storeState writes the account proof and the storage trie of a verified
+// governance state to the database.
+func (g *governanceStateDB) storeState(s *types.GovState) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, node := range s.Proof {
+		g.db.Put(crypto.Keccak256(node), node)
+	}
+	triedb := trie.NewDatabase(g.db)
+	t, err := trie.New(common.Hash{}, triedb)
+	if err != nil {
+		return err
+	}
+	for _, kv := range s.Storage {
+		if err := t.TryUpdate(kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	root, err := t.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if err := triedb.Commit(root, false); err != nil {
+		return err
+	}
+
+	number := s.Number.Uint64()
+	g.height2Root[number] = s.Root
+	if !g.hasHead || number > g.headHeight {
+		log.Debug("Governance head root changed", "number", number)
+		g.headRoot = s.Root
+		g.headHeight = number
+		g.hasHead = true
+	}
+	return nil
+}
+
+// governance provides the DEXON governance to the light chain, backed by the
+// governance states retrieved on demand.
+type governance struct {
+	*core.Governance
+	db             *governanceStateDB
+	verifierCache  *dexCore.TSigVerifierCache
+	notarySetCache *dexCore.NodeSetCache
+}
+
+func newGovernance(db ethdb.Database) *governance {
+	stateDB := &governanceStateDB{
+		db:          db,
+		height2Root: make(map[uint64]common.Hash),
+	}
+	g := &governance{
+		Governance: core.NewGovernance(stateDB),
+		db:         stateDB,
+	}
+	g.verifierCache = dexCore.NewTSigVerifierCache(g.Governance, 5)
+	g.notarySetCache = dexCore.NewNodeSetCache(g.Governance)
+	return g
+}
+
+// retrieve makes the governance state at the given trusted header available,
+// fetching it from the network if it is not in the database. The state is
+// proven against the root of the header before it is used.
+func (g *governance) retrieve(ctx context.Context, odr OdrBackend, header *types.Header) error {
+	if g.db.has(header.Number.Uint64()) {
+		return nil
+	}
+	s, err := GetGovState(ctx, odr, header)
+	if err != nil {
+		return err
+	}
+	if err := VerifyGovState(header, s); err != nil {
+		return err
+	}
+	return g.db.storeState(s)
+}
+
+// prepare retrieves the governance states needed to verify the given
+// contiguous headers following the verified parent: the one at the parent,
+// recording the height each round begins at, and the ones at the beginning of
+// the rounds the configuration, CRS and DKG results of the headers are read
+// from. All of them are at verified headers, so the headers can only be in the
+// round of the parent or the one after it.
+func (g *governance) prepare(ctx context.Context, odr OdrBackend, parent *types.Header, chain []*types.Header) error {
+	if err := g.retrieve(ctx, odr, parent); err != nil {
+		return err
+	}
+
+	from := uint64(0)
+	if chain[0].Round > dexCore.ConfigRoundShift {
+		from = chain[0].Round - dexCore.ConfigRoundShift
+	}
+	rounds := []uint64{0}
+	for round := from; round <= parent.Round; round++ {
+		if round != 0 {
+			rounds = append(rounds, round)
+		}
+	}
+
+	for _, round := range rounds {
+		height := g.GetRoundHeight(round)
+		if round != 0 && height == 0 {
+			return fmt.Errorf("height of round %d unknown", round)
+		}
+		if g.db.has(height) {
+			continue
+		}
+		header, err := GetHeaderByNumber(ctx, odr, height)
+		if err != nil {
+			return err
+		}
+		if err := g.retrieve(ctx, odr, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyProposer checks the proposer of the header is in the notary set of
+// its round.
+func (g *governance) verifyProposer(header *types.Header) error {
+	var coreBlock coreTypes.Block
+	if err := rlp.DecodeBytes(header.DexconMeta, &coreBlock); err != nil {
+		return err
+	}
+	if coreBlock.IsEmpty() {
+		return nil
+	}
+	notarySet, err := g.notarySetCache.GetNotarySet(header.Round)
+	if err != nil {
+		return err
+	}
+	if _, exists := notarySet[coreBlock.ProposerID]; !exists {
+		return errNotInNotarySet
+	}
+	return nil
+}
+
+// witnessValidator validates the witness of dexon headers against the light
+// chain. Light clients hold neither bodies nor states, so the other
+// validations always fail.
+type witnessValidator struct {
+	lc *LightChain
+}
+
+func (v *witnessValidator) ValidateBody(block *types.Block) error {
+	return errNoBodies
+}
+
+func (v *witnessValidator) ValidateState(block, parent *types.Block, state *state.StateDB,
+	receipts types.Receipts, usedGas uint64) error {
+	return errNoStates
+}
+
+func (v *witnessValidator) ValidateWitnessData(height uint64, hash common.Hash) error {
+	header := v.lc.GetHeaderByNumber(height)
+	if header == nil || header.Hash() != hash {
+		return consensus.ErrWitnessMismatch
+	}
+	return nil
+}
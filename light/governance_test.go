@@ -0,0 +1,132 @@
+// Copyright 2019 The dexon-consensus Authors
+// This file is part of the dexon-consensus library.
+//
+// The dexon-consensus library is free software: you can redistribute it
+// and/or modify it under the terms of the GNU Lesser General Public License as
+// published by the Free Software Foundation, either version 3 of the License,
+// or (at your option) any later version.
+//
+// The dexon-consensus library is distributed in the hope that it will be
+// useful, but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the GNU Lesser
+// General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the dexon-consensus library. If not, see
+// <http://www.gnu.org/licenses/>.
+
+package light
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/dexon-foundation/dexon/common"
+	"github.com/dexon-foundation/dexon/core"
+	"github.com/dexon-foundation/dexon/core/rawdb"
+	"github.com/dexon-foundation/dexon/core/state"
+	"github.com/dexon-foundation/dexon/core/types"
+	"github.com/dexon-foundation/dexon/core/vm"
+	"github.com/dexon-foundation/dexon/ethdb"
+)
+
+func TestVerifyGovState(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	genesis := core.DefaultTestnetGenesisBlock().MustCommit(db)
+	statedb, err := state.New(genesis.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to get genesis state: %v", err)
+	}
+	header := genesis.Header()
+
+	govState, err := state.GetGovState(statedb, header, vm.GovernanceContractAddress)
+	if err != nil {
+		t.Fatalf("failed to get governance state: %v", err)
+	}
+	if err := VerifyGovState(header, govState); err != nil {
+		t.Fatalf("failed to verify governance state: %v", err)
+	}
+
+	// The state of another block is rejected.
+	other := types.CopyHeader(header)
+	other.Number = big.NewInt(1)
+	if err := VerifyGovState(other, govState); err != errGovStateMismatch {
+		t.Errorf("header mismatch error mismatch: have %v, want %v", err, errGovStateMismatch)
+	}
+
+	// Tampered storage is rejected.
+	tampered := *govState
+	tampered.Storage = append([][2][]byte{}, govState.Storage...)
+	tampered.Storage[0] = [2][]byte{tampered.Storage[0][0], common.Hex2Bytes("01")}
+	if err := VerifyGovState(header, &tampered); err != errGovStorageMismatch {
+		t.Errorf("storage mismatch error mismatch: have %v, want %v", err, errGovStorageMismatch)
+	}
+
+	// A proof not leading to the governance contract is rejected.
+	tampered = *govState
+	tampered.Proof = govState.Proof[:1]
+	if err := VerifyGovState(header, &tampered); err == nil {
+		t.Errorf("incomplete proof verified")
+	}
+
+	// A verified state can be stored and read back.
+	gov := newGovernance(ethdb.NewMemDatabase())
+	if err := gov.db.storeState(govState); err != nil {
+		t.Fatalf("failed to store governance state: %v", err)
+	}
+	if height := gov.GetRoundHeight(0); height != 0 {
+		t.Errorf("round height mismatch: have %d, want 0", height)
+	}
+	if gov.Configuration(0).RoundLength == 0 {
+		t.Errorf("configuration not available")
+	}
+}
+
+func TestRetrieveGovState(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	genesis := core.DefaultTestnetGenesisBlock().MustCommit(db)
+	statedb, err := state.New(genesis.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatalf("failed to get genesis state: %v", err)
+	}
+	header := genesis.Header()
+	govState, err := state.GetGovState(statedb, header, vm.GovernanceContractAddress)
+	if err != nil {
+		t.Fatalf("failed to get governance state: %v", err)
+	}
+
+	// A state in the database not proven by the trusted header is rejected.
+	tampered := *govState
+	tampered.Storage = append([][2][]byte{}, govState.Storage...)
+	tampered.Storage[0] = [2][]byte{tampered.Storage[0][0], common.Hex2Bytes("01")}
+	ldb := ethdb.NewMemDatabase()
+	rawdb.WriteGovState(ldb, header.Hash(), &tampered)
+	odr := &testOdr{sdb: db, ldb: ldb, disable: true}
+	gov := newGovernance(ethdb.NewMemDatabase())
+	if err := gov.retrieve(context.Background(), odr, header); err != errGovStorageMismatch {
+		t.Fatalf("tampered state error mismatch: have %v, want %v", err, errGovStorageMismatch)
+	}
+	if gov.db.has(0) {
+		t.Fatalf("tampered state stored")
+	}
+
+	rawdb.WriteGovState(ldb, header.Hash(), govState)
+	if err := gov.retrieve(context.Background(), odr, header); err != nil {
+		t.Fatalf("failed to retrieve governance state: %v", err)
+	}
+	if !gov.db.has(0) {
+		t.Fatalf("governance state not stored")
+	}
+}
+
+func TestWitnessValidator(t *testing.T) {
+	var v core.Validator = &witnessValidator{}
+	block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(1)})
+	if err := v.ValidateBody(block); err != errNoBodies {
+		t.Errorf("body validation error mismatch: have %v, want %v", err, errNoBodies)
+	}
+	if err := v.ValidateState(block, block, nil, nil, 0); err != errNoStates {
+		t.Errorf("state validation error mismatch: have %v, want %v", err, errNoStates)
+	}
+}
@@ -19,6 +19,7 @@ package light
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"sync"
 	"sync/atomic"
@@ -42,6 +43,8 @@ import (
 var (
 	bodyCacheLimit  = 256
 	blockCacheLimit = 256
+
+	govStateRetrievalTimeout = 30 * time.Second
 )
 
 // LightChain represents a canonical chain that by default only handles block
@@ -72,6 +75,7 @@ type LightChain struct {
 	wg            sync.WaitGroup
 
 	engine consensus.Engine
+	gov    *governance // governance of dexon chains, nil otherwise
 }
 
 // NewLightChain returns a fully initialised light chain using information
@@ -92,6 +96,9 @@ func NewLightChain(odr OdrBackend, config *params.ChainConfig, engine consensus.
 		blockCache:    blockCache,
 		engine:        engine,
 	}
+	if config.Dexcon != nil {
+		bc.gov = newGovernance(bc.chainDb)
+	}
 	var err error
 	bc.hc, err = core.NewHeaderChain(odr.Database(), config, bc.engine, bc.getProcInterrupt)
 	if err != nil {
@@ -355,9 +362,35 @@ func (self *LightChain) postChainEvents(events []interface{}) {
 //
 // In the case of a light chain, InsertHeaderChain also creates and posts light
 // chain events when necessary.
+//
+// Headers of dexon chains are verified against the notary set and the DKG
+// group key of their rounds, retrieving the governance states needed. The
+// states are only trusted at verified headers, so the headers are verified and
+// inserted round by round.
 func (self *LightChain) InsertHeaderChain(chain []*types.Header, checkFreq int) (int, error) {
+	if self.gov == nil {
+		return self.insertHeaderChain(chain, checkFreq)
+	}
+	for start := 0; start < len(chain); {
+		end := start + 1
+		for end < len(chain) && chain[end].Round == chain[start].Round {
+			end++
+		}
+		if i, err := self.insertHeaderChain(chain[start:end], checkFreq); err != nil {
+			return start + i, err
+		}
+		start = end
+	}
+	return 0, nil
+}
+
+func (self *LightChain) insertHeaderChain(chain []*types.Header, checkFreq int) (int, error) {
 	start := time.Now()
-	if i, err := self.hc.ValidateHeaderChain(chain, checkFreq); err != nil {
+	if self.gov != nil {
+		if i, err := self.validateDexonHeaderChain(chain); err != nil {
+			return i, err
+		}
+	} else if i, err := self.hc.ValidateHeaderChain(chain, checkFreq); err != nil {
 		return i, err
 	}
 
@@ -394,6 +427,55 @@ func (self *LightChain) InsertHeaderChain(chain []*types.Header, checkFreq int)
 	return i, err
 }
 
+// validateDexonHeaderChain verifies a batch of dexon headers, including the
+// randomness signed by the DKG group and the proposer being a notary. The
+// governance states are anchored to the verified parent of the batch, which
+// covers the headers up to the round following the parent's.
+func (self *LightChain) validateDexonHeaderChain(chain []*types.Header) (int, error) {
+	if len(chain) == 0 {
+		return 0, nil
+	}
+	parent := self.GetHeader(chain[0].ParentHash, chain[0].Number.Uint64()-1)
+	if parent == nil {
+		return 0, consensus.ErrUnknownAncestor
+	}
+	for i, header := range chain {
+		if header.Round > parent.Round+1 {
+			return i, fmt.Errorf("round %d not verifiable from round %d", header.Round, parent.Round)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), govStateRetrievalTimeout)
+	defer cancel()
+	if err := self.gov.prepare(ctx, self.odr, parent, chain); err != nil {
+		return 0, err
+	}
+
+	headers := make([]*types.HeaderWithGovState, len(chain))
+	for i, header := range chain {
+		headers[i] = &types.HeaderWithGovState{Header: header}
+	}
+	validator := &witnessValidator{lc: self}
+	if i, err := self.hc.ValidateDexonHeaderChain(headers, self.gov, self.gov.verifierCache, validator); err != nil {
+		return i, err
+	}
+	for i, header := range chain {
+		if err := self.gov.verifyProposer(header); err != nil {
+			return i, err
+		}
+	}
+	return 0, nil
+}
+
+// GetGovStateByHash retrieves the governance state at a block from the
+// database. Only the states needed to verify headers are available.
+func (self *LightChain) GetGovStateByHash(hash common.Hash) (*types.GovState, error) {
+	govState := rawdb.ReadGovState(self.chainDb, hash)
+	if govState == nil {
+		return nil, errGovStateNotReady
+	}
+	return govState, nil
+}
+
 // CurrentHeader retrieves the current head header of the canonical chain. The
 // header is retrieved from the HeaderChain's internal cache.
 func (self *LightChain) CurrentHeader() *types.Header {
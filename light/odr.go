@@ -134,6 +134,19 @@ func (req *ReceiptsRequest) StoreResult(db ethdb.Database) {
 	rawdb.WriteReceipts(db, req.Hash, req.Number, req.Receipts)
 }
 
+// GovStateRequest is the ODR request type for retrieving the governance state
+// at a block
+type GovStateRequest struct {
+	OdrRequest
+	Header   *types.Header
+	GovState *types.GovState
+}
+
+// StoreResult stores the retrieved data in local database
+func (req *GovStateRequest) StoreResult(db ethdb.Database) {
+	rawdb.WriteGovState(db, req.Header.Hash(), req.GovState)
+}
+
 // ChtRequest is the ODR request type for state/storage trie entries
 type ChtRequest struct {
 	OdrRequest
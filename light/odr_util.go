@@ -124,6 +124,20 @@ func GetBlock(ctx context.Context, odr OdrBackend, hash common.Hash, number uint
 	return types.NewBlockWithHeader(header).WithBody(body.Transactions, body.Uncles), nil
 }
 
+// GetGovState retrieves the governance state at the block of the given header
+// from the database or network. States retrieved from the network have been
+// verified against the state root of the header.
+func GetGovState(ctx context.Context, odr OdrBackend, header *types.Header) (*types.GovState, error) {
+	if govState := rawdb.ReadGovState(odr.Database(), header.Hash()); govState != nil {
+		return govState, nil
+	}
+	r := &GovStateRequest{Header: header}
+	if err := odr.Retrieve(ctx, r); err != nil {
+		return nil, err
+	}
+	return r.GovState, nil
+}
+
 // GetBlockReceipts retrieves the receipts generated by the transactions included
 // in a block given by its hash.
 func GetBlockReceipts(ctx context.Context, odr OdrBackend, hash common.Hash, number uint64) (types.Receipts, error) {
@@ -232,18 +232,18 @@ var (
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(EthashConfig), nil, nil, nil}
+	AllEthashProtocolChanges = &ChainConfig{big.NewInt(1337), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), new(EthashConfig), nil, nil, nil}
 
 	// AllCliqueProtocolChanges contains every protocol change (EIPs) introduced
 	// and accepted by the Ethereum core developers into the Clique consensus.
 	//
 	// This configuration is intentionally not using keyed fields to force anyone
 	// adding flags to the config to also have to set these fields.
-	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, nil}
+	AllCliqueProtocolChanges = &ChainConfig{big.NewInt(1337), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, &CliqueConfig{Period: 0, Epoch: 30000}, nil, nil}
 
-	AllDexconProtocolChanges = &ChainConfig{big.NewInt(1337), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, nil, new(DexconConfig), new(RecoveryConfig)}
+	AllDexconProtocolChanges = &ChainConfig{big.NewInt(1337), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, nil, new(DexconConfig), new(RecoveryConfig)}
 
-	TestChainConfig = &ChainConfig{big.NewInt(1), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, new(EthashConfig), nil, nil, nil}
+	TestChainConfig = &ChainConfig{big.NewInt(1), 0, big.NewInt(0), nil, false, big.NewInt(0), common.Hash{}, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), nil, big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0), new(EthashConfig), nil, nil, nil}
 	TestRules       = TestChainConfig.Rules(new(big.Int))
 
 	// Ethereum MainnetChainConfig is the chain parameters to run a node on the main network.
@@ -317,6 +317,12 @@ type ChainConfig struct {
 	PetersburgBlock     *big.Int `json:"petersburgBlock,omitempty"`     // Petersburg switch block (nil = same as Constantinople)
 	EWASMBlock          *big.Int `json:"ewasmBlock,omitempty"`          // EWASM switch block (nil = no fork, 0 = already activated)
 
+	// DEXON governance forks
+	DelegationBlock         *big.Int `json:"delegationBlock,omitempty"`         // Delegated staking switch block (nil = no fork, 0 = already activated)
+	RewardDistributionBlock *big.Int `json:"rewardDistributionBlock,omitempty"` // Block reward distribution switch block (nil = no fork, 0 = already activated)
+	LivenessPenaltyBlock    *big.Int `json:"livenessPenaltyBlock,omitempty"`    // Graduated liveness penalty switch block (nil = no fork, 0 = already activated)
+	NodeKeySeparationBlock  *big.Int `json:"nodeKeySeparationBlock,omitempty"`  // Node key separation switch block (nil = no fork, 0 = already activated)
+
 	// Various consensus engines
 	Ethash *EthashConfig `json:"ethash,omitempty"`
 	Clique *CliqueConfig `json:"clique,omitempty"`
@@ -367,6 +373,10 @@ type DexconConfig struct {
 	RoundLength       uint64         `json:"roundLength"`
 	MinBlockInterval  uint64         `json:"minBlockInterval"`
 	FineValues        []*big.Int     `json:"fineValues"`
+
+	// Graduated liveness penalty, zero values fall back to the defaults.
+	LivenessWindow        uint64 `json:"livenessWindow"`
+	LivenessJailThreshold uint64 `json:"livenessJailThreshold"`
 }
 
 type dexconConfigSpecMarshaling struct {
@@ -379,7 +389,7 @@ type dexconConfigSpecMarshaling struct {
 
 // String implements the stringer interface, returning the consensus engine details.
 func (d *DexconConfig) String() string {
-	return fmt.Sprintf("{GenesisCRSText: %v Owner: %v MinStake: %v LockupPeriod: %v MiningVelocity: %v NextHalvingSupply: %v LastHalvedAmount: %v MinGasPrice: %v BlockGasLimit: %v LambdaBA: %v LambdaDKG: %v NotaryParamAlpha: %v NotaryParamBeta: %v RoundLength: %v MinBlockInterval: %v FineValues: %v}",
+	return fmt.Sprintf("{GenesisCRSText: %v Owner: %v MinStake: %v LockupPeriod: %v MiningVelocity: %v NextHalvingSupply: %v LastHalvedAmount: %v MinGasPrice: %v BlockGasLimit: %v LambdaBA: %v LambdaDKG: %v NotaryParamAlpha: %v NotaryParamBeta: %v RoundLength: %v MinBlockInterval: %v FineValues: %v LivenessWindow: %v LivenessJailThreshold: %v}",
 		d.GenesisCRSText,
 		d.Owner,
 		d.MinStake,
@@ -396,6 +406,8 @@ func (d *DexconConfig) String() string {
 		d.RoundLength,
 		d.MinBlockInterval,
 		d.FineValues,
+		d.LivenessWindow,
+		d.LivenessJailThreshold,
 	)
 }
 
@@ -416,7 +428,7 @@ func (c *ChainConfig) String() string {
 	default:
 		engine = "unknown"
 	}
-	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v  ConstantinopleFix: %v Engine: %v}",
+	return fmt.Sprintf("{ChainID: %v Homestead: %v DAO: %v DAOSupport: %v EIP150: %v EIP155: %v EIP158: %v Byzantium: %v Constantinople: %v  ConstantinopleFix: %v Delegation: %v RewardDistribution: %v LivenessPenalty: %v NodeKeySeparation: %v Engine: %v}",
 		c.ChainID,
 		c.HomesteadBlock,
 		c.DAOForkBlock,
@@ -427,6 +439,10 @@ func (c *ChainConfig) String() string {
 		c.ByzantiumBlock,
 		c.ConstantinopleBlock,
 		c.PetersburgBlock,
+		c.DelegationBlock,
+		c.RewardDistributionBlock,
+		c.LivenessPenaltyBlock,
+		c.NodeKeySeparationBlock,
 		engine,
 	)
 }
@@ -478,6 +494,30 @@ func (c *ChainConfig) IsEWASM(num *big.Int) bool {
 	return isForked(c.EWASMBlock, num)
 }
 
+// IsDelegation returns whether num is either equal to the delegated staking
+// fork block or greater.
+func (c *ChainConfig) IsDelegation(num *big.Int) bool {
+	return isForked(c.DelegationBlock, num)
+}
+
+// IsRewardDistribution returns whether num is either equal to the block
+// reward distribution fork block or greater.
+func (c *ChainConfig) IsRewardDistribution(num *big.Int) bool {
+	return isForked(c.RewardDistributionBlock, num)
+}
+
+// IsLivenessPenalty returns whether num is either equal to the graduated
+// liveness penalty fork block or greater.
+func (c *ChainConfig) IsLivenessPenalty(num *big.Int) bool {
+	return isForked(c.LivenessPenaltyBlock, num)
+}
+
+// IsNodeKeySeparation returns whether num is either equal to the node key
+// separation fork block or greater.
+func (c *ChainConfig) IsNodeKeySeparation(num *big.Int) bool {
+	return isForked(c.NodeKeySeparationBlock, num)
+}
+
 // GasTable returns the gas table corresponding to the current phase (homestead or homestead reprice).
 //
 // The returned GasTable's fields shouldn't, under any circumstances, be changed.
@@ -549,6 +589,18 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, head *big.Int) *Confi
 	if isForkIncompatible(c.EWASMBlock, newcfg.EWASMBlock, head) {
 		return newCompatError("ewasm fork block", c.EWASMBlock, newcfg.EWASMBlock)
 	}
+	if isForkIncompatible(c.DelegationBlock, newcfg.DelegationBlock, head) {
+		return newCompatError("Delegation fork block", c.DelegationBlock, newcfg.DelegationBlock)
+	}
+	if isForkIncompatible(c.RewardDistributionBlock, newcfg.RewardDistributionBlock, head) {
+		return newCompatError("Reward distribution fork block", c.RewardDistributionBlock, newcfg.RewardDistributionBlock)
+	}
+	if isForkIncompatible(c.LivenessPenaltyBlock, newcfg.LivenessPenaltyBlock, head) {
+		return newCompatError("Liveness penalty fork block", c.LivenessPenaltyBlock, newcfg.LivenessPenaltyBlock)
+	}
+	if isForkIncompatible(c.NodeKeySeparationBlock, newcfg.NodeKeySeparationBlock, head) {
+		return newCompatError("Node key separation fork block", c.NodeKeySeparationBlock, newcfg.NodeKeySeparationBlock)
+	}
 	return nil
 }
 
@@ -15,24 +15,26 @@ var _ = (*dexconConfigSpecMarshaling)(nil)
 // MarshalJSON marshals as JSON.
 func (d DexconConfig) MarshalJSON() ([]byte, error) {
 	type DexconConfig struct {
-		GenesisCRSText    string                  `json:"genesisCRSText"`
-		Owner             common.Address          `json:"owner"`
-		MinStake          *math.HexOrDecimal256   `json:"minStake"`
-		LockupPeriod      uint64                  `json:"lockupPeriod"`
-		MiningVelocity    float32                 `json:"miningVelocity"`
-		NextHalvingSupply *math.HexOrDecimal256   `json:"nextHalvingSupply"`
-		LastHalvedAmount  *math.HexOrDecimal256   `json:"lastHalvedAmount"`
-		MinGasPrice       *math.HexOrDecimal256   `json:"minGasPrice"`
-		BlockGasLimit     uint64                  `json:"blockGasLimit"`
-		LambdaBA          uint64                  `json:"lambdaBA"`
-		LambdaDKG         uint64                  `json:"lambdaDKG"`
-		NotarySetSize     uint32                  `json:"notarySetSize"`
-		NotaryParamAlpha  float32                 `json:"notaryParamAlpha"`
-		NotaryParamBeta   float32                 `json:"notaryParamBeta"`
-		DKGSetSize        uint32                  `json:"dkgSetSize"`
-		RoundLength       uint64                  `json:"roundLength"`
-		MinBlockInterval  uint64                  `json:"minBlockInterval"`
-		FineValues        []*math.HexOrDecimal256 `json:"fineValues"`
+		GenesisCRSText        string                  `json:"genesisCRSText"`
+		Owner                 common.Address          `json:"owner"`
+		MinStake              *math.HexOrDecimal256   `json:"minStake"`
+		LockupPeriod          uint64                  `json:"lockupPeriod"`
+		MiningVelocity        float32                 `json:"miningVelocity"`
+		NextHalvingSupply     *math.HexOrDecimal256   `json:"nextHalvingSupply"`
+		LastHalvedAmount      *math.HexOrDecimal256   `json:"lastHalvedAmount"`
+		MinGasPrice           *math.HexOrDecimal256   `json:"minGasPrice"`
+		BlockGasLimit         uint64                  `json:"blockGasLimit"`
+		LambdaBA              uint64                  `json:"lambdaBA"`
+		LambdaDKG             uint64                  `json:"lambdaDKG"`
+		NotarySetSize         uint32                  `json:"notarySetSize"`
+		NotaryParamAlpha      float32                 `json:"notaryParamAlpha"`
+		NotaryParamBeta       float32                 `json:"notaryParamBeta"`
+		DKGSetSize            uint32                  `json:"dkgSetSize"`
+		RoundLength           uint64                  `json:"roundLength"`
+		MinBlockInterval      uint64                  `json:"minBlockInterval"`
+		FineValues            []*math.HexOrDecimal256 `json:"fineValues"`
+		LivenessWindow        uint64                  `json:"livenessWindow"`
+		LivenessJailThreshold uint64                  `json:"livenessJailThreshold"`
 	}
 	var enc DexconConfig
 	enc.GenesisCRSText = d.GenesisCRSText
@@ -58,30 +60,34 @@ func (d DexconConfig) MarshalJSON() ([]byte, error) {
 			enc.FineValues[k] = (*math.HexOrDecimal256)(v)
 		}
 	}
+	enc.LivenessWindow = d.LivenessWindow
+	enc.LivenessJailThreshold = d.LivenessJailThreshold
 	return json.Marshal(&enc)
 }
 
 // UnmarshalJSON unmarshals from JSON.
 func (d *DexconConfig) UnmarshalJSON(input []byte) error {
 	type DexconConfig struct {
-		GenesisCRSText    *string                 `json:"genesisCRSText"`
-		Owner             *common.Address         `json:"owner"`
-		MinStake          *math.HexOrDecimal256   `json:"minStake"`
-		LockupPeriod      *uint64                 `json:"lockupPeriod"`
-		MiningVelocity    *float32                `json:"miningVelocity"`
-		NextHalvingSupply *math.HexOrDecimal256   `json:"nextHalvingSupply"`
-		LastHalvedAmount  *math.HexOrDecimal256   `json:"lastHalvedAmount"`
-		MinGasPrice       *math.HexOrDecimal256   `json:"minGasPrice"`
-		BlockGasLimit     *uint64                 `json:"blockGasLimit"`
-		LambdaBA          *uint64                 `json:"lambdaBA"`
-		LambdaDKG         *uint64                 `json:"lambdaDKG"`
-		NotarySetSize     *uint32                 `json:"notarySetSize"`
-		NotaryParamAlpha  *float32                `json:"notaryParamAlpha"`
-		NotaryParamBeta   *float32                `json:"notaryParamBeta"`
-		DKGSetSize        *uint32                 `json:"dkgSetSize"`
-		RoundLength       *uint64                 `json:"roundLength"`
-		MinBlockInterval  *uint64                 `json:"minBlockInterval"`
-		FineValues        []*math.HexOrDecimal256 `json:"fineValues"`
+		GenesisCRSText        *string                 `json:"genesisCRSText"`
+		Owner                 *common.Address         `json:"owner"`
+		MinStake              *math.HexOrDecimal256   `json:"minStake"`
+		LockupPeriod          *uint64                 `json:"lockupPeriod"`
+		MiningVelocity        *float32                `json:"miningVelocity"`
+		NextHalvingSupply     *math.HexOrDecimal256   `json:"nextHalvingSupply"`
+		LastHalvedAmount      *math.HexOrDecimal256   `json:"lastHalvedAmount"`
+		MinGasPrice           *math.HexOrDecimal256   `json:"minGasPrice"`
+		BlockGasLimit         *uint64                 `json:"blockGasLimit"`
+		LambdaBA              *uint64                 `json:"lambdaBA"`
+		LambdaDKG             *uint64                 `json:"lambdaDKG"`
+		NotarySetSize         *uint32                 `json:"notarySetSize"`
+		NotaryParamAlpha      *float32                `json:"notaryParamAlpha"`
+		NotaryParamBeta       *float32                `json:"notaryParamBeta"`
+		DKGSetSize            *uint32                 `json:"dkgSetSize"`
+		RoundLength           *uint64                 `json:"roundLength"`
+		MinBlockInterval      *uint64                 `json:"minBlockInterval"`
+		FineValues            []*math.HexOrDecimal256 `json:"fineValues"`
+		LivenessWindow        *uint64                 `json:"livenessWindow"`
+		LivenessJailThreshold *uint64                 `json:"livenessJailThreshold"`
 	}
 	var dec DexconConfig
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -144,5 +150,11 @@ func (d *DexconConfig) UnmarshalJSON(input []byte) error {
 			d.FineValues[k] = (*big.Int)(v)
 		}
 	}
+	if dec.LivenessWindow != nil {
+		d.LivenessWindow = *dec.LivenessWindow
+	}
+	if dec.LivenessJailThreshold != nil {
+		d.LivenessJailThreshold = *dec.LivenessJailThreshold
+	}
 	return nil
 }
@@ -124,8 +124,31 @@ const (
 	EarliestBlockNumber = BlockNumber(0)
 )
 
+// roundStartBlockNumberBase is the alias of the first block of round 0, the
+// aliases of later rounds count down from it.
+const roundStartBlockNumberBase = BlockNumber(-3)
+
+// roundStartPrefix prefixes the round in the JSON alias of the first block of
+// a round, e.g. "round:0x1a".
+const roundStartPrefix = "round:"
+
+// RoundStartBlockNumber returns the alias of the first block of the round.
+func RoundStartBlockNumber(round uint64) BlockNumber {
+	return roundStartBlockNumberBase - BlockNumber(round)
+}
+
+// RoundStart returns the round if the block number is the alias of the first
+// block of a round.
+func (bn BlockNumber) RoundStart() (uint64, bool) {
+	if bn > roundStartBlockNumberBase {
+		return 0, false
+	}
+	return uint64(roundStartBlockNumberBase - bn), true
+}
+
 // UnmarshalJSON parses the given JSON fragment into a BlockNumber. It supports:
 // - "latest", "earliest" or "pending" as string arguments
+// - "round:" followed by a hex round number for the first block of the round
 // - the block number
 // Returned errors:
 // - an invalid block number error when the given argument isn't a known strings
@@ -148,6 +171,18 @@ func (bn *BlockNumber) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
+	if strings.HasPrefix(input, roundStartPrefix) {
+		round, err := hexutil.DecodeUint64(input[len(roundStartPrefix):])
+		if err != nil {
+			return err
+		}
+		if round > uint64(roundStartBlockNumberBase-math.MinInt64) {
+			return fmt.Errorf("Round too high")
+		}
+		*bn = RoundStartBlockNumber(round)
+		return nil
+	}
+
 	blckNum, err := hexutil.DecodeUint64(input)
 	if err != nil {
 		return err
@@ -46,6 +46,12 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		14: {`someString`, true, BlockNumber(0)},
 		15: {`""`, true, BlockNumber(0)},
 		16: {``, true, BlockNumber(0)},
+		17: {`"round:0x0"`, false, RoundStartBlockNumber(0)},
+		18: {`"round:0x1a"`, false, RoundStartBlockNumber(26)},
+		19: {`"round:"`, true, BlockNumber(0)},
+		20: {`"round:1"`, true, BlockNumber(0)},
+		21: {`"round:0x7ffffffffffffffd"`, false, RoundStartBlockNumber(math.MaxInt64 - 2)},
+		22: {`"round:0x7ffffffffffffffe"`, true, BlockNumber(0)},
 	}
 
 	for i, test := range tests {
@@ -64,3 +70,17 @@ func TestBlockNumberJSONUnmarshal(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockNumberRoundStart(t *testing.T) {
+	for _, bn := range []BlockNumber{PendingBlockNumber, LatestBlockNumber, EarliestBlockNumber, 1} {
+		if _, ok := bn.RoundStart(); ok {
+			t.Errorf("block number %d is taken as round start", bn)
+		}
+	}
+	for _, round := range []uint64{0, 1, math.MaxInt64 - 2} {
+		have, ok := RoundStartBlockNumber(round).RoundStart()
+		if !ok || have != round {
+			t.Errorf("round mismatch: have %d, want %d", have, round)
+		}
+	}
+}